@@ -1,47 +1,186 @@
 package handler
 
 import (
+	"time"
+
+	"github.com/micro/go-micro/v2"
+	client "github.com/micro/go-micro/v2/client"
 	server "github.com/micro/go-micro/v2/server"
+	"github.com/micro/go-micro/v2/util/log"
+	uuid "github.com/satori/go.uuid"
 
+	"github.com/lecex/pay/config"
 	db "github.com/lecex/pay/providers/database"
 
+	appPB "github.com/lecex/pay/proto/app"
+	billPB "github.com/lecex/pay/proto/bill"
 	configPB "github.com/lecex/pay/proto/config"
+	notifyPB "github.com/lecex/pay/proto/notify"
 	orderPB "github.com/lecex/pay/proto/order"
+	repairPB "github.com/lecex/pay/proto/repair"
+	sharingPB "github.com/lecex/pay/proto/sharing"
 	tradePB "github.com/lecex/pay/proto/trade"
+	webhookPB "github.com/lecex/pay/proto/webhook"
 
+	"github.com/lecex/pay/service/gateway"
 	"github.com/lecex/pay/service/repository"
 	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/service/webhook"
 )
 
+// configTopic 商户配置变更事件主题
+var configTopic = config.Conf.Name + ".config"
+
+// configCacheTTL 商户配置缓存有效期 其它实例未收到配置变更事件时最多使用该时长的旧配置
+var configCacheTTL = time.Duration(envInt("PAY_CONFIG_CACHE_TTL", 60)) * time.Second
+
 // Handler 注册方法
 type Handler struct {
 	Server server.Server
+	Client client.Client
+
+	stop   chan struct{} // 关闭后后台任务退出
+	poller *Poller       // 付款码支付后台轮询 全部支付服务共用
+	cache  *trade.Cache  // 商户配置和支付通道缓存 全部支付服务共用
 }
 
 // Register 注册
 func (srv *Handler) Register() {
-	configPB.RegisterConfigsHandler(srv.Server, srv.Config()) // 配置服务实现
-	orderPB.RegisterOrdersHandler(srv.Server, srv.Order())    // 订单服务实现
-	tradePB.RegisterTradesHandler(srv.Server, srv.Trade())    // 支付服务实现
+	configPB.RegisterConfigsHandler(srv.Server, srv.Config())    // 配置服务实现
+	orderPB.RegisterOrdersHandler(srv.Server, srv.Order())       // 订单服务实现
+	tradePB.RegisterTradesHandler(srv.Server, srv.Gateway())     // 支付服务实现 开放接口网关校验签名
+	notifyPB.RegisterNotifyHandler(srv.Server, srv.Notify())     // 异步通知服务实现
+	notifyPB.RegisterCashierHandler(srv.Server, srv.Cashier())   // 门店收银台服务实现
+	webhookPB.RegisterWebhooksHandler(srv.Server, srv.Webhook()) // 商户通知服务实现
+	billPB.RegisterBillsHandler(srv.Server, srv.Bill())          // 对账服务实现
+	sharingPB.RegisterSharingsHandler(srv.Server, srv.Sharing()) // 分账服务实现
+	repairPB.RegisterRepairsHandler(srv.Server, srv.Repair())    // 订单补偿服务实现
+	appPB.RegisterAppsHandler(srv.Server, srv.App())             // 开发者应用服务实现
+	trade.SetRecorder(srv.Order().Record)                        // 通道请求记录
+	// 商户配置变更清除缓存 不指定队列 每个实例都收到事件
+	if err := micro.RegisterSubscriber(configTopic, srv.Server, srv.Config().Changed); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Run 启动后台任务
+func (srv *Handler) Run() {
+	srv.stop = make(chan struct{})
+	go srv.Webhook().Worker.Run(srv.stop) // 商户通知推送
+	go srv.Bill().Daily(srv.stop)         // 每日对账
+	go srv.Sharing().Run(srv.stop)        // 订单分账
+	go srv.Repair().Run(srv.stop)         // 订单补偿
+	go srv.Sync().Run(srv.stop)           // 待付款和待退款订单同步
+	go srv.Gateway().Run(srv.stop)        // 清理过期请求随机字符串
+}
+
+// Stop 停止后台任务 等待付款码支付后台轮询退出
+func (srv *Handler) Stop() {
+	if srv.stop != nil {
+		close(srv.stop)
+	}
+	if srv.poller != nil {
+		srv.poller.Close()
+	}
 }
 
 // Config 订单管理服务实现
 func (srv *Handler) Config() *Config {
-	return &Config{&repository.ConfigRepository{db.DB}}
+	return &Config{
+		Repo:  &repository.ConfigRepository{db.DB},
+		Event: micro.NewEvent(configTopic, srv.Client),
+		Cache: srv.configCache(),
+	}
 }
 
 // Order 订单管理服务实现
 func (srv *Handler) Order() *Order {
-	return &Order{&repository.OrderRepository{db.DB}}
+	return &Order{&repository.OrderRepository{db.DB}, &repository.ChannelLogRepository{db.DB}}
 }
 
 // Trade 订单管理服务实现
 func (srv *Handler) Trade() *Trade {
+	if srv.poller == nil { // Register 和 Run 时创建服务 没有并发调用
+		srv.poller = NewPoller()
+	}
 	return &Trade{
-		Config: &repository.ConfigRepository{db.DB},
-		Repo:   &repository.OrderRepository{db.DB},
-		Alipay: &trade.Alipay{},
-		Wechat: &trade.Wechat{},
-		Icbc:   &trade.Icbc{},
+		Cache:   srv.configCache(),
+		Config:  &repository.ConfigRepository{db.DB},
+		Repo:    &repository.OrderRepository{db.DB},
+		Webhook: &repository.WebhookRepository{db.DB},
+		Sharing: &repository.SharingRepository{db.DB},
+		Repair:  &repository.RepairRepository{db.DB},
+		Poller:  srv.poller,
+	}
+}
+
+// configCache 商户配置和支付通道缓存 Register 和 Run 时创建服务 没有并发调用
+func (srv *Handler) configCache() *trade.Cache {
+	if srv.cache == nil {
+		srv.cache = trade.NewCache(configCacheTTL)
+	}
+	return srv.cache
+}
+
+// Notify 异步通知服务实现
+func (srv *Handler) Notify() *Notify {
+	return &Notify{srv.Trade()}
+}
+
+// Cashier 门店收银台服务实现
+func (srv *Handler) Cashier() *Cashier {
+	return &Cashier{srv.Trade()}
+}
+
+// Webhook 商户通知服务实现
+func (srv *Handler) Webhook() *Webhook {
+	repo := &repository.WebhookRepository{db.DB}
+	return &Webhook{
+		Repo:   repo,
+		Worker: webhook.NewWorker(repo, &repository.ConfigRepository{db.DB}),
+	}
+}
+
+// Bill 对账服务实现
+func (srv *Handler) Bill() *Bill {
+	return &Bill{
+		Trade: srv.Trade(),
+		Repo:  &repository.BillRepository{db.DB},
+		Lease: &repository.LeaseRepository{db.DB},
+		Owner: uuid.NewV4().String(),
 	}
 }
+
+// Sharing 分账服务实现
+func (srv *Handler) Sharing() *Sharing {
+	return &Sharing{srv.Trade(), &repository.SharingRepository{db.DB}}
+}
+
+// Repair 订单补偿服务实现
+func (srv *Handler) Repair() *Repair {
+	return &Repair{srv.Trade(), &repository.RepairRepository{db.DB}}
+}
+
+// Sync 待付款和待退款订单同步
+func (srv *Handler) Sync() *Sync {
+	return NewSync(srv.Trade(), &repository.LeaseRepository{db.DB})
+}
+
+// Gateway 开放接口网关
+func (srv *Handler) Gateway() *Gateway {
+	key, err := gateway.Platform()
+	if err != nil {
+		log.Fatal(err)
+	}
+	return &Gateway{
+		Trade: srv.Trade(),
+		App:   &repository.AppRepository{db.DB},
+		Nonce: &repository.NonceRepository{db.DB},
+		Key:   key,
+	}
+}
+
+// App 开发者应用服务实现
+func (srv *Handler) App() *App {
+	return &App{&repository.AppRepository{db.DB}}
+}
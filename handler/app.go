@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	pb "github.com/lecex/pay/proto/app"
+	"github.com/lecex/pay/service/gateway"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/secret"
+	"github.com/lecex/pay/util"
+)
+
+// App 开发者应用
+type App struct {
+	Repo repository.App
+}
+
+// List 获取开发者应用列表
+func (srv *App) List(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	apps, err := srv.Repo.List(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	total, err := srv.Repo.Total(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		maskApp(ctx, app)
+	}
+	res.Total = total
+	res.Apps = apps
+	return err
+}
+
+// Get 获取开发者应用
+func (srv *App) Get(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	err = srv.Repo.Get(req.App)
+	if err != nil {
+		return err
+	}
+	res.App = req.App
+	maskApp(ctx, res.App)
+	return err
+}
+
+// Create 创建开发者应用
+func (srv *App) Create(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.App.AppId == "" {
+		req.App.AppId = util.NonceStr()
+	}
+	if err = validateApp(req.App); err != nil {
+		return err
+	}
+	err = srv.Repo.Create(req.App)
+	if err != nil {
+		res.Valid = false
+		return fmt.Errorf("创建应用失败")
+	}
+	res.App = req.App
+	res.Valid = true
+	maskApp(ctx, res.App)
+	return err
+}
+
+// Update 更新开发者应用 传入的脱敏秘钥视为未修改
+func (srv *App) Update(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if secret.Masked(req.App.Secret) {
+		stored := &pb.App{Id: req.App.Id}
+		if err = srv.Repo.Get(stored); err != nil || secret.Mask(stored.Secret) != req.App.Secret {
+			return fmt.Errorf("应用秘钥与已保存秘钥不一致")
+		}
+		req.App.Secret = stored.Secret
+	}
+	if err = validateApp(req.App); err != nil {
+		return err
+	}
+	err = srv.Repo.Update(req.App)
+	if err != nil {
+		res.Valid = false
+		return fmt.Errorf("更新应用失败")
+	}
+	res.App = req.App
+	res.Valid = true
+	maskApp(ctx, res.App)
+	return err
+}
+
+// Delete 删除开发者应用
+func (srv *App) Delete(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	valid, err := srv.Repo.Delete(req.App)
+	if err != nil {
+		res.Valid = false
+		return fmt.Errorf("删除应用失败")
+	}
+	res.Valid = valid
+	return err
+}
+
+// maskApp 没有秘钥权限时应用秘钥替换为指纹
+func maskApp(ctx context.Context, app *pb.App) {
+	if app != nil && !privileged(ctx) {
+		app.Secret = secret.Mask(app.Secret)
+	}
+}
+
+// validateApp 校验签名方式、验签秘钥、商户和 IP 白名单
+func validateApp(app *pb.App) error {
+	switch app.SignType {
+	case gateway.RSA2:
+		if _, err := util.ParsePublicKey(app.PublicKey); err != nil {
+			return fmt.Errorf("应用公钥错误:%s", err.Error())
+		}
+	case gateway.HmacSHA256:
+		if len(app.Secret) < 32 {
+			return fmt.Errorf("应用秘钥长度不能少于32位")
+		}
+	default:
+		return fmt.Errorf("签名方式只支持 %s、%s", gateway.RSA2, gateway.HmacSHA256)
+	}
+	if strings.TrimSpace(app.StoreIds) == "" {
+		return fmt.Errorf("请传入允许调用的商户ID")
+	}
+	for _, v := range strings.Split(app.IpAllowlist, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(v); err != nil && net.ParseIP(v) == nil {
+			return fmt.Errorf("IP 白名单格式错误:%s", v)
+		}
+	}
+	return nil
+}
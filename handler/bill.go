@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v2/util/log"
+
+	pb "github.com/lecex/pay/proto/bill"
+	configPB "github.com/lecex/pay/proto/config"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/service/worker"
+)
+
+// billDateLayout 对账日期格式
+const billDateLayout = "2006-01-02"
+
+// billLeaseName 每日对账租约 多个实例同时运行时只有获取租约的实例对账
+// 对账完成后记录完成日期并释放租约 对账中断时租约 PAY_BILL_LEASE 秒(默认3600)到期后由其它实例重新对账
+const billLeaseName = "bill_daily"
+
+var (
+	billInterval = 10 * time.Minute
+	billLease    = time.Duration(envInt("PAY_BILL_LEASE", 3600)) * time.Second
+)
+
+// Bill 对账
+type Bill struct {
+	Trade *Trade
+	Repo  repository.Bill
+	Lease repository.Lease
+	Owner string // 实例标识
+}
+
+// Reconcile 下载通道对账单并与系统订单对账 未指定商户时对账所有启用的商户
+func (srv *Bill) Reconcile(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.BillDate == "" {
+		return fmt.Errorf("对账日期不允许为空:BillDate")
+	}
+	if _, err = time.Parse(billDateLayout, req.BillDate); err != nil {
+		return fmt.Errorf("对账日期格式错误:%s", req.BillDate)
+	}
+	storeIds := []string{req.StoreId}
+	if req.StoreId == "" {
+		if storeIds, err = srv.storeIds(); err != nil {
+			return err
+		}
+	}
+	var errs []string
+	for _, storeId := range storeIds {
+		con, err := srv.Trade.storeConfig(storeId)
+		if err != nil {
+			errs = append(errs, storeId+":查询商户支付配置信息失败")
+			log.Error(req, storeId, err)
+			continue
+		}
+		channels := []string{req.Channel}
+		if req.Channel == "" {
+			channels = billChannels(con)
+		}
+		for _, name := range channels {
+			discrepancies, err := srv.reconcile(con, name, req.BillDate)
+			if err != nil {
+				errs = append(errs, storeId+"."+name+":"+err.Error())
+				log.Error(req, storeId, name, err)
+				continue
+			}
+			res.Discrepancies = append(res.Discrepancies, discrepancies...)
+		}
+	}
+	res.Total = int64(len(res.Discrepancies))
+	if len(errs) > 0 {
+		return errors.New("对账失败:" + strings.Join(errs, ";"))
+	}
+	res.Valid = true
+	return nil
+}
+
+// List 获取对账差异列表
+func (srv *Bill) List(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	discrepancies, err := srv.Repo.List(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	total, err := srv.Repo.Total(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	res.Total = total
+	res.Discrepancies = discrepancies
+	return err
+}
+
+// Daily 每天 PAY_BILL_HOUR 点(默认10点 通道对账单生成后)起每 billInterval 检查一次 对账前一天所有商户 stop 关闭后退出
+func (srv *Bill) Daily(stop <-chan struct{}) {
+	hour := envInt("PAY_BILL_HOUR", 10)
+	worker.Run(billInterval, stop, func() {
+		now := time.Now().In(worker.CST)
+		if now.Hour() < hour {
+			return
+		}
+		srv.daily(now.AddDate(0, 0, -1).Format(billDateLayout))
+	})
+}
+
+// daily 获取租约后对账所有商户 其它实例已获取租约或当天已完成对账时跳过
+// 部分商户对账失败时同样记录完成 失败的商户记录日志 需要手动调用 Reconcile
+func (srv *Bill) daily(date string) {
+	now := time.Now()
+	ok, err := srv.Lease.Acquire(billLeaseName, srv.Owner, worker.Format(now), worker.Format(now.Add(billLease)))
+	if err != nil {
+		log.Error(date, err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer func() {
+		if err := srv.Lease.Release(billLeaseName, srv.Owner, worker.Format(time.Now())); err != nil {
+			log.Error(date, err)
+		}
+	}()
+	// 获取租约后检查 其它实例可能已完成对账并释放租约
+	finished, err := srv.Repo.Finished(date)
+	if err != nil {
+		log.Error(date, err)
+		return
+	}
+	if finished {
+		return
+	}
+	if err := srv.Reconcile(context.TODO(), &pb.Request{BillDate: date}, &pb.Response{}); err != nil {
+		log.Error(date, err)
+	}
+	if err := srv.Repo.Finish(date, worker.Format(time.Now())); err != nil {
+		log.Error(date, err)
+	}
+}
+
+// reconcile 对账单个商户通道 对账单日期前后一天的订单用于匹配跨日交易
+func (srv *Bill) reconcile(con *configPB.Config, name string, date string) ([]*pb.BillDiscrepancy, error) {
+	channel, err := srv.Trade.channel(name, con)
+	if err != nil {
+		return nil, err
+	}
+	biller, ok := channel.(trade.Biller)
+	if !ok {
+		return nil, fmt.Errorf("支付通道不支持下载对账单:%s", name)
+	}
+	records, err := biller.Bill(date)
+	if err != nil {
+		return nil, err
+	}
+	day, _ := time.Parse(billDateLayout, date)
+	layout := worker.TimeLayout
+	orders, err := srv.Repo.Orders(con.Id, name, day.AddDate(0, 0, -1).Format(layout), day.AddDate(0, 0, 2).Format(layout))
+	if err != nil {
+		return nil, err
+	}
+	dayOrders, err := srv.Repo.Orders(con.Id, name, day.Format(layout), day.AddDate(0, 0, 1).Format(layout))
+	if err != nil {
+		return nil, err
+	}
+	discrepancies := bill.Reconcile(records, orders, dayOrders)
+	for _, d := range discrepancies {
+		d.StoreId, d.Channel, d.BillDate = con.Id, name, date
+	}
+	if err = srv.Repo.Save(con.Id, name, date, discrepancies); err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+// storeIds 获取所有启用的商户
+func (srv *Bill) storeIds() (storeIds []string, err error) {
+	const limit = 100
+	for page := int64(1); ; page++ {
+		configs, err := srv.Trade.Config.List(&configPB.ListQuery{Limit: limit, Page: page, Where: "status = 1"})
+		if err != nil {
+			return nil, err
+		}
+		for _, con := range configs {
+			storeIds = append(storeIds, con.Id)
+		}
+		if len(configs) < limit {
+			return storeIds, nil
+		}
+	}
+}
+
+// billChannels 商户已配置的支付通道
+func billChannels(con *configPB.Config) (channels []string) {
+	if con.Alipay != nil && con.Alipay.AppId != "" {
+		channels = append(channels, "alipay")
+	}
+	if con.Wechat != nil && con.Wechat.MchId != "" {
+		channels = append(channels, "wechat")
+	}
+	if con.Icbc != nil && con.Icbc.AppId != "" {
+		channels = append(channels, "icbc")
+	}
+	return channels
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/lecex/pay/proto/bill"
+	configPB "github.com/lecex/pay/proto/config"
+	orderPB "github.com/lecex/pay/proto/order"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/worker"
+)
+
+// fakeBill 内存对账仓库 订单直接读取内存订单仓库
+type fakeBill struct {
+	orders   *fakeOrder
+	saved    map[string][]*pb.BillDiscrepancy
+	finished map[string]bool
+}
+
+func (repo *fakeBill) Orders(storeId string, channel string, start string, end string) (orders []*orderPB.Order, err error) {
+	repo.orders.mu.Lock()
+	defer repo.orders.mu.Unlock()
+	for _, o := range repo.orders.orders {
+		if o.StoreId == storeId && o.Channel == channel {
+			c := *o
+			orders = append(orders, &c)
+		}
+	}
+	return orders, nil
+}
+
+func (repo *fakeBill) Save(storeId string, channel string, billDate string, discrepancies []*pb.BillDiscrepancy) error {
+	repo.saved[storeId+channel+billDate] = discrepancies
+	return nil
+}
+
+func (repo *fakeBill) List(req *pb.ListQuery) ([]*pb.BillDiscrepancy, error) { return nil, nil }
+func (repo *fakeBill) Total(req *pb.ListQuery) (int64, error)                { return 0, nil }
+func (repo *fakeBill) Finished(billDate string) (bool, error)                { return repo.finished[billDate], nil }
+
+func (repo *fakeBill) Finish(billDate string, finishedAt string) error {
+	repo.finished[billDate] = true
+	return nil
+}
+
+func TestBillReconcile(t *testing.T) {
+	h := newTestTrade(1)
+	for _, outTradeNo := range []string{"B0001", "B0002"} {
+		h.AopF2F(context.TODO(), &tradePB.Request{
+			StoreId: "store-0",
+			BizContent: &tradePB.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "对账测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+			},
+		}, &tradePB.Response{})
+	}
+	repo := &fakeBill{orders: h.Repo.(*fakeOrder), saved: map[string][]*pb.BillDiscrepancy{}, finished: map[string]bool{}}
+	b := &Bill{Trade: h, Repo: repo}
+	res := &pb.Response{}
+	err := b.Reconcile(context.TODO(), &pb.Request{StoreId: "store-0", Channel: "fake", BillDate: "2020-01-01"}, res)
+	if err != nil || !res.Valid {
+		t.Fatal(res, err)
+	}
+	want := map[string]string{"B0002": bill.ChannelMissing, "B0009": bill.LocalMissing}
+	if len(res.Discrepancies) != len(want) {
+		t.Fatalf("want %d discrepancies got %+v", len(want), res.Discrepancies)
+	}
+	for _, d := range res.Discrepancies {
+		if want[d.OutTradeNo] != d.Type || d.StoreId != "store-0" || d.BillDate != "2020-01-01" {
+			t.Errorf("unexpected discrepancy %+v", d)
+		}
+	}
+	if len(repo.saved["store-0fake2020-01-01"]) != len(want) {
+		t.Fatalf("discrepancies not saved: %+v", repo.saved)
+	}
+	if err = b.Reconcile(context.TODO(), &pb.Request{StoreId: "store-0", BillDate: "20200101"}, res); err == nil {
+		t.Fatal("want invalid bill date error")
+	}
+}
+
+// countConfig 记录查询商户列表次数
+type countConfig struct {
+	*fakeConfig
+	lists int
+}
+
+func (repo *countConfig) List(req *configPB.ListQuery) ([]*configPB.Config, error) {
+	repo.lists++
+	return repo.fakeConfig.List(req)
+}
+
+func TestBillDailyLease(t *testing.T) {
+	h := newTestTrade(1)
+	config := &countConfig{fakeConfig: h.Config.(*fakeConfig)}
+	h.Config = config
+	repo := &fakeBill{orders: h.Repo.(*fakeOrder), saved: map[string][]*pb.BillDiscrepancy{}, finished: map[string]bool{}}
+	lease := &fakeLease{owners: map[string][2]string{}}
+	// 多个实例同时对账 只有获取租约的实例对账
+	a := &Bill{Trade: h, Repo: repo, Lease: lease, Owner: "a"}
+	b := &Bill{Trade: h, Repo: repo, Lease: lease, Owner: "b"}
+	a.daily("2020-01-01")
+	b.daily("2020-01-01")
+	if config.lists != 1 || !repo.finished["2020-01-01"] {
+		t.Fatalf("want 1 reconciliation got %d", config.lists)
+	}
+	// 对账完成后释放租约 其它实例可对账下一天
+	b.daily("2020-01-02")
+	if config.lists != 2 {
+		t.Fatalf("lease not released, got %d reconciliations", config.lists)
+	}
+	// 实例对账中断 租约到期前其它实例跳过 到期后重新对账
+	now := time.Now()
+	lease.owners[billLeaseName] = [2]string{"crashed", worker.Format(now.Add(time.Hour))}
+	a.daily("2020-01-03")
+	if config.lists != 2 || repo.finished["2020-01-03"] {
+		t.Fatalf("want lease held, got %d reconciliations", config.lists)
+	}
+	lease.owners[billLeaseName] = [2]string{"crashed", worker.Format(now.Add(-time.Second))}
+	a.daily("2020-01-03")
+	if config.lists != 3 || !repo.finished["2020-01-03"] {
+		t.Fatalf("want reconciliation after lease expired, got %d", config.lists)
+	}
+}
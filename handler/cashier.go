@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/micro/go-micro/v2/util/log"
+
+	configPB "github.com/lecex/pay/proto/config"
+	pb "github.com/lecex/pay/proto/notify"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/cashier"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/util"
+)
+
+// Cashier 门店收银台 用户使用微信或支付宝扫描门店二维码输入金额付款
+type Cashier struct {
+	Trade *Trade
+}
+
+// Index 收银台首页 微信客户端先静默授权获取 openid 再展示输入金额页面
+func (srv *Cashier) Index(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	storeId := util.Get(req, "store_id")
+	con, client, err := srv.config(storeId, req)
+	if err != nil {
+		srv.html(res, cashier.RenderError(err.Error()))
+		return nil
+	}
+	page := &cashier.Page{
+		StoreId:   con.Id,
+		StoreName: con.StoreName,
+		Client:    client,
+		ReturnUrl: con.ReturnUrl,
+	}
+	if client == cashier.Wechat {
+		appId, _, err := cashier.WechatApp(con)
+		if err != nil {
+			srv.html(res, cashier.RenderError(err.Error()))
+			return nil
+		}
+		code := util.Get(req, "code")
+		if code == "" {
+			redirect := cashier.Url(storeId)
+			if redirect == "" {
+				srv.html(res, cashier.RenderError("未配置收银台地址"))
+				log.Error(req, "PAY_CASHIER_URL 未配置")
+				return nil
+			}
+			res.StatusCode = 302
+			res.Header = map[string]*pb.Pair{
+				"Location": {Key: "Location", Values: []string{trade.WechatOauthUrl(appId, redirect)}},
+			}
+			return nil
+		}
+		openId, err := trade.WechatOpenId(appId, con.Wechat.AppSecret, code)
+		if err != nil {
+			srv.html(res, cashier.RenderError("微信授权失败 请重新扫码"))
+			log.Error(req, err)
+			return nil
+		}
+		page.OpenIdToken = cashier.OpenIdToken(con.Wechat.AppSecret, con.Id, openId, time.Now().Add(cashier.OpenIdTTL))
+	}
+	body, err := cashier.Render(page)
+	if err != nil {
+		srv.html(res, cashier.RenderError("页面加载失败"))
+		log.Error(req, err)
+		return nil
+	}
+	srv.html(res, body)
+	return nil
+}
+
+// Pay 收银台下单 微信使用公众号支付返回调起支付参数 支付宝使用手机网站支付返回跳转地址
+func (srv *Cashier) Pay(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	form := util.Form(req)
+	content := &tradePB.Content{}
+	defer func() {
+		body, _ := json.Marshal(map[string]string{
+			"return_code":  content.ReturnCode,
+			"return_msg":   content.ReturnMsg,
+			"out_trade_no": content.OutTradeNo,
+			"pay_params":   content.PayParams,
+			"pay_url":      content.PayUrl,
+		})
+		res.StatusCode = 200
+		res.Header = map[string]*pb.Pair{
+			"Content-Type": {Key: "Content-Type", Values: []string{"application/json; charset=utf-8"}},
+		}
+		res.Body = string(body)
+	}()
+	con, client, err := srv.config(form["store_id"], req)
+	if err != nil {
+		content.ReturnCode = "Cashier.Pay.config"
+		content.ReturnMsg = err.Error()
+		return nil
+	}
+	channel, err := cashier.Channel(client, con)
+	if err != nil {
+		content.ReturnCode = "Cashier.Pay.Channel"
+		content.ReturnMsg = err.Error()
+		return nil
+	}
+	totalFee, err := cashier.ParseAmount(form["amount"])
+	if err != nil {
+		content.ReturnCode = "Cashier.Pay.Amount"
+		content.ReturnMsg = err.Error()
+		return nil
+	}
+	title := con.StoreName
+	if title == "" {
+		title = "扫码付款"
+	}
+	tradeReq := &tradePB.Request{
+		StoreId: con.Id,
+		BizContent: &tradePB.BizContent{
+			Channel:    channel,
+			Title:      title,
+			TotalFee:   totalFee,
+			OutTradeNo: cashier.OutTradeNo(),
+		},
+	}
+	tradeRes := &tradePB.Response{}
+	switch client {
+	case cashier.Wechat:
+		_, sub, err := cashier.WechatApp(con)
+		if err != nil {
+			content.ReturnCode = "Cashier.Pay.WechatApp"
+			content.ReturnMsg = err.Error()
+			return nil
+		}
+		// 只使用收银台首页授权签发的 openid 不信任提交的 openid
+		openId, err := cashier.OpenId(con.Wechat.AppSecret, con.Id, form["open_id_token"], time.Now())
+		if err != nil {
+			content.ReturnCode = "Cashier.Pay.OpenId"
+			content.ReturnMsg = err.Error()
+			return nil
+		}
+		if sub {
+			tradeReq.BizContent.SubOpenId = openId
+		} else {
+			tradeReq.BizContent.OpenId = openId
+		}
+		err = srv.Trade.JsapiPay(ctx, tradeReq, tradeRes)
+	default:
+		tradeReq.BizContent.Scene = "WAP"
+		err = srv.Trade.WebPay(ctx, tradeReq, tradeRes)
+	}
+	if err != nil {
+		content.ReturnCode = "Cashier.Pay.Error"
+		content.ReturnMsg = err.Error()
+		log.Error(req, err)
+		return nil
+	}
+	content = tradeRes.Content
+	return nil
+}
+
+// config 获取启用的商户配置并识别扫码客户端
+func (srv *Cashier) config(storeId string, req *pb.Request) (con *configPB.Config, client string, err error) {
+	if storeId == "" {
+		return nil, "", errors.New("收款码无效 缺少商户编号")
+	}
+	con, err = srv.Trade.storeConfig(storeId)
+	if err != nil {
+		log.Error(req, err)
+		return nil, "", errors.New("收款码无效 商户不存在")
+	}
+	if !con.Status {
+		return nil, "", errors.New("商户支付功能已禁用")
+	}
+	client = cashier.Client(header(req, "User-Agent"))
+	if _, err = cashier.Channel(client, con); err != nil {
+		return nil, "", err
+	}
+	return con, client, nil
+}
+
+// html 返回网页内容
+func (srv *Cashier) html(res *pb.Response, body string) {
+	res.StatusCode = 200
+	res.Header = map[string]*pb.Pair{
+		"Content-Type": {Key: "Content-Type", Values: []string{"text/html; charset=utf-8"}},
+	}
+	res.Body = body
+}
+
+// header 获取请求头
+func header(req *pb.Request, key string) string {
+	if v, ok := req.Header[key]; ok && len(v.Values) > 0 {
+		return v.Values[0]
+	}
+	return ""
+}
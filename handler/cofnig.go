@@ -3,25 +3,66 @@ package handler
 import (
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v2"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/env"
 
 	pb "github.com/lecex/pay/proto/config"
 	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/secret"
+	"github.com/lecex/pay/service/trade"
+)
+
+// 商户配置变更类型
+const (
+	configUpdate = "update"
+	configDelete = "delete"
 )
 
+// 商户秘钥权限 请求元数据 X-Pay-Scope 为权限服务使用 PAY_SCOPE_SECRET 签发且包含 config:secret 的权限令牌时返回秘钥明文
+// 客户端自行设置的权限不会被信任 未配置 PAY_SCOPE_SECRET 时始终脱敏
+const (
+	scopeHeader = "X-Pay-Scope"
+	secretScope = "config:secret"
+)
+
+// scopeSecret 权限令牌签名秘钥 只由权限服务持有
+var scopeSecret = env.Getenv("PAY_SCOPE_SECRET", "")
+
 // Config 配置
 type Config struct {
-	Repo repository.Config
+	Repo  repository.Config
+	Event micro.Event  // 商户配置变更事件 通知其它实例清除缓存
+	Cache *trade.Cache // 本实例商户配置和支付通道缓存
 }
 
 // SelfUpdate 用户通过 token 自己更新支付数据
 func (srv *Config) SelfUpdate(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
 	if srv.Repo.Exist(req.Config) {
+		err = srv.unmask(req.Config)
+		if err != nil {
+			return err
+		}
+		if !validate(req.Config, res) {
+			return nil
+		}
 		err = srv.Repo.Update(req.Config)
 		if err != nil {
 			return err
 		}
 		res.Config = req.Config
+		srv.changed(req.Config.Id, configUpdate)
 	} else {
+		err = srv.unmask(req.Config)
+		if err != nil {
+			return err
+		}
+		if !validate(req.Config, res) {
+			return nil
+		}
 		err = srv.Repo.Create(req.Config)
 		if err != nil {
 			return err
@@ -29,9 +70,7 @@ func (srv *Config) SelfUpdate(ctx context.Context, req *pb.Request, res *pb.Resp
 		res.Config = req.Config
 		res.Valid = true
 	}
-	if err != nil {
-		return err
-	}
+	mask(ctx, res.Config)
 	return err
 }
 
@@ -44,6 +83,9 @@ func (srv *Config) List(ctx context.Context, req *pb.Request, res *pb.Response)
 	}
 	res.Total = total
 	res.Configs = configs
+	for _, config := range res.Configs {
+		mask(ctx, config)
+	}
 	return err
 }
 
@@ -54,11 +96,19 @@ func (srv *Config) Get(ctx context.Context, req *pb.Request, res *pb.Response) (
 		return err
 	}
 	res.Config = req.Config
+	mask(ctx, res.Config)
 	return err
 }
 
 // Create 创建配置
 func (srv *Config) Create(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	err = srv.unmask(req.Config)
+	if err != nil {
+		return err
+	}
+	if !validate(req.Config, res) {
+		return nil
+	}
 	err = srv.Repo.Create(req.Config)
 	if err != nil {
 		res.Valid = false
@@ -66,17 +116,27 @@ func (srv *Config) Create(ctx context.Context, req *pb.Request, res *pb.Response
 	}
 	res.Config = req.Config
 	res.Valid = true
+	mask(ctx, res.Config)
 	return err
 }
 
 // Update 更新配置
 func (srv *Config) Update(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	err = srv.unmask(req.Config)
+	if err != nil {
+		return err
+	}
+	if !validate(req.Config, res) {
+		return nil
+	}
 	err = srv.Repo.Update(req.Config)
 	if err != nil {
 		res.Valid = false
 		return fmt.Errorf("更新配置失败")
 	}
+	srv.changed(req.Config.Id, configUpdate)
 	res.Config = req.Config
+	mask(ctx, res.Config)
 	return err
 }
 
@@ -87,6 +147,104 @@ func (srv *Config) Delete(ctx context.Context, req *pb.Request, res *pb.Response
 		res.Valid = false
 		return fmt.Errorf("删除配置失败")
 	}
+	srv.changed(req.Config.Id, configDelete)
 	res.Valid = valid
 	return err
 }
+
+// changed 商户配置变更后清除本实例缓存 并通知其它实例清除缓存
+// 通知失败时其它实例在缓存到期后读取新配置
+func (srv *Config) changed(id string, action string) {
+	srv.Cache.Invalidate(id)
+	if srv.Event == nil {
+		return
+	}
+	if err := srv.Event.Publish(context.TODO(), &pb.Event{Id: id, Action: action}); err != nil {
+		log.Error(id, action, err)
+	}
+}
+
+// Changed 订阅商户配置变更事件 清除本实例商户配置和支付通道缓存
+func (srv *Config) Changed(ctx context.Context, e *pb.Event) error {
+	srv.Cache.Invalidate(e.Id)
+	return nil
+}
+
+// validate 保存前校验商户配置 校验失败时返回每个字段的错误 不保存配置
+func validate(config *pb.Config, res *pb.Response) bool {
+	if config == nil {
+		return true
+	}
+	res.Errors = trade.Validate(config)
+	if len(res.Errors) > 0 {
+		res.Valid = false
+		return false
+	}
+	return true
+}
+
+// secretFields 商户秘钥字段 读取时脱敏
+func secretFields(config *pb.Config) map[string]*string {
+	fields := map[string]*string{
+		"notify_secret": &config.NotifySecret,
+	}
+	if config.Alipay != nil {
+		fields["alipay.private_key"] = &config.Alipay.PrivateKey
+		fields["alipay.app_auth_token"] = &config.Alipay.AppAuthToken
+	}
+	if config.Wechat != nil {
+		fields["wechat.api_key"] = &config.Wechat.ApiKey
+		fields["wechat.pem_key"] = &config.Wechat.PemKey
+		fields["wechat.app_secret"] = &config.Wechat.AppSecret
+	}
+	if config.Icbc != nil {
+		fields["icbc.private_key"] = &config.Icbc.PrivateKey
+	}
+	return fields
+}
+
+// privileged 调用方是否拥有商户秘钥权限
+func privileged(ctx context.Context) bool {
+	for _, scope := range secret.Scopes(scopeSecret, metadataValue(ctx, scopeHeader), time.Now()) {
+		if scope == secretScope {
+			return true
+		}
+	}
+	return false
+}
+
+// mask 没有商户秘钥权限时秘钥替换为指纹
+func mask(ctx context.Context, config *pb.Config) {
+	if config == nil || privileged(ctx) {
+		return
+	}
+	for _, field := range secretFields(config) {
+		*field = secret.Mask(*field)
+	}
+}
+
+// unmask 更新时传入的脱敏秘钥视为未修改 替换为已保存的秘钥 指纹不一致时返回错误
+func (srv *Config) unmask(config *pb.Config) error {
+	if config == nil {
+		return nil
+	}
+	var stored map[string]*string
+	for name, field := range secretFields(config) {
+		if !secret.Masked(*field) {
+			continue
+		}
+		if stored == nil {
+			repoConfig := &pb.Config{Id: config.Id}
+			if config.Id == "" || srv.Repo.Get(repoConfig) != nil {
+				return fmt.Errorf("配置秘钥 %s 无效", name)
+			}
+			stored = secretFields(repoConfig)
+		}
+		value, ok := stored[name]
+		if !ok || secret.Mask(*value) != *field {
+			return fmt.Errorf("配置秘钥 %s 与已保存秘钥不一致", name)
+		}
+		*field = *value
+	}
+	return nil
+}
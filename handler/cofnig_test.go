@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v2/metadata"
+
+	pb "github.com/lecex/pay/proto/config"
+	"github.com/lecex/pay/service/secret"
+)
+
+// savingConfig 保存更新内容的商户配置仓库
+type savingConfig struct {
+	*fakeConfig
+}
+
+func (repo *savingConfig) List(req *pb.ListQuery) (configs []*pb.Config, err error) {
+	for id := range repo.configs {
+		config := &pb.Config{Id: id}
+		repo.Get(config)
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+func (repo *savingConfig) Update(config *pb.Config) error {
+	c := *config
+	alipay, wechat, icbc := *config.Alipay, *config.Wechat, *config.Icbc
+	c.Alipay, c.Wechat, c.Icbc = &alipay, &wechat, &icbc
+	repo.configs[config.Id] = &c
+	return nil
+}
+
+// testRSAKey 测试用 RSA 私钥和公钥 base64 编码
+func testRSAKey(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key)), base64.StdEncoding.EncodeToString(pub)
+}
+
+func TestConfigMask(t *testing.T) {
+	trade := newTestTrade(1)
+	repo := &savingConfig{trade.Config.(*fakeConfig)}
+	privateKey, publicKey := testRSAKey(t)
+	repo.configs["store-0"].Alipay = &pb.Alipay{AppId: "app-0", PrivateKey: privateKey, AliPayPublicKey: publicKey, SignType: "RSA2"}
+	repo.configs["store-0"].Wechat = &pb.Wechat{AppId: "wx-0", MchId: "mch-0", ApiKey: strings.Repeat("k", 32)}
+	srv := &Config{Repo: repo}
+
+	// 默认返回秘钥指纹
+	res := &pb.Response{}
+	if err := srv.Get(context.TODO(), &pb.Request{Config: &pb.Config{Id: "store-0"}}, res); err != nil {
+		t.Fatal(err)
+	}
+	masked := res.Config
+	if masked.Alipay.PrivateKey != secret.Mask(privateKey) || !secret.Masked(masked.Wechat.ApiKey) || masked.Alipay.AppId == "" {
+		t.Fatalf("Get = %+v", masked.Alipay)
+	}
+	res = &pb.Response{}
+	srv.List(context.TODO(), &pb.Request{}, res)
+	if len(res.Configs) != 1 || strings.Contains(res.Configs[0].String(), privateKey) {
+		t.Fatalf("List = %v", res.Configs)
+	}
+
+	// 客户端自行设置的权限不返回明文
+	scopeSecret = "scope-secret"
+	defer func() { scopeSecret = "" }()
+	for _, scope := range []string{
+		"config:secret",
+		secret.ScopeToken("other-secret", []string{"config:secret"}, time.Now().Add(time.Minute)),
+		secret.ScopeToken(scopeSecret, []string{"config:secret"}, time.Now().Add(-time.Minute)),
+		secret.ScopeToken(scopeSecret, []string{"config:read"}, time.Now().Add(time.Minute)),
+	} {
+		ctx := metadata.NewContext(context.TODO(), metadata.Metadata{"X-Pay-Scope": scope})
+		res = &pb.Response{}
+		srv.Get(ctx, &pb.Request{Config: &pb.Config{Id: "store-0"}}, res)
+		if res.Config.Alipay.PrivateKey == privateKey {
+			t.Fatalf("scope %s unmasked private key", scope)
+		}
+	}
+
+	// 权限服务签发的令牌包含商户秘钥权限时返回明文
+	token := secret.ScopeToken(scopeSecret, []string{"config:read", "config:secret"}, time.Now().Add(time.Minute))
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{"X-Pay-Scope": token})
+	res = &pb.Response{}
+	srv.Get(ctx, &pb.Request{Config: &pb.Config{Id: "store-0"}}, res)
+	if res.Config.Alipay.PrivateKey != privateKey {
+		t.Fatalf("privileged Get = %+v", res.Config.Alipay)
+	}
+
+	// 回传脱敏秘钥视为未修改
+	masked.Alipay.AppId = "app-new"
+	res = &pb.Response{}
+	if err := srv.SelfUpdate(context.TODO(), &pb.Request{Config: masked}, res); err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.configs["store-0"]
+	if stored.Alipay.AppId != "app-new" || stored.Alipay.PrivateKey != privateKey || secret.Masked(stored.Wechat.ApiKey) {
+		t.Fatalf("stored = %+v %+v", stored.Alipay, stored.Wechat)
+	}
+	if !secret.Masked(res.Config.Alipay.PrivateKey) {
+		t.Fatalf("SelfUpdate response = %+v", res.Config.Alipay)
+	}
+
+	// 指纹与已保存秘钥不一致时拒绝更新
+	forged := &pb.Config{Id: "store-0", Alipay: &pb.Alipay{PrivateKey: secret.Mask("other")}, Wechat: &pb.Wechat{}, Icbc: &pb.Icbc{}}
+	if err := srv.Update(context.TODO(), &pb.Request{Config: forged}, &pb.Response{}); err == nil {
+		t.Fatal("Update accepted forged mask")
+	}
+	if repo.configs["store-0"].Alipay.PrivateKey != privateKey {
+		t.Fatal("forged mask overwrote private key")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	trade := newTestTrade(1)
+	repo := &savingConfig{trade.Config.(*fakeConfig)}
+	srv := &Config{Repo: repo}
+	_, publicKey := testRSAKey(t)
+	config := &pb.Config{
+		Id:     "store-0",
+		Alipay: &pb.Alipay{AppId: "app-0", PrivateKey: "not-a-key", AliPayPublicKey: publicKey, SignType: "MD5"},
+		Wechat: &pb.Wechat{},
+		Icbc:   &pb.Icbc{},
+	}
+	res := &pb.Response{}
+	if err := srv.Update(context.TODO(), &pb.Request{Config: config}, res); err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]bool{}
+	for _, e := range res.Errors {
+		fields[e.Field] = true
+	}
+	if res.Valid || len(res.Errors) != 2 || !fields["alipay.sign_type"] || !fields["alipay.private_key"] {
+		t.Fatalf("errors = %v", res.Errors)
+	}
+	if repo.configs["store-0"].Alipay.PrivateKey == "not-a-key" {
+		t.Fatal("invalid config saved")
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v2/metadata"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/env"
+
+	appPB "github.com/lecex/pay/proto/app"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/gateway"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 开放接口配置 请求时间戳与服务器时间相差超过 PAY_API_TIMESTAMP_SKEW 秒时拒绝 有效期内随机字符串不可重复
+// PAY_TRUSTED_PROXIES 为设置 X-Forwarded-For 的可信代理 IP 或 CIDR 多个以逗号分隔
+var (
+	gatewaySkew          = time.Duration(envInt("PAY_API_TIMESTAMP_SKEW", 300)) * time.Second
+	gatewayCleanInterval = time.Minute
+	trustedProxies       = env.Getenv("PAY_TRUSTED_PROXIES", "")
+)
+
+// Gateway 开放接口网关 校验开发者应用签名后调用支付服务 应答使用平台私钥签名
+type Gateway struct {
+	Trade *Trade
+	App   repository.App
+	Nonce repository.Nonce
+	Key   string // 平台私钥 为空时应答不签名
+}
+
+// AopF2F 商家扫用户付款码
+func (srv *Gateway) AopF2F(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "AopF2F", req, res, srv.Trade.AopF2F)
+}
+
+// Query 支付查询
+func (srv *Gateway) Query(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "Query", req, res, srv.Trade.Query)
+}
+
+// Refund 退款
+func (srv *Gateway) Refund(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "Refund", req, res, srv.Trade.Refund)
+}
+
+// RefundQuery 退款查询
+func (srv *Gateway) RefundQuery(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "RefundQuery", req, res, srv.Trade.RefundQuery)
+}
+
+// Cancel 撤销交易
+func (srv *Gateway) Cancel(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "Cancel", req, res, srv.Trade.Cancel)
+}
+
+// Precreate 预下单
+func (srv *Gateway) Precreate(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "Precreate", req, res, srv.Trade.Precreate)
+}
+
+// JsapiPay 公众号/小程序支付
+func (srv *Gateway) JsapiPay(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "JsapiPay", req, res, srv.Trade.JsapiPay)
+}
+
+// WebPay 手机网站/电脑网站支付
+func (srv *Gateway) WebPay(ctx context.Context, req *pb.Request, res *pb.Response) error {
+	return srv.call(ctx, "WebPay", req, res, srv.Trade.WebPay)
+}
+
+// call 校验请求后调用支付服务 校验失败和支付服务的应答都使用平台私钥签名
+func (srv *Gateway) call(ctx context.Context, method string, req *pb.Request, res *pb.Response, next func(context.Context, *pb.Request, *pb.Response) error) error {
+	if code, msg := srv.verify(ctx, method, req); code != "" {
+		res.Content = &pb.Content{ReturnCode: code, ReturnMsg: msg}
+	} else if err := next(ctx, req, res); err != nil {
+		return err
+	}
+	if srv.Key != "" && res.Content != nil {
+		sign, err := gateway.Sign(srv.Key, res.Content)
+		if err != nil {
+			log.Error(method, err)
+		}
+		res.Sign = sign
+	}
+	return nil
+}
+
+// verify 校验开发者应用、IP 白名单、时间戳、签名、商户权限和随机字符串 返回错误码和错误信息
+func (srv *Gateway) verify(ctx context.Context, method string, req *pb.Request) (code string, msg string) {
+	if req.AppId == "" || req.Sign == "" || req.Timestamp == "" || req.Nonce == "" {
+		return "Gateway.Params.Missing", "缺少 app_id、sign、timestamp 或 nonce"
+	}
+	app := &appPB.App{AppId: req.AppId}
+	if err := srv.App.Get(app); err != nil || app.Id == 0 {
+		return "Gateway.AppId.NotFound", "应用不存在"
+	}
+	if !app.Status {
+		return "Gateway.AppId.Disabled", "应用已禁用"
+	}
+	if req.SignType != app.SignType {
+		return "Gateway.SignType.Invalid", "签名方式与应用注册的签名方式不一致"
+	}
+	if ip := clientIP(ctx); !gateway.AllowedIP(app.IpAllowlist, ip) {
+		return "Gateway.ClientIp.Forbidden", "IP " + ip + " 不在应用白名单内"
+	}
+	ts, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return "Gateway.Timestamp.Invalid", "时间戳格式错误"
+	}
+	now := time.Now()
+	if d := now.Sub(time.Unix(ts, 0)); d > gatewaySkew || d < -gatewaySkew {
+		return "Gateway.Timestamp.Expired", "请求已过期"
+	}
+	if err := gateway.Verify(app, gateway.RequestContent(method, req), req.Sign); err != nil {
+		return "Gateway.Sign.Invalid", "签名错误"
+	}
+	if !gateway.Contains(app.StoreIds, req.StoreId) {
+		return "Gateway.StoreId.Forbidden", "应用无权调用该商户"
+	}
+	// 签名校验通过后再记录随机字符串 避免伪造请求占用随机字符串
+	if len(req.Nonce) > 64 {
+		return "Gateway.Nonce.Invalid", "随机字符串长度不能超过64位"
+	}
+	fresh, err := srv.Nonce.Use(req.AppId, req.Nonce, worker.Format(now))
+	if err != nil {
+		log.Error(req.AppId, req.Nonce, err)
+		return "Gateway.Nonce.Error", "请求随机字符串记录失败"
+	}
+	if !fresh {
+		return "Gateway.Nonce.Replay", "重复请求"
+	}
+	return "", ""
+}
+
+// Run 定时清理超出时间戳有效期的随机字符串
+func (srv *Gateway) Run(stop <-chan struct{}) {
+	worker.Run(gatewayCleanInterval, stop, func() {
+		if err := srv.Nonce.Clean(worker.Format(time.Now().Add(-2 * gatewaySkew))); err != nil {
+			log.Error(err)
+		}
+	})
+}
+
+// clientIP 调用方 IP Remote 为 go-micro 服务端根据连接设置的对端地址 只读取该键 客户端传入的其它大小写元数据忽略
+// 对端为可信代理时使用代理追加的 X-Forwarded-For 无法获取对端地址时返回空 配置了白名单的应用拒绝调用
+func clientIP(ctx context.Context) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return gateway.ClientIP(md["Remote"], forwardedFor(md), trustedProxies)
+}
+
+// forwardedFor 可信代理追加的 X-Forwarded-For 经 gRPC 转发时键为小写
+func forwardedFor(md metadata.Metadata) string {
+	if v, ok := md["X-Forwarded-For"]; ok {
+		return v
+	}
+	return md[strings.ToLower("X-Forwarded-For")]
+}
+
+// metadataValue 获取请求元数据 忽略大小写 仅用于客户端可设置的元数据
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	for k, v := range md {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
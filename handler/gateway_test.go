@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/metadata"
+
+	appPB "github.com/lecex/pay/proto/app"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/gateway"
+	"github.com/lecex/pay/util"
+)
+
+// fakeApp 内存开发者应用仓库
+type fakeApp struct {
+	apps map[string]*appPB.App
+}
+
+func (repo *fakeApp) List(req *appPB.ListQuery) ([]*appPB.App, error) { return nil, nil }
+func (repo *fakeApp) Total(req *appPB.ListQuery) (int64, error)       { return 0, nil }
+func (repo *fakeApp) Create(app *appPB.App) error                     { return nil }
+func (repo *fakeApp) Update(app *appPB.App) error                     { return nil }
+func (repo *fakeApp) Delete(app *appPB.App) (bool, error)             { return true, nil }
+
+func (repo *fakeApp) Get(app *appPB.App) error {
+	a, ok := repo.apps[app.AppId]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*app = *a
+	return nil
+}
+
+// fakeNonce 内存请求随机字符串仓库
+type fakeNonce struct {
+	mu     sync.Mutex
+	nonces map[string]bool
+}
+
+func (repo *fakeNonce) Use(appId string, nonce string, createdAt string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.nonces[appId+"|"+nonce] {
+		return false, nil
+	}
+	repo.nonces[appId+"|"+nonce] = true
+	return true, nil
+}
+
+func (repo *fakeNonce) Clean(before string) error { return nil }
+
+func TestGateway(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	platformKey := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key))
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	platformPublicKey := base64.StdEncoding.EncodeToString(der)
+	appSecret := strings.Repeat("s", 32)
+	srv := &Gateway{
+		Trade: newTestTrade(2),
+		App: &fakeApp{apps: map[string]*appPB.App{
+			"app-1": {Id: 1, AppId: "app-1", SignType: gateway.HmacSHA256, Secret: appSecret, StoreIds: "store-0", IpAllowlist: "10.0.0.0/8", Status: true},
+			"app-2": {Id: 2, AppId: "app-2", SignType: gateway.HmacSHA256, Secret: appSecret, StoreIds: "store-0"},
+		}},
+		Nonce: &fakeNonce{nonces: map[string]bool{}},
+		Key:   platformKey,
+	}
+	trustedProxies = "172.16.0.0/12"
+	defer func() { trustedProxies = "" }()
+	// 经可信代理转发 代理追加调用方地址
+	ctx := metadata.NewContext(context.TODO(), metadata.Metadata{"Remote": "172.16.0.1:52100", "X-Forwarded-For": "8.8.8.8, 10.1.2.3"})
+	// 客户端伪造 X-Forwarded-For
+	spoofed := metadata.NewContext(context.TODO(), metadata.Metadata{"Remote": "172.16.0.1:52100", "X-Forwarded-For": "10.1.2.3, 8.8.8.8"})
+	direct := metadata.NewContext(context.TODO(), metadata.Metadata{"Remote": "8.8.8.8:52100", "X-Forwarded-For": "10.1.2.3"})
+	// 客户端伪造小写 remote
+	remote := metadata.NewContext(context.TODO(), metadata.Metadata{"remote": "10.1.2.3:52100"})
+	remoteDirect := metadata.NewContext(context.TODO(), metadata.Metadata{"Remote": "8.8.8.8:52100", "remote": "10.1.2.3:52100"})
+	seq := 0
+	request := func(appId, storeId, outTradeNo string, ts time.Time) *pb.Request {
+		seq++
+		req := &pb.Request{
+			StoreId: storeId,
+			BizContent: &pb.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "网关测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+			},
+			AppId:     appId,
+			SignType:  gateway.HmacSHA256,
+			Timestamp: strconv.FormatInt(ts.Unix(), 10),
+			Nonce:     "nonce-" + strconv.Itoa(seq),
+		}
+		req.Sign = gateway.HmacSign(appSecret, gateway.RequestContent("AopF2F", req))
+		return req
+	}
+	call := func(ctx context.Context, req *pb.Request) *pb.Response {
+		res := &pb.Response{}
+		if err := srv.AopF2F(ctx, req, res); err != nil {
+			t.Fatal(err)
+		}
+		if err := util.RsaVerify(platformPublicKey, gateway.ResponseContent(res.Content), res.Sign, gateway.RSA2); err != nil {
+			t.Fatalf("response sign: %v %+v", err, res)
+		}
+		return res
+	}
+
+	req := request("app-1", "store-0", "G1", time.Now())
+	if res := call(ctx, req); res.Content.Status != SUCCESS {
+		t.Fatalf("AopF2F = %+v", res.Content)
+	}
+	// 重放请求
+	if res := call(ctx, req); res.Content.ReturnCode != "Gateway.Nonce.Replay" {
+		t.Fatalf("replay = %+v", res.Content)
+	}
+
+	tampered := request("app-1", "store-0", "G2", time.Now())
+	tampered.BizContent.TotalFee = 1
+	forbidden := request("app-1", "store-1", "G3", time.Now())
+	for code, c := range map[string]struct {
+		ctx context.Context
+		req *pb.Request
+	}{
+		"Gateway.Sign.Invalid":                     {ctx, tampered},
+		"Gateway.StoreId.Forbidden":                {ctx, forbidden},
+		"Gateway.Timestamp.Expired":                {ctx, request("app-1", "store-0", "G4", time.Now().Add(-10*time.Minute))},
+		"Gateway.ClientIp.Forbidden":               {context.TODO(), request("app-1", "store-0", "G5", time.Now())},
+		"Gateway.ClientIp.Forbidden spoofed":       {spoofed, request("app-1", "store-0", "G9", time.Now())},
+		"Gateway.ClientIp.Forbidden direct":        {direct, request("app-1", "store-0", "G10", time.Now())},
+		"Gateway.ClientIp.Forbidden remote":        {remote, request("app-1", "store-0", "G11", time.Now())},
+		"Gateway.ClientIp.Forbidden remote direct": {remoteDirect, request("app-1", "store-0", "G12", time.Now())},
+		"Gateway.AppId.Disabled":                   {ctx, request("app-2", "store-0", "G6", time.Now())},
+		"Gateway.AppId.NotFound":                   {ctx, request("app-3", "store-0", "G7", time.Now())},
+		"Gateway.Params.Missing":                   {ctx, &pb.Request{StoreId: "store-0", BizContent: &pb.BizContent{OutTradeNo: "G8"}}},
+	} {
+		if res := call(c.ctx, c.req); res.Content.ReturnCode != strings.Fields(code)[0] {
+			t.Errorf("%s: %+v", code, res.Content)
+		}
+	}
+	// 校验失败的请求不创建订单 签名错误不占用随机字符串
+	if err := srv.Trade.Repo.StoreIdAndOutTradeNoGet(&orderPB.Order{StoreId: "store-0", OutTradeNo: "G2"}); err == nil {
+		t.Error("tampered request created order")
+	}
+	tampered.BizContent.TotalFee = 100
+	if res := call(ctx, tampered); res.Content.Status != SUCCESS {
+		t.Fatalf("AopF2F after tamper = %+v", res.Content)
+	}
+}
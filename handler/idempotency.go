@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v2/util/log"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+)
+
+// fingerprint 请求指纹 相同订单号的重复请求指纹不一致时视为冲突
+func fingerprint(api string, fields ...string) string {
+	h := sha256.Sum256([]byte(api + "\n" + strings.Join(fields, "\n")))
+	return hex.EncodeToString(h[:])
+}
+
+// aopF2FFingerprint 付款码支付请求指纹
+func aopF2FFingerprint(o *orderPB.Order) string {
+	return fingerprint("AopF2F", o.Channel, o.AuthCode, strconv.FormatInt(o.TotalFee, 10), o.OutTradeNo)
+}
+
+// prepayFingerprint 预下单请求指纹 扫码、JSAPI、网页支付共用 同一未支付订单可更换支付方式重新下单
+func prepayFingerprint(o *orderPB.Order) string {
+	return fingerprint("Prepay", o.Channel, o.Title, strconv.FormatInt(o.TotalFee, 10), o.OutTradeNo)
+}
+
+// refundFingerprint 退款请求指纹 退款金额为请求金额 0 表示退还剩余可退款金额
+func refundFingerprint(b *pb.BizContent) string {
+	return fingerprint("Refund", b.OutTradeNo, b.OutRefundNo, strconv.FormatInt(b.RefundFee, 10))
+}
+
+// conflict 已存在订单的请求指纹与本次请求不一致 未记录指纹的历史订单使用 legacy 计算
+func conflict(repoOrder *orderPB.Order, fp string, legacy func() string) bool {
+	stored := repoOrder.Fingerprint
+	if stored == "" && legacy != nil {
+		stored = legacy()
+	}
+	return stored != "" && stored != fp
+}
+
+// storedContent 已处理完成订单的最终应答 未保存应答的订单根据订单信息生成 退款订单需要传入原订单
+func storedContent(repoOrder *orderPB.Order, originalOrder *orderPB.Order) *pb.Content {
+	content := &pb.Content{}
+	if repoOrder.Response != "" && json.Unmarshal([]byte(repoOrder.Response), content) == nil {
+		return content
+	}
+	content.ReturnCode = SUCCESS
+	content.Status = SUCCESS
+	if repoOrder.Status == -1 {
+		content.Status = CLOSED
+	}
+	content.Channel = repoOrder.Channel
+	content.OutTradeNo = repoOrder.OutTradeNo
+	content.TradeNo = repoOrder.TradeNo
+	content.TotalFee = repoOrder.TotalFee
+	content.RefundFee = repoOrder.RefundFee
+	if originalOrder != nil {
+		content.OutTradeNo = originalOrder.OutTradeNo
+		content.OutRefundNo = repoOrder.OutTradeNo
+		content.TotalFee = originalOrder.TotalFee
+		content.RefundFee = -repoOrder.TotalFee
+	}
+	return content
+}
+
+// saveResponse 保存订单最终应答 重复请求时直接返回 保存失败不影响本次应答
+// 更新订单状态失败的应答不保存 由补偿任务修复订单后按订单信息生成应答
+func (srv *Trade) saveResponse(repoOrder *orderPB.Order, content *pb.Content) {
+	if content.ReturnCode != SUCCESS || (content.Status != SUCCESS && content.Status != CLOSED) {
+		return
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		log.Error(repoOrder, err)
+		return
+	}
+	repoOrder.Response = string(b)
+	if err = srv.Repo.UpdateResponse(repoOrder); err != nil {
+		log.Error(repoOrder, err)
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/clbanning/mxj"
+	"github.com/micro/go-micro/v2/util/log"
+
+	configPB "github.com/lecex/pay/proto/config"
+	pb "github.com/lecex/pay/proto/notify"
+	orderPB "github.com/lecex/pay/proto/order"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/state"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/util"
+)
+
+// Notify 异步通知
+type Notify struct {
+	Trade *Trade
+}
+
+// Alipay 支付宝异步通知
+// https://opendocs.alipay.com/open/200/106120
+func (srv *Notify) Alipay(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	return srv.notify("alipay", req, res)
+}
+
+// Wechat 微信异步通知
+// https://pay.weixin.qq.com/wiki/doc/api/native.php?chapter=9_7&index=8
+func (srv *Notify) Wechat(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	return srv.notify("wechat", req, res)
+}
+
+// Icbc 工行异步通知
+// https://open.icbc.com.cn/icbc/apip/api_detail.html?apiId=10000000000000052474
+func (srv *Notify) Icbc(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	return srv.notify("icbc", req, res)
+}
+
+// WechatRefund 微信退款异步通知
+// https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_16&index=10
+func (srv *Notify) WechatRefund(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	return srv.refundNotify("wechat", req, res)
+}
+
+// notify 处理异步通知并按通道要求应答 处理失败时通道会重复通知
+func (srv *Notify) notify(name string, req *pb.Request, res *pb.Response) (err error) {
+	res.StatusCode = 200
+	storeId := util.Get(req, "store_id")
+	con, err := srv.Trade.storeConfig(storeId)
+	if err != nil {
+		res.StatusCode = 500
+		res.Body = "fail"
+		log.Error(req, err)
+		return nil
+	}
+	channel, err := srv.Trade.channel(name, con)
+	if err != nil {
+		res.StatusCode = 500
+		res.Body = "fail"
+		log.Error(req, err)
+		return nil
+	}
+	content, err := channel.Notify(req)
+	if err == nil {
+		err = srv.hander(con, channel, content, orderEvent(state.SourceNotify, "Notify."+name, content))
+	}
+	if err != nil {
+		log.Error(req, err)
+	}
+	res.Body = channel.NotifyReply(content, err)
+	return nil
+}
+
+// hander 根据验签后的通知结果更新待付款订单
+func (srv *Notify) hander(con *configPB.Config, channel trade.Channel, content mxj.Map, e *orderPB.OrderEvent) (err error) {
+	status, _ := content["status"].(string)
+	if status != SUCCESS && status != CLOSED { // 非最终状态无需处理
+		return nil
+	}
+	outTradeNo, ok := content["out_trade_no"].(string)
+	if !ok || outTradeNo == "" {
+		return errors.New("通知缺少商户订单号")
+	}
+	repoOrder := &orderPB.Order{
+		StoreId:    con.Id,
+		OutTradeNo: outTradeNo,
+	}
+	err = srv.Trade.Repo.StoreIdAndOutTradeNoGet(repoOrder)
+	if err != nil {
+		return err
+	}
+	if repoOrder.Status != 0 {
+		// 本地已关闭的订单通道通知支付成功 撤销交易退还用户资金 撤销成功后才应答通知
+		if status == SUCCESS && repoOrder.Status == -1 {
+			return srv.reverse(channel, repoOrder)
+		}
+		return nil // 订单已处理 重复通知直接返回成功
+	}
+	switch status {
+	case SUCCESS:
+		if totalFee, _ := content["total_fee"].(int64); totalFee != repoOrder.TotalFee {
+			return fmt.Errorf("通知金额%d与订单金额%d不符", totalFee, repoOrder.TotalFee)
+		}
+		tradeNo, ok := content["trade_no"].(string)
+		if !ok || tradeNo == "" {
+			return errors.New("通知缺少通道交易号")
+		}
+		repoOrder.TradeNo = tradeNo
+		err = srv.Trade.successOrder(con, repoOrder, e)
+	case CLOSED:
+		err = srv.Trade.closeOrder(con, repoOrder, state.Closed, e)
+	}
+	if err != nil {
+		return errors.New("更新订单状态失败:" + err.Error())
+	}
+	return nil
+}
+
+// reverse 撤销本地已关闭但通道支付成功的交易 撤销失败时不应答通知 通道重新通知时再次撤销
+func (srv *Notify) reverse(channel trade.Channel, repoOrder *orderPB.Order) error {
+	content, err := channel.Cancel(&tradePB.BizContent{
+		Channel:    repoOrder.Channel,
+		OutTradeNo: repoOrder.OutTradeNo,
+	})
+	if err != nil {
+		return errors.New("撤销已关闭订单的支付失败:" + err.Error())
+	}
+	if content["return_code"] != "SUCCESS" {
+		return fmt.Errorf("撤销已关闭订单的支付失败:%v", content["return_msg"])
+	}
+	log.Log(repoOrder, "订单已关闭 通道通知支付成功 已撤销交易")
+	return nil
+}
+
+// refundNotify 处理退款异步通知并按通道要求应答 处理失败时通道会重复通知
+func (srv *Notify) refundNotify(name string, req *pb.Request, res *pb.Response) (err error) {
+	res.StatusCode = 200
+	storeId := util.Get(req, "store_id")
+	con, err := srv.Trade.storeConfig(storeId)
+	if err != nil {
+		res.StatusCode = 500
+		res.Body = "fail"
+		log.Error(req, err)
+		return nil
+	}
+	channel, err := srv.Trade.channel(name, con)
+	if err != nil {
+		res.StatusCode = 500
+		res.Body = "fail"
+		log.Error(req, err)
+		return nil
+	}
+	notifier, ok := channel.(trade.RefundNotifier)
+	if !ok {
+		res.StatusCode = 500
+		res.Body = "fail"
+		log.Error(req, name+"通道不支持退款异步通知")
+		return nil
+	}
+	content, err := notifier.RefundNotify(req)
+	if err == nil {
+		err = srv.refundHander(con, content, orderEvent(state.SourceNotify, "Notify."+name+"Refund", content))
+	}
+	if err != nil {
+		log.Error(req, err)
+	}
+	res.Body = channel.NotifyReply(content, err)
+	return nil
+}
+
+// refundHander 根据退款通知结果更新退款订单 并在同一事务中重新计算原订单退款金额
+// 退款接口受理后订单已按成功处理 通知退款失败时关闭退款订单
+func (srv *Notify) refundHander(con *configPB.Config, content mxj.Map, e *orderPB.OrderEvent) (err error) {
+	status, _ := content["status"].(string)
+	if status != SUCCESS && status != CLOSED { // 非最终状态无需处理
+		return nil
+	}
+	outRefundNo, ok := content["out_refund_no"].(string)
+	if !ok || outRefundNo == "" {
+		return errors.New("通知缺少退款订单号")
+	}
+	refundOrder := &orderPB.Order{
+		StoreId:    con.Id,
+		OutTradeNo: outRefundNo,
+	}
+	err = srv.Trade.Repo.StoreIdAndOutTradeNoGet(refundOrder)
+	if err != nil {
+		return err
+	}
+	if refundOrder.TotalFee >= 0 || refundOrder.LinkId == "" {
+		return fmt.Errorf("订单%s不是退款订单", refundOrder.OutTradeNo)
+	}
+	if (status == SUCCESS && refundOrder.Status == 1) || (status == CLOSED && refundOrder.Status == -1) { // 重复通知直接返回成功
+		return nil
+	}
+	originalOrder := &orderPB.Order{Id: refundOrder.LinkId}
+	switch status {
+	case SUCCESS:
+		if refundFee, _ := content["refund_fee"].(int64); refundFee != -refundOrder.TotalFee {
+			return fmt.Errorf("通知退款金额%d与退款订单金额%d不符", refundFee, -refundOrder.TotalFee)
+		}
+		if tradeNo, _ := content["trade_no"].(string); tradeNo != "" {
+			refundOrder.TradeNo = tradeNo
+		}
+		err = srv.Trade.successRefund(con, refundOrder, originalOrder, e)
+	case CLOSED:
+		err = srv.Trade.closeRefund(con, refundOrder, originalOrder, e)
+	}
+	if err != nil {
+		return errors.New("更新退款订单状态失败:" + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/clbanning/mxj"
+
+	notifyPB "github.com/lecex/pay/proto/notify"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/state"
+)
+
+func TestNotifySuccessOrder(t *testing.T) {
+	h := newTestTrade(1)
+	h.AopF2F(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "通知测试",
+			TotalFee:   100,
+			OutTradeNo: "PN0001",
+		},
+	}, &pb.Response{})
+	req := &notifyPB.Request{Get: map[string]*notifyPB.Pair{
+		"store_id":     {Key: "store_id", Values: []string{"store-0"}},
+		"out_trade_no": {Key: "out_trade_no", Values: []string{"PN0001"}},
+	}}
+	res := &notifyPB.Response{}
+	n := &Notify{h}
+	if err := n.notify("fake", req, res); err != nil || res.Body != "success" {
+		t.Fatal(res, err)
+	}
+	order := &orderPB.Order{StoreId: "store-0", OutTradeNo: "PN0001"}
+	h.Repo.StoreIdAndOutTradeNoGet(order)
+	if order.Status != 1 || order.TradeNo == "" {
+		t.Fatalf("order not updated: %+v", order)
+	}
+}
+
+func TestNotifyClosedOrder(t *testing.T) {
+	h := newTestTrade(1)
+	n := &Notify{h}
+	for _, c := range []struct{ outTradeNo, body string }{{"PN0002", "success"}, {"PN0003_FAIL", "fail"}} {
+		h.AopF2F(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "通知测试",
+				TotalFee:   100,
+				OutTradeNo: c.outTradeNo,
+			},
+		}, &pb.Response{})
+		order := &orderPB.Order{StoreId: "store-0", OutTradeNo: c.outTradeNo}
+		h.Repo.StoreIdAndOutTradeNoGet(order)
+		if err := h.closeOrder(h.Config.(*fakeConfig).configs["store-0"], order, state.Closed, &orderPB.OrderEvent{}); err != nil {
+			t.Fatal(err)
+		}
+		// 已关闭订单通知支付成功 撤销交易成功后才应答成功
+		req := &notifyPB.Request{Get: map[string]*notifyPB.Pair{
+			"store_id":     {Key: "store_id", Values: []string{"store-0"}},
+			"out_trade_no": {Key: "out_trade_no", Values: []string{c.outTradeNo}},
+		}}
+		res := &notifyPB.Response{}
+		if err := n.notify("fake", req, res); err != nil || res.Body != c.body {
+			t.Fatalf("%s: %+v %v", c.outTradeNo, res, err)
+		}
+		h.Repo.StoreIdAndOutTradeNoGet(order)
+		if order.Status != -1 {
+			t.Fatalf("%s: order reopened %+v", c.outTradeNo, order)
+		}
+	}
+}
+
+func TestNotifyWechatRefund(t *testing.T) {
+	h := newTestTrade(1)
+	h.AopF2F(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "退款通知测试",
+			TotalFee:   100,
+			OutTradeNo: "RN0001",
+		},
+	}, &pb.Response{})
+	original := &orderPB.Order{StoreId: "store-0", OutTradeNo: "RN0001"}
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	// 退款处理中的退款订单
+	h.Repo.Create(&orderPB.Order{StoreId: "store-0", Channel: "fake", OutTradeNo: "RN0001_R", TotalFee: -40, LinkId: original.Id})
+
+	con, _ := h.storeConfig("store-0")
+	n := &Notify{h}
+	content := mxj.Map{"status": SUCCESS, "out_refund_no": "RN0001_R", "refund_fee": int64(50), "trade_no": "5000"}
+	if err := n.refundHander(con, content, &orderPB.OrderEvent{}); err == nil {
+		t.Fatal("refund fee mismatch passed")
+	}
+	content["refund_fee"] = int64(40)
+	for i := 0; i < 2; i++ { // 重复通知
+		if err := n.refundHander(con, content, &orderPB.OrderEvent{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	refund := &orderPB.Order{StoreId: "store-0", OutTradeNo: "RN0001_R"}
+	h.Repo.StoreIdAndOutTradeNoGet(refund)
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	if refund.Status != 1 || refund.TradeNo != "5000" || original.RefundFee != 40 {
+		t.Fatalf("refund not updated: %+v %+v", refund, original)
+	}
+
+	// 退款失败关闭退款订单 原订单退款金额回退
+	content["status"] = CLOSED
+	if err := n.refundHander(con, content, &orderPB.OrderEvent{}); err != nil {
+		t.Fatal(err)
+	}
+	h.Repo.StoreIdAndOutTradeNoGet(refund)
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	if refund.Status != -1 || original.RefundFee != 0 {
+		t.Fatalf("refund not closed: %+v %+v", refund, original)
+	}
+}
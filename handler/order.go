@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/micro/go-micro/v2/util/log"
+
 	pb "github.com/lecex/pay/proto/order"
 	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/state"
+	"github.com/lecex/pay/service/worker"
 )
 
 // Order 订单
 type Order struct {
 	Repo repository.Order
+	Log  repository.ChannelLog
 }
 
 // Amount 查询总和
@@ -57,6 +62,8 @@ func (srv *Order) Get(ctx context.Context, req *pb.Request, res *pb.Response) (e
 
 // Create 创建订单
 func (srv *Order) Create(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	req.Order.State = state.Current(req.Order)
+	req.Order.Status = state.Status(req.Order.State)
 	err = srv.Repo.Create(req.Order)
 	if err != nil {
 		res.Valid = false
@@ -67,17 +74,100 @@ func (srv *Order) Create(ctx context.Context, req *pb.Request, res *pb.Response)
 	return err
 }
 
-// Update 更新订单
+// Update 更新订单 订单状态变更需要符合状态流转规则并记录变更
 func (srv *Order) Update(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
-	err = srv.Repo.Update(req.Order)
+	if req.Order.Id == "" {
+		return fmt.Errorf("请传入更新id")
+	}
+	err = srv.Repo.Transaction(func(tx repository.Order) error {
+		current := &pb.Order{Id: req.Order.Id}
+		if err := tx.LockGet(current); err != nil {
+			return err
+		}
+		to := state.Current(current)
+		if req.Order.State != "" {
+			to = req.Order.State
+		} else if req.Order.Status != current.Status {
+			to = state.Current(&pb.Order{Status: req.Order.Status, TotalFee: current.TotalFee, RefundFee: current.RefundFee})
+		}
+		req.Order.State = current.State
+		req.Order.Status = current.Status
+		_, err := transit(tx, req.Order, to, &pb.OrderEvent{
+			Source: state.SourceRPC,
+			Actor:  "Orders.Update",
+		})
+		return err
+	})
 	if err != nil {
 		res.Valid = false
-		return fmt.Errorf("更新订单失败")
+		return fmt.Errorf("更新订单失败:%s", err.Error())
 	}
 	res.Order = req.Order
 	return err
 }
 
+// Events 获取订单状态变更记录 根据订单ID或商户ID和订单编号查询
+func (srv *Order) Events(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.Order == nil {
+		return fmt.Errorf("请传入订单")
+	}
+	if req.Order.Id == "" {
+		if req.Order.StoreId == "" || req.Order.OutTradeNo == "" {
+			return fmt.Errorf("订单ID或商户ID和订单编号不允许为空:Id,StoreId,OutTradeNo")
+		}
+		if err = srv.Repo.StoreIdAndOutTradeNoGet(req.Order); err != nil {
+			return err
+		}
+	}
+	events, err := srv.Repo.Events(req.Order)
+	if err != nil {
+		return err
+	}
+	res.Order = req.Order
+	res.Events = events
+	res.Total = int64(len(events))
+	return nil
+}
+
+// ChannelLogs 获取订单通道请求记录 根据订单ID或商户ID和订单编号查询
+func (srv *Order) ChannelLogs(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.Order == nil {
+		return fmt.Errorf("请传入订单")
+	}
+	if req.Order.Id != "" {
+		err = srv.Repo.Get(req.Order)
+	} else if req.Order.StoreId != "" && req.Order.OutTradeNo != "" {
+		err = srv.Repo.StoreIdAndOutTradeNoGet(req.Order)
+	} else {
+		return fmt.Errorf("订单ID或商户ID和订单编号不允许为空:Id,StoreId,OutTradeNo")
+	}
+	if err != nil {
+		return err
+	}
+	logs, err := srv.Log.List(req.Order)
+	if err != nil {
+		return err
+	}
+	res.Order = req.Order
+	res.ChannelLogs = logs
+	res.Total = int64(len(logs))
+	return nil
+}
+
+// Record 记录通道请求 根据商户订单号关联订单 记录失败不影响支付
+func (srv *Order) Record(l *pb.ChannelLog) {
+	if l.StoreId != "" && l.OutTradeNo != "" {
+		order := &pb.Order{StoreId: l.StoreId, OutTradeNo: l.OutTradeNo}
+		if err := srv.Repo.StoreIdAndOutTradeNoGet(order); err == nil {
+			l.OrderId = order.Id
+		}
+	}
+	l.Error = worker.Truncate(l.Error, 255)
+	if err := srv.Log.Create(l); err != nil {
+		log.Error(l, err)
+	}
+}
+
 // Delete 删除订单订单
 func (srv *Order) Delete(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
 	valid, err := srv.Repo.Delete(req.Order)
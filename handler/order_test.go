@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/state"
+)
+
+func TestOrderEvents(t *testing.T) {
+	h := newTestTrade(1)
+	o := &Order{Repo: h.Repo}
+	h.AopF2F(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "状态测试",
+			TotalFee:   100,
+			OutTradeNo: "E0001",
+		},
+	}, &pb.Response{})
+	res := &pb.Response{}
+	h.Refund(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			OutTradeNo:  "E0001",
+			OutRefundNo: "E0001_R",
+			RefundFee:   40,
+		},
+	}, res)
+	if res.Content.Status != SUCCESS {
+		t.Fatalf("Refund: %+v", res.Content)
+	}
+	// 部分退款的订单不允许撤销
+	res = &pb.Response{}
+	h.Cancel(context.TODO(), &pb.Request{
+		StoreId:    "store-0",
+		BizContent: &pb.BizContent{OutTradeNo: "E0001"},
+	}, res)
+	if res.Content.ReturnCode != "Cancel.State" {
+		t.Fatalf("Cancel: %+v", res.Content)
+	}
+
+	r := &orderPB.Response{}
+	err := o.Events(context.TODO(), &orderPB.Request{Order: &orderPB.Order{StoreId: "store-0", OutTradeNo: "E0001"}}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{
+		{"", state.Created},
+		{state.Created, state.Success},
+		{state.Success, state.Refunding},
+		{state.Refunding, state.PartiallyRefunded},
+	}
+	if len(r.Events) != len(want) {
+		t.Fatalf("events = %+v", r.Events)
+	}
+	for i, e := range r.Events {
+		if e.FromState != want[i][0] || e.ToState != want[i][1] || e.Source != state.SourceRPC {
+			t.Errorf("event %d = %+v, want %v", i, e, want[i])
+		}
+	}
+	if r.Events[1].Actor != "Trade.AopF2F" || r.Events[1].Payload == "" {
+		t.Errorf("success event = %+v", r.Events[1])
+	}
+
+	// 订单更新不允许非法状态流转
+	order := r.Order
+	order.Status = -1
+	order.State = ""
+	if err = o.Update(context.TODO(), &orderPB.Request{Order: order}, &orderPB.Response{}); err == nil {
+		t.Fatal("Update PARTIALLY_REFUNDED -> CLOSED should fail")
+	}
+	order.State = state.Refunded
+	if err = o.Update(context.TODO(), &orderPB.Request{Order: order}, &orderPB.Response{}); err != nil {
+		t.Fatal(err)
+	}
+	r = &orderPB.Response{}
+	o.Events(context.TODO(), &orderPB.Request{Order: &orderPB.Order{Id: order.Id}}, r)
+	if e := r.Events[len(r.Events)-1]; e.ToState != state.Refunded || e.Actor != "Orders.Update" {
+		t.Fatalf("update event = %+v", e)
+	}
+}
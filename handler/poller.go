@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"sync"
+)
+
+// Poller 付款码支付后台轮询 同一订单同时只运行一个轮询
+// Close 后轮询立即退出 未完成的订单由订单同步继续处理
+type Poller struct {
+	mu     sync.Mutex
+	orders map[string]bool
+	stop   chan struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPoller 创建后台轮询
+func NewPoller() *Poller {
+	return &Poller{
+		orders: map[string]bool{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// Running 订单是否正在轮询
+func (p *Poller) Running(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.orders[id]
+}
+
+// Start 订单没有正在运行的轮询时后台运行 fn stop 关闭时 fn 需要退出
+// 订单已在轮询或已关闭时不运行 返回 false
+func (p *Poller) Start(id string, fn func(stop <-chan struct{})) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || p.orders[id] {
+		return false
+	}
+	p.orders[id] = true
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			delete(p.orders, id)
+			p.mu.Unlock()
+			p.wg.Done()
+		}()
+		fn(p.stop)
+	}()
+	return true
+}
+
+// Close 通知全部轮询退出并等待退出完成
+func (p *Poller) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.stop)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
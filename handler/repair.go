@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/clbanning/mxj"
+	"github.com/micro/go-micro/v2/util/log"
+
+	configPB "github.com/lecex/pay/proto/config"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/repair"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/state"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 补偿配置 通道处理成功但更新订单状态失败时写入补偿队列 按退避间隔重试 最多 PAY_REPAIR_MAX_ATTEMPTS 次
+var (
+	repairInterval    = time.Duration(envInt("PAY_REPAIR_INTERVAL", 30)) * time.Second
+	repairBackoff     = time.Duration(envInt("PAY_REPAIR_BACKOFF", 30)) * time.Second
+	repairMaxBackoff  = time.Hour
+	repairMaxAttempts = int64(envInt("PAY_REPAIR_MAX_ATTEMPTS", 10))
+)
+
+// 补偿状态
+const (
+	repairFailed   = -1 // 补偿失败 已达最大重试次数 需人工处理
+	repairPending  = 0  // 待补偿
+	repairResolved = 1  // 已修复
+)
+
+// Repair 订单补偿
+type Repair struct {
+	Trade *Trade
+	Repo  repository.Repair
+}
+
+// List 获取补偿记录列表
+func (srv *Repair) List(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.ListQuery == nil {
+		req.ListQuery = &pb.ListQuery{}
+	}
+	repairs, err := srv.Repo.List(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	total, err := srv.Repo.Total(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	res.Total = total
+	res.Repairs = repairs
+	return err
+}
+
+// Retry 立即重试补偿 补偿失败的记录重试成功后标记为已修复
+func (srv *Repair) Retry(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.Repair == nil || req.Repair.Id == 0 {
+		return errors.New("请传入补偿记录id")
+	}
+	if err = srv.Repo.Get(req.Repair); err != nil {
+		return err
+	}
+	if req.Repair.Status == repairResolved {
+		return errors.New("补偿记录已修复")
+	}
+	// 抢占后重试 避免与其它实例的定时补偿同时处理
+	ok, err := srv.Repo.Claim(req.Repair, worker.Format(time.Now().Add(repairBackoff)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("补偿记录正在处理 请稍后重试")
+	}
+	err = srv.Resolve(req.Repair)
+	res.Repair = req.Repair
+	res.Valid = err == nil
+	return err
+}
+
+// Run 定时处理到期补偿 stop 关闭后退出
+func (srv *Repair) Run(stop <-chan struct{}) {
+	worker.Run(repairInterval, stop, srv.Scan)
+}
+
+// Scan 处理一批到期补偿 抢占期间其它实例不会重复补偿 补偿中断时到期后会重新补偿
+func (srv *Repair) Scan() {
+	queue := &worker.Queue{
+		Lease: repairBackoff,
+		Limit: 100,
+		Due: func(now string, limit int64) ([]interface{}, error) {
+			repairs, err := srv.Repo.Due(now, limit)
+			tasks := make([]interface{}, len(repairs))
+			for i, r := range repairs {
+				tasks[i] = r
+			}
+			return tasks, err
+		},
+		Claim: func(task interface{}, nextAt string) (bool, error) {
+			return srv.Repo.Claim(task.(*pb.Repair), nextAt)
+		},
+		Handle: func(task interface{}) error {
+			return srv.Resolve(task.(*pb.Repair))
+		},
+	}
+	queue.Scan()
+}
+
+// Resolve 补偿一次并记录补偿结果 失败时计算下次补偿时间
+func (srv *Repair) Resolve(r *pb.Repair) (err error) {
+	resolveErr := srv.resolve(r)
+	r.Attempts++
+	switch {
+	case resolveErr == nil:
+		r.Status = repairResolved
+		r.Error = ""
+	case r.Attempts >= repairMaxAttempts:
+		r.Status = repairFailed
+		r.Error = worker.Truncate(resolveErr.Error(), 255)
+	default:
+		r.Status = repairPending
+		r.Error = worker.Truncate(resolveErr.Error(), 255)
+		r.NextAt = worker.Format(time.Now().Add(worker.Backoff(repairBackoff, repairMaxBackoff, r.Attempts)))
+	}
+	if err = srv.Repo.Update(r); err != nil {
+		return err
+	}
+	return resolveErr
+}
+
+// resolve 按记录的通道处理结果重新更新订单状态 仍失败时向支付通道查询订单最新状态后更新
+func (srv *Repair) resolve(r *pb.Repair) error {
+	con, repoOrder, originalOrder, err := srv.orders(r)
+	if err != nil {
+		return err
+	}
+	e := &orderPB.OrderEvent{Source: state.SourceRepair, Actor: "Repairs.Update", Payload: r.Payload}
+	updateErr := srv.Trade.settle(con, repoOrder, originalOrder, r.ToState, r.TradeNo, e)
+	if updateErr == nil {
+		return nil
+	}
+	// 更新失败后订单内容已被修改 重新获取订单
+	con, repoOrder, originalOrder, err = srv.orders(r)
+	if err != nil {
+		return err
+	}
+	channel, err := srv.Trade.channel(repoOrder.Channel, con)
+	if err != nil {
+		return err
+	}
+	to, content, err := query(channel, repoOrder, originalOrder)
+	if err != nil {
+		return fmt.Errorf("更新订单状态失败:%s,通道查询失败:%s", updateErr.Error(), err.Error())
+	}
+	tradeNo, _ := content["trade_no"].(string)
+	err = srv.Trade.settle(con, repoOrder, originalOrder, to, tradeNo, orderEvent(state.SourceRepair, "Repairs.Query", content))
+	if err != nil {
+		return fmt.Errorf("通道订单状态%s,更新订单状态失败:%s", to, err.Error())
+	}
+	return nil
+}
+
+// orders 获取补偿订单和商户配置 退款订单同时获取原订单
+func (srv *Repair) orders(r *pb.Repair) (con *configPB.Config, repoOrder *orderPB.Order, originalOrder *orderPB.Order, err error) {
+	repoOrder = &orderPB.Order{Id: r.OrderId}
+	if err = srv.Trade.Repo.Get(repoOrder); err != nil {
+		return nil, nil, nil, fmt.Errorf("获取订单失败:%s", err.Error())
+	}
+	if repoOrder.LinkId != "" {
+		originalOrder = &orderPB.Order{Id: repoOrder.LinkId}
+		if err = srv.Trade.Repo.Get(originalOrder); err != nil {
+			return nil, nil, nil, fmt.Errorf("获取原订单失败:%s", err.Error())
+		}
+	}
+	con, err = srv.Trade.storeConfig(repoOrder.StoreId)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("查询商户支付配置信息失败:%s", err.Error())
+	}
+	return con, repoOrder, originalOrder, nil
+}
+
+// query 向支付通道查询订单 返回通道处理结果对应的订单状态
+func query(channel trade.Channel, repoOrder *orderPB.Order, originalOrder *orderPB.Order) (string, mxj.Map, error) {
+	if originalOrder != nil {
+		content, err := channel.RefundQuery(&tradePB.BizContent{
+			Channel:     repoOrder.Channel,
+			OutTradeNo:  originalOrder.OutTradeNo,
+			OutRefundNo: repoOrder.OutTradeNo,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		switch trade.RefundStatus(content) {
+		case SUCCESS:
+			return state.Success, content, nil
+		case CLOSED:
+			return state.Closed, content, nil
+		}
+		return "", content, fmt.Errorf("通道退款状态:%v %v", content["status"], content["return_msg"])
+	}
+	content, err := channel.Query(&tradePB.BizContent{
+		Channel:    repoOrder.Channel,
+		OutTradeNo: repoOrder.OutTradeNo,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if content["return_code"] != "SUCCESS" {
+		return "", content, fmt.Errorf("%v", content["return_msg"])
+	}
+	switch content["status"] {
+	case SUCCESS:
+		return state.Success, content, nil
+	case CLOSED:
+		return state.Closed, content, nil
+	}
+	return "", content, fmt.Errorf("通道订单状态:%v", content["status"])
+}
+
+// settle 按通道处理结果更新订单状态 退款订单需要传入原订单
+func (srv *Trade) settle(con *configPB.Config, repoOrder *orderPB.Order, originalOrder *orderPB.Order, to string, tradeNo string, e *orderPB.OrderEvent) error {
+	if originalOrder != nil {
+		switch to {
+		case state.Success:
+			return srv.successRefund(con, repoOrder, originalOrder, e)
+		case state.Closed:
+			return srv.closeRefund(con, repoOrder, originalOrder, e)
+		}
+		return fmt.Errorf("退款订单不支持补偿为%s", to)
+	}
+	switch to {
+	case state.Success:
+		if tradeNo != "" {
+			repoOrder.TradeNo = tradeNo
+		}
+		return srv.successOrder(con, repoOrder, e)
+	case state.Closed, state.Reversed:
+		return srv.closeOrder(con, repoOrder, to, e)
+	}
+	return fmt.Errorf("订单不支持补偿为%s", to)
+}
+
+// repair 通道处理成功但更新订单状态失败 写入补偿队列由后台任务重试 写入失败不影响本次应答
+func (srv *Trade) repair(repoOrder *orderPB.Order, to string, content mxj.Map, cause error) {
+	log.Error(repoOrder, cause)
+	if srv.Repair == nil {
+		return
+	}
+	r := &pb.Repair{
+		StoreId:    repoOrder.StoreId,
+		OrderId:    repoOrder.Id,
+		OutTradeNo: repoOrder.OutTradeNo,
+		Channel:    repoOrder.Channel,
+		ToState:    to,
+		Status:     repairPending,
+		Error:      worker.Truncate(cause.Error(), 255),
+		NextAt:     worker.Format(time.Now().Add(repairBackoff)),
+	}
+	r.TradeNo, _ = content["trade_no"].(string)
+	if c, ok := content["content"].(mxj.Map); ok {
+		j, _ := c.Json()
+		r.Payload = string(j)
+	}
+	if err := srv.Repair.Create(r); err != nil {
+		log.Error(r, err)
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/repair"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/state"
+)
+
+// fakeRepair 内存补偿仓库
+type fakeRepair struct {
+	mu      sync.Mutex
+	repairs []*pb.Repair
+}
+
+func (repo *fakeRepair) List(req *pb.ListQuery) (repairs []*pb.Repair, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, r := range repo.repairs {
+		if req.Repair == nil || req.Repair.Status == r.Status {
+			repairs = append(repairs, r)
+		}
+	}
+	return repairs, nil
+}
+func (repo *fakeRepair) Total(req *pb.ListQuery) (int64, error) {
+	repairs, err := repo.List(req)
+	return int64(len(repairs)), err
+}
+func (repo *fakeRepair) Create(repair *pb.Repair) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repair.Id = int64(len(repo.repairs) + 1)
+	repo.repairs = append(repo.repairs, repair)
+	return nil
+}
+func (repo *fakeRepair) Update(repair *pb.Repair) error { return nil }
+func (repo *fakeRepair) Get(repair *pb.Repair) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	*repair = *repo.repairs[repair.Id-1]
+	return nil
+}
+func (repo *fakeRepair) Due(now string, limit int64) ([]*pb.Repair, error) { return nil, nil }
+func (repo *fakeRepair) Claim(repair *pb.Repair, nextAt string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	r := repo.repairs[repair.Id-1]
+	if r.Status != repair.Status || r.Attempts != repair.Attempts || r.NextAt != repair.NextAt {
+		return false, nil
+	}
+	r.NextAt, repair.NextAt = nextAt, nextAt
+	return true, nil
+}
+
+// failingOrder 模拟数据库故障 fail 为 true 时订单更新失败
+type failingOrder struct {
+	*fakeOrder
+	fail bool
+}
+
+func (repo *failingOrder) Update(order *orderPB.Order) error {
+	if repo.fail {
+		return errors.New("数据库连接失败")
+	}
+	return repo.fakeOrder.Update(order)
+}
+
+func (repo *failingOrder) Transaction(fn func(tx repository.Order) error) error {
+	return repo.fakeOrder.Transaction(func(repository.Order) error { return fn(repo) })
+}
+
+func TestRepair(t *testing.T) {
+	h := newTestTrade(1)
+	repo := &failingOrder{fakeOrder: h.Repo.(*fakeOrder)}
+	h.Repo = repo
+	h.Repair = &fakeRepair{}
+	srv := &Repair{Trade: h, Repo: h.Repair}
+	aopF2F := func(outTradeNo string) *tradePB.Content {
+		res := &tradePB.Response{}
+		h.AopF2F(context.TODO(), &tradePB.Request{
+			StoreId: "store-0",
+			BizContent: &tradePB.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "补偿测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+			},
+		}, res)
+		return res.Content
+	}
+	repairs := func(status int64) []*pb.Repair {
+		res := &pb.Response{}
+		srv.List(context.TODO(), &pb.Request{ListQuery: &pb.ListQuery{Repair: &pb.Repair{Status: status}}}, res)
+		return res.Repairs
+	}
+	order := func(outTradeNo string) *orderPB.Order {
+		o := &orderPB.Order{StoreId: "store-0", OutTradeNo: outTradeNo}
+		repo.StoreIdAndOutTradeNoGet(o)
+		return o
+	}
+
+	// 通道支付成功但更新订单失败 写入补偿队列
+	repo.fail = true
+	if content := aopF2F("R1"); content.ReturnCode != "AopF2.Update.Success" || content.Status != WAITING {
+		t.Fatalf("AopF2F: %+v", content)
+	}
+	pending := repairs(repairPending)
+	if len(pending) != 1 || pending[0].ToState != state.Success || pending[0].TradeNo != "key-store-0|R1" {
+		t.Fatalf("repairs = %+v", pending)
+	}
+	repo.fail = false
+	if err := srv.Resolve(pending[0]); err != nil {
+		t.Fatal(err)
+	}
+	if o := order("R1"); o.State != state.Success || o.TradeNo != "key-store-0|R1" {
+		t.Fatalf("order = %+v", o)
+	}
+	if pending[0].Status != repairResolved {
+		t.Fatalf("repair = %+v", pending[0])
+	}
+
+	// 记录的处理结果已不能应用时以通道查询结果为准
+	r := &pb.Repair{OrderId: order("R1").Id, ToState: state.Closed}
+	h.Repair.Create(r)
+	if err := srv.Resolve(r); err != nil || r.Status != repairResolved {
+		t.Fatalf("Resolve by query: %v %+v", err, r)
+	}
+	if o := order("R1"); o.State != state.Success {
+		t.Fatalf("order = %+v", o)
+	}
+
+	// 超过最大重试次数后等待人工处理
+	repo.fail = true
+	aopF2F("R2")
+	r = repairs(repairPending)[0]
+	for i := int64(0); i < repairMaxAttempts; i++ {
+		srv.Resolve(r)
+	}
+	if failed := repairs(repairFailed); len(failed) != 1 || failed[0].OrderId != order("R2").Id || failed[0].Error == "" {
+		t.Fatalf("failed repairs = %+v", failed)
+	}
+	repo.fail = false
+	res := &pb.Response{}
+	if err := srv.Retry(context.TODO(), &pb.Request{Repair: &pb.Repair{Id: r.Id}}, res); err != nil || !res.Valid {
+		t.Fatalf("Retry: %v %+v", err, res.Repair)
+	}
+	if o := order("R2"); o.State != state.Success {
+		t.Fatalf("order = %+v", o)
+	}
+	// 其它实例已抢占时手动重试返回错误
+	repo.fail = true
+	aopF2F("R3")
+	repo.fail = false
+	r = repairs(repairPending)[0]
+	srv.Repo = &claimedRepair{h.Repair.(*fakeRepair)}
+	if err := srv.Retry(context.TODO(), &pb.Request{Repair: &pb.Repair{Id: r.Id}}, res); err == nil {
+		t.Fatal("want claimed error")
+	}
+	if o := order("R3"); o.State == state.Success {
+		t.Fatalf("order resolved while claimed: %+v", o)
+	}
+}
+
+// claimedRepair 查询补偿记录后其它实例立即抢占
+type claimedRepair struct {
+	*fakeRepair
+}
+
+func (repo *claimedRepair) Get(repair *pb.Repair) error {
+	repo.fakeRepair.Get(repair)
+	r := *repair
+	repo.fakeRepair.Claim(&r, "2099-01-01 00:00:00")
+	return nil
+}
@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/micro/go-micro/v2/util/log"
+
+	configPB "github.com/lecex/pay/proto/config"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/sharing"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/sharing"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 分账配置 支付成功 PAY_SHARING_DELAY 秒后分账 失败后按退避间隔重试 最多 PAY_SHARING_MAX_ATTEMPTS 次
+var (
+	sharingDelay       = time.Duration(envInt("PAY_SHARING_DELAY", 60)) * time.Second
+	sharingInterval    = time.Duration(envInt("PAY_SHARING_INTERVAL", 30)) * time.Second
+	sharingBackoff     = time.Duration(envInt("PAY_SHARING_BACKOFF", 60)) * time.Second
+	sharingMaxBackoff  = time.Hour * 6
+	sharingMaxAttempts = int64(envInt("PAY_SHARING_MAX_ATTEMPTS", 8))
+)
+
+// sharingRefundCancelled 退款取消待分账明细的失败原因 退款关闭后恢复分账
+const sharingRefundCancelled = "订单退款取消分账"
+
+// Sharing 分账
+type Sharing struct {
+	Trade *Trade
+	Repo  repository.Sharing
+}
+
+// Rules 获取商户分账规则
+func (srv *Sharing) Rules(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.StoreId == "" {
+		return errors.New("商户ID不允许为空:StoreId")
+	}
+	res.Rules, err = srv.Repo.Rules(req.StoreId)
+	if err != nil {
+		return err
+	}
+	res.Total = int64(len(res.Rules))
+	return nil
+}
+
+// SetRules 设置商户分账规则 覆盖商户原有规则 已创建的分账明细不受影响
+func (srv *Sharing) SetRules(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.StoreId == "" {
+		return errors.New("商户ID不允许为空:StoreId")
+	}
+	if err = sharing.Validate(req.Rules); err != nil {
+		return err
+	}
+	if err = srv.Repo.SaveRules(req.StoreId, req.Rules); err != nil {
+		return err
+	}
+	res.Rules = req.Rules
+	res.Total = int64(len(req.Rules))
+	res.Valid = true
+	return nil
+}
+
+// List 获取分账明细列表
+func (srv *Sharing) List(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.ListQuery == nil {
+		req.ListQuery = &pb.ListQuery{}
+	}
+	splits, err := srv.Repo.List(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	total, err := srv.Repo.Total(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	res.Total = total
+	res.Splits = splits
+	return err
+}
+
+// Query 根据商户订单号查询订单分账明细
+func (srv *Sharing) Query(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.StoreId == "" || req.OutTradeNo == "" {
+		return errors.New("商户ID和订单编号不允许为空:StoreId,OutTradeNo")
+	}
+	res.Splits, err = srv.Repo.Splits(&pb.SharingSplit{StoreId: req.StoreId, OutTradeNo: req.OutTradeNo})
+	if err != nil {
+		return err
+	}
+	res.Total = int64(len(res.Splits))
+	res.Valid = true
+	return nil
+}
+
+// Run 定时处理到期分账 stop 关闭后退出
+func (srv *Sharing) Run(stop <-chan struct{}) {
+	worker.Run(sharingInterval, stop, srv.Scan)
+}
+
+// Scan 处理一批到期分账 同一订单的接收方一次分账
+// 抢占期间其它实例不会重复分账 分账中断时到期后会重新分账
+func (srv *Sharing) Scan() {
+	queue := &worker.Queue{
+		Lease: sharingBackoff,
+		Limit: 100,
+		Due: func(now string, limit int64) ([]interface{}, error) {
+			splits, err := srv.Repo.Due(now, limit)
+			tasks := []interface{}{}
+			orders := map[string]bool{}
+			for _, split := range splits {
+				if !orders[split.OrderId] {
+					orders[split.OrderId] = true
+					tasks = append(tasks, split)
+				}
+			}
+			return tasks, err
+		},
+		Claim: func(task interface{}, nextAt string) (bool, error) {
+			return srv.Repo.Claim(task.(*pb.SharingSplit), nextAt)
+		},
+		Handle: func(task interface{}) error {
+			return srv.Split(task.(*pb.SharingSplit).OrderId)
+		},
+	}
+	queue.Scan()
+}
+
+// Split 请求订单待分账明细分账 失败时计算下次分账时间
+func (srv *Sharing) Split(orderId string) (err error) {
+	all, err := srv.Repo.Splits(&pb.SharingSplit{OrderId: orderId})
+	if err != nil {
+		return err
+	}
+	var splits []*pb.SharingSplit
+	for _, s := range all {
+		if s.Status == sharing.StatusPending {
+			splits = append(splits, s)
+		}
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	first := splits[0]
+	order := &orderPB.Order{
+		Id:         first.OrderId,
+		StoreId:    first.StoreId,
+		Channel:    first.Channel,
+		OutTradeNo: first.OutTradeNo,
+		TradeNo:    first.TradeNo,
+	}
+	var splitErr error
+	if first.Type == sharing.TypeFinish {
+		splitErr = srv.finish(first.StoreId, order, first.OutOrderNo)
+	} else {
+		splitErr = srv.split(first.StoreId, order, first.OutOrderNo, splits)
+	}
+	// 同一订单的明细使用相同的下次分账时间 下次抢占时一并顺延
+	nextAt := worker.Format(time.Now().Add(worker.Backoff(sharingBackoff, sharingMaxBackoff, first.Attempts+1)))
+	for _, s := range splits {
+		s.Attempts++
+		switch {
+		case splitErr == nil:
+			s.Status = sharing.StatusSuccess
+			s.Error = ""
+		case s.Attempts >= sharingMaxAttempts:
+			s.Status = sharing.StatusFailed
+			s.Error = worker.Truncate(splitErr.Error(), 255)
+		default:
+			s.Error = worker.Truncate(splitErr.Error(), 255)
+			s.NextAt = nextAt
+		}
+		if err = srv.Repo.Update(s); err != nil {
+			return err
+		}
+	}
+	return splitErr
+}
+
+// split 向支付通道请求分账
+func (srv *Sharing) split(storeId string, order *orderPB.Order, outOrderNo string, splits []*pb.SharingSplit) error {
+	con, err := srv.Trade.storeConfig(storeId)
+	if err != nil {
+		return fmt.Errorf("查询商户支付配置信息失败:%s", err.Error())
+	}
+	channel, err := srv.Trade.channel(order.Channel, con)
+	if err != nil {
+		return err
+	}
+	sharer, ok := channel.(trade.ProfitSharer)
+	if !ok {
+		return errors.New("支付通道不支持分账:" + order.Channel)
+	}
+	receivers, err := sharing.Receivers(splits)
+	if err != nil {
+		return err
+	}
+	content, err := sharer.ProfitSharing(order, outOrderNo, receivers)
+	if err != nil {
+		return err
+	}
+	if content["return_code"] != "SUCCESS" {
+		return fmt.Errorf("%v", content["return_msg"])
+	}
+	return nil
+}
+
+// finish 向支付通道请求完结分账
+func (srv *Sharing) finish(storeId string, order *orderPB.Order, outOrderNo string) error {
+	con, err := srv.Trade.storeConfig(storeId)
+	if err != nil {
+		return fmt.Errorf("查询商户支付配置信息失败:%s", err.Error())
+	}
+	channel, err := srv.Trade.channel(order.Channel, con)
+	if err != nil {
+		return err
+	}
+	finisher, ok := channel.(trade.SharingFinisher)
+	if !ok {
+		return errors.New("支付通道不支持完结分账:" + order.Channel)
+	}
+	content, err := finisher.ProfitSharingFinish(order, outOrderNo)
+	if err != nil {
+		return err
+	}
+	if content["return_code"] != "SUCCESS" {
+		return fmt.Errorf("%v", content["return_msg"])
+	}
+	return nil
+}
+
+// createSplits 支付成功后按商户分账规则创建待分账明细 由后台任务分账 创建失败不影响订单处理
+// 下单时冻结资金的通道没有分账明细时创建完结分账任务 解冻资金给商户
+func (srv *Trade) createSplits(con *configPB.Config, repoOrder *orderPB.Order) {
+	if srv.Sharing == nil || !con.ProfitSharing || repoOrder.TotalFee <= 0 {
+		return
+	}
+	rules, err := srv.Sharing.Rules(con.Id)
+	if err != nil {
+		log.Error(repoOrder, err)
+		return
+	}
+	nextAt := worker.Format(time.Now().Add(sharingDelay))
+	splits := sharing.Splits(rules, repoOrder, nextAt)
+	if len(splits) == 0 {
+		channel, err := srv.channel(repoOrder.Channel, con)
+		if err != nil {
+			return
+		}
+		if _, ok := channel.(trade.SharingFinisher); !ok {
+			return
+		}
+		splits = append(splits, sharing.Finish(repoOrder, nextAt))
+	}
+	if err = srv.Sharing.CreateSplits(splits); err != nil {
+		log.Error(repoOrder, err)
+	}
+}
+
+// returnSplits 退款订单创建后按累计退款比例回退已分账资金 待分账明细按相同比例扣减分账金额
+// 同一退款重复请求时已处理的明细不重复回退 回退失败时不允许退款 通道不支持回退时跳过已分账明细
+func (srv *Trade) returnSplits(channel trade.Channel, refundOrder *orderPB.Order, originalOrder *orderPB.Order) error {
+	if srv.Sharing == nil {
+		return nil
+	}
+	splits, err := srv.Sharing.Splits(&pb.SharingSplit{OrderId: originalOrder.Id})
+	if err != nil {
+		return errors.New("查询订单分账明细失败")
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	refundFee, err := srv.Repo.RefundingFee(originalOrder)
+	if err != nil {
+		return errors.New("查询订单已退款金额失败")
+	}
+	for _, s := range splits {
+		outReturnNo := sharing.OutReturnNo(s, refundOrder.OutTradeNo)
+		if s.OutReturnNo == outReturnNo || (s.Status != sharing.StatusPending && s.Status != sharing.StatusSuccess) {
+			continue
+		}
+		if s.Type == sharing.TypeFinish {
+			// 全额退款后没有待解冻资金 取消完结分账
+			if s.Status == sharing.StatusPending && refundFee >= originalOrder.TotalFee {
+				s.Status = sharing.StatusFailed
+				s.Error = sharingRefundCancelled
+				if err = srv.Sharing.Update(s); err != nil {
+					return errors.New("更新分账明细失败")
+				}
+			}
+			continue
+		}
+		amount := sharing.ReturnAmount(s, originalOrder.TotalFee, refundFee)
+		if amount <= 0 {
+			continue
+		}
+		if s.Status == sharing.StatusSuccess {
+			returner, ok := channel.(trade.SharingReturner)
+			if !ok {
+				// 支付宝等不支持单独回退分账的通道不阻止退款 已分账资金由商户与接收方自行结算
+				log.Log("支付通道不支持分账回退 跳过回退:", s.Channel, s.OutOrderNo, s.Account)
+				continue
+			}
+			receiver := sharing.NewReceiver(s)
+			receiver.Amount = amount
+			content, err := returner.ProfitSharingReturn(s.OutOrderNo, outReturnNo, receiver)
+			if err != nil {
+				return err
+			}
+			if content["return_code"] != "SUCCESS" {
+				s.Error = worker.Truncate(fmt.Sprint(content["return_msg"]), 255)
+				srv.Sharing.Update(s)
+				return errors.New("分账回退失败:" + s.Error)
+			}
+			s.Error = ""
+		}
+		s.OutReturnNo = outReturnNo
+		s.ReturnedAmount += amount
+		if s.ReturnedAmount >= s.Amount {
+			switch s.Status {
+			case sharing.StatusPending:
+				s.Status = sharing.StatusFailed
+				s.Error = sharingRefundCancelled
+			case sharing.StatusSuccess:
+				s.Status = sharing.StatusReturned
+			}
+		}
+		if err = srv.Sharing.Update(s); err != nil {
+			return errors.New("更新分账明细失败")
+		}
+	}
+	return nil
+}
+
+// restoreSplits 退款关闭后按剩余退款金额恢复退款时扣减或取消的待分账明细 取消的明细重新排队分账
+// 已通过通道回退的分账资金已退回商户 不再恢复
+func (srv *Trade) restoreSplits(originalOrder *orderPB.Order) error {
+	if srv.Sharing == nil {
+		return nil
+	}
+	splits, err := srv.Sharing.Splits(&pb.SharingSplit{OrderId: originalOrder.Id})
+	if err != nil {
+		return errors.New("查询订单分账明细失败")
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	refundFee, err := srv.Repo.RefundingFee(originalOrder)
+	if err != nil {
+		return errors.New("查询订单已退款金额失败")
+	}
+	for _, s := range splits {
+		cancelled := s.Status == sharing.StatusFailed && s.Error == sharingRefundCancelled
+		if s.Status != sharing.StatusPending && !cancelled {
+			continue
+		}
+		if s.Type == sharing.TypeFinish {
+			if !cancelled || refundFee >= originalOrder.TotalFee {
+				continue
+			}
+		} else {
+			returned := sharing.Returned(s, originalOrder.TotalFee, refundFee)
+			if returned >= s.ReturnedAmount {
+				continue
+			}
+			s.ReturnedAmount = returned
+		}
+		if cancelled {
+			s.Status = sharing.StatusPending
+			s.Error = ""
+			s.NextAt = worker.Format(time.Now())
+		}
+		if err = srv.Sharing.Update(s); err != nil {
+			return errors.New("更新分账明细失败")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/sharing"
+	tradePB "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/sharing"
+	"github.com/lecex/pay/service/state"
+)
+
+// fakeSharing 内存分账仓库
+type fakeSharing struct {
+	mu     sync.Mutex
+	rules  []*pb.SharingRule
+	splits []*pb.SharingSplit
+}
+
+func (repo *fakeSharing) Rules(storeId string) ([]*pb.SharingRule, error) { return repo.rules, nil }
+func (repo *fakeSharing) SaveRules(storeId string, rules []*pb.SharingRule) error {
+	repo.rules = rules
+	return nil
+}
+func (repo *fakeSharing) List(req *pb.ListQuery) ([]*pb.SharingSplit, error) { return nil, nil }
+func (repo *fakeSharing) Total(req *pb.ListQuery) (int64, error)             { return 0, nil }
+
+func (repo *fakeSharing) CreateSplits(splits []*pb.SharingSplit) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, s := range splits {
+		c := *s
+		c.Id = int64(len(repo.splits) + 1)
+		repo.splits = append(repo.splits, &c)
+	}
+	return nil
+}
+
+func (repo *fakeSharing) Splits(split *pb.SharingSplit) (splits []*pb.SharingSplit, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, s := range repo.splits {
+		if (split.OrderId == "" || s.OrderId == split.OrderId) && (split.OutTradeNo == "" || s.OutTradeNo == split.OutTradeNo) {
+			c := *s
+			splits = append(splits, &c)
+		}
+	}
+	return splits, nil
+}
+
+func (repo *fakeSharing) Update(split *pb.SharingSplit) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	c := *split
+	repo.splits[split.Id-1] = &c
+	return nil
+}
+
+func (repo *fakeSharing) Due(now string, limit int64) (splits []*pb.SharingSplit, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, s := range repo.splits {
+		if s.Status == sharing.StatusPending && s.NextAt <= now {
+			c := *s
+			splits = append(splits, &c)
+		}
+	}
+	return splits, nil
+}
+
+func (repo *fakeSharing) Claim(split *pb.SharingSplit, nextAt string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	ok := false
+	for _, s := range repo.splits {
+		if s.OrderId == split.OrderId && s.Status == sharing.StatusPending && s.Attempts == split.Attempts && s.NextAt == split.NextAt {
+			s.NextAt = nextAt
+			ok = true
+		}
+	}
+	return ok, nil
+}
+
+func TestSharingSplitAndReturn(t *testing.T) {
+	sharingDelay = 0
+	h := newTestTrade(1)
+	h.Config.(*fakeConfig).configs["store-0"].ProfitSharing = true
+	repo := &fakeSharing{rules: []*pb.SharingRule{
+		{Id: 1, Channel: "fake", Type: "MERCHANT_ID", Account: "1900000109", Rate: 3000},
+		{Id: 2, Channel: "fake", Type: "PERSONAL_OPENID", Account: "oUpF8u", Rate: 10}, // 金额不足1分不参与分账
+		{Id: 3, Channel: "wechat", Type: "MERCHANT_ID", Account: "1900000110", Rate: 1000},
+	}}
+	h.Sharing = repo
+	srv := &Sharing{Trade: h, Repo: repo}
+
+	for _, outTradeNo := range []string{"S0001", "S0002"} {
+		h.AopF2F(context.TODO(), &tradePB.Request{
+			StoreId: "store-0",
+			BizContent: &tradePB.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "分账测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+			},
+		}, &tradePB.Response{})
+	}
+	if len(repo.splits) != 2 || repo.splits[0].Amount != 30 || repo.splits[0].OutOrderNo != "PSS0001" {
+		t.Fatalf("splits: %+v", repo.splits)
+	}
+	// S0002 改为个人接收方 用于测试无法回退
+	repo.splits[1].Type, repo.splits[1].Account = "PERSONAL_OPENID", "oUpF8u"
+	srv.Scan()
+	if repo.splits[0].Status != sharing.StatusSuccess || repo.splits[0].Attempts != 1 {
+		t.Fatalf("split S0001: %+v", repo.splits[0])
+	}
+
+	// 已分账订单部分退款按退款比例回退分账
+	res := &tradePB.Response{}
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0001", OutRefundNo: "S0001_R1", RefundFee: 50},
+	}, res)
+	if s := repo.splits[0]; res.Content.Status != SUCCESS || s.Status != sharing.StatusSuccess || s.ReturnedAmount != 15 || s.OutReturnNo != "PRS0001_R1_1" {
+		t.Fatalf("refund S0001_R1: %+v %+v", res.Content, s)
+	}
+	// 重复请求不重复回退
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0001", OutRefundNo: "S0001_R1", RefundFee: 50},
+	}, &tradePB.Response{})
+	if repo.splits[0].ReturnedAmount != 15 {
+		t.Fatalf("refund S0001_R1 again: %+v", repo.splits[0])
+	}
+	// 退还剩余金额时回退剩余分账
+	res = &tradePB.Response{}
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0001", OutRefundNo: "S0001_R2"},
+	}, res)
+	if s := repo.splits[0]; res.Content.Status != SUCCESS || s.Status != sharing.StatusReturned || s.ReturnedAmount != 30 {
+		t.Fatalf("refund S0001_R2: %+v %+v", res.Content, s)
+	}
+
+	// 个人接收方无法回退 关闭退款订单释放可退款金额
+	res = &tradePB.Response{}
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0002", OutRefundNo: "S0002_R"},
+	}, res)
+	if res.Content.ReturnCode != "Refund.ProfitSharing.Return" || repo.splits[1].Status != sharing.StatusSuccess || repo.splits[1].ReturnedAmount != 0 {
+		t.Fatalf("refund S0002: %+v %+v", res.Content, repo.splits[1])
+	}
+	refund := &orderPB.Order{StoreId: "store-0", OutTradeNo: "S0002_R"}
+	if err := h.Repo.StoreIdAndOutTradeNoGet(refund); err != nil || refund.State != state.Closed {
+		t.Fatalf("refund order not closed: %+v %v", refund, err)
+	}
+	original := &orderPB.Order{StoreId: "store-0", OutTradeNo: "S0002"}
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	if fee, _ := h.Repo.RefundingFee(original); fee != 0 || original.State != state.Success {
+		t.Fatalf("original order: %+v refunding %d", original, fee)
+	}
+}
+
+func TestSharingRefundFail(t *testing.T) {
+	sharingDelay = 0
+	h := newTestTrade(1)
+	h.Config.(*fakeConfig).configs["store-0"].ProfitSharing = true
+	repo := &fakeSharing{rules: []*pb.SharingRule{
+		{Id: 1, Channel: "fake", Type: "MERCHANT_ID", Account: "1900000109", Rate: 3000},
+	}}
+	h.Sharing = repo
+	srv := &Sharing{Trade: h, Repo: repo}
+	h.AopF2F(context.TODO(), &tradePB.Request{
+		StoreId: "store-0",
+		BizContent: &tradePB.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "分账测试",
+			TotalFee:   100,
+			OutTradeNo: "S0005",
+		},
+	}, &tradePB.Response{})
+	// 通道拒绝退款后恢复退款时取消的待分账明细
+	res := &tradePB.Response{}
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0005", OutRefundNo: "S0005_RFAIL"},
+	}, res)
+	if s := repo.splits[0]; res.Content.Status != CLOSED || s.Status != sharing.StatusPending || s.ReturnedAmount != 0 || s.Error != "" {
+		t.Fatalf("refund S0005_RFAIL: %+v %+v", res.Content, s)
+	}
+	srv.Scan()
+	if s := repo.splits[0]; s.Status != sharing.StatusSuccess {
+		t.Fatalf("split after refund closed: %+v", s)
+	}
+}
+
+func TestSharingReturnUnsupported(t *testing.T) {
+	sharingDelay = 0
+	h := newTestTrade(1)
+	h.Config.(*fakeConfig).configs["store-0"].ProfitSharing = true
+	repo := &fakeSharing{rules: []*pb.SharingRule{
+		{Id: 1, Channel: "royalty", Type: "userId", Account: "2088101117955611", Rate: 3000},
+	}}
+	h.Sharing = repo
+	srv := &Sharing{Trade: h, Repo: repo}
+	h.AopF2F(context.TODO(), &tradePB.Request{
+		StoreId: "store-0",
+		BizContent: &tradePB.BizContent{
+			Channel:    "royalty",
+			AuthCode:   "134567890123456789",
+			Title:      "分账测试",
+			TotalFee:   100,
+			OutTradeNo: "S0004",
+		},
+	}, &tradePB.Response{})
+	srv.Scan()
+	// 通道不支持回退时不阻止退款 已分账明细保持分账成功
+	res := &tradePB.Response{}
+	h.Refund(context.TODO(), &tradePB.Request{
+		StoreId:    "store-0",
+		BizContent: &tradePB.BizContent{OutTradeNo: "S0004", OutRefundNo: "S0004_R"},
+	}, res)
+	if s := repo.splits[0]; res.Content.Status != SUCCESS || s.Status != sharing.StatusSuccess || s.ReturnedAmount != 0 {
+		t.Fatalf("refund S0004: %+v %+v", res.Content, s)
+	}
+}
+
+func TestSharingFinish(t *testing.T) {
+	sharingDelay = 0
+	h := newTestTrade(1)
+	h.Config.(*fakeConfig).configs["store-0"].ProfitSharing = true
+	// 分账金额不足1分 没有分账明细
+	repo := &fakeSharing{rules: []*pb.SharingRule{
+		{Id: 1, Channel: "fake", Type: "MERCHANT_ID", Account: "1900000109", Rate: 10},
+	}}
+	h.Sharing = repo
+	srv := &Sharing{Trade: h, Repo: repo}
+	for _, channel := range []string{"fake", "royalty"} {
+		h.AopF2F(context.TODO(), &tradePB.Request{
+			StoreId: "store-0",
+			BizContent: &tradePB.BizContent{
+				Channel:    channel,
+				AuthCode:   "134567890123456789",
+				Title:      "分账测试",
+				TotalFee:   50,
+				OutTradeNo: "F_" + channel,
+			},
+		}, &tradePB.Response{})
+	}
+	// 不冻结资金的通道不需要完结分账
+	if len(repo.splits) != 1 || repo.splits[0].Type != sharing.TypeFinish || repo.splits[0].OutOrderNo != "PFF_fake" {
+		t.Fatalf("splits: %+v", repo.splits)
+	}
+	srv.Scan()
+	if s := repo.splits[0]; s.Status != sharing.StatusSuccess || s.Attempts != 1 {
+		t.Fatalf("finish: %+v", s)
+	}
+}
+
+func TestSharingRetry(t *testing.T) {
+	sharingDelay = 0
+	h := newTestTrade(1)
+	h.Config.(*fakeConfig).configs["store-0"].ProfitSharing = true
+	repo := &fakeSharing{rules: []*pb.SharingRule{
+		{Id: 1, Channel: "fake", Type: "MERCHANT_ID", Account: "fail", Rate: 3000},
+	}}
+	h.Sharing = repo
+	srv := &Sharing{Trade: h, Repo: repo}
+	h.AopF2F(context.TODO(), &tradePB.Request{
+		StoreId: "store-0",
+		BizContent: &tradePB.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "分账测试",
+			TotalFee:   100,
+			OutTradeNo: "S0003",
+		},
+	}, &tradePB.Response{})
+	if err := srv.Split(repo.splits[0].OrderId); err == nil {
+		t.Fatal("want split error")
+	}
+	s := repo.splits[0]
+	if s.Status != sharing.StatusPending || s.Attempts != 1 || s.Error != "分账失败" || s.NextAt == "" {
+		t.Fatalf("split: %+v", s)
+	}
+	// 到达最大次数后分账失败
+	repo.splits[0].Attempts = sharingMaxAttempts - 1
+	srv.Split(repo.splits[0].OrderId)
+	if repo.splits[0].Status != sharing.StatusFailed {
+		t.Fatalf("split: %+v", repo.splits[0])
+	}
+}
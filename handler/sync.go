@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lecex/core/env"
+	"github.com/micro/go-micro/v2/util/log"
+	uuid "github.com/satori/go.uuid"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/state"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 订单同步配置 每 PAY_SYNC_INTERVAL 秒扫描创建超过 PAY_SYNC_DELAY 秒且未超过 PAY_SYNC_MAX_AGE 秒的待付款和待退款订单
+// 向支付通道查询后更新订单 同一订单两次同步间隔不少于 PAY_SYNC_DELAY 秒
+var (
+	syncInterval = time.Duration(envInt("PAY_SYNC_INTERVAL", 30)) * time.Second
+	syncDelay    = time.Duration(envInt("PAY_SYNC_DELAY", 60)) * time.Second
+	syncMaxAge   = time.Duration(envInt("PAY_SYNC_MAX_AGE", 86400)) * time.Second
+	syncLease    = time.Duration(envInt("PAY_SYNC_LEASE", 120)) * time.Second
+	syncRate     = envInt("PAY_SYNC_RATE", 10)
+	syncLimit    = int64(500)
+)
+
+// syncLeaseName 订单同步租约 多个实例同时运行时只有持有租约的实例同步订单
+const syncLeaseName = "order_sync"
+
+// Sync 待付款和待退款订单同步
+type Sync struct {
+	Trade *Trade
+	Lease repository.Lease
+	Owner string         // 实例标识
+	Rates map[string]int // 各通道每秒查询次数
+}
+
+// NewSync 创建订单同步 通道查询频率默认 PAY_SYNC_RATE 次/秒 通过 PAY_SYNC_RATE_LIMIT 按通道设置 如 alipay:20,wechat:10
+func NewSync(t *Trade, lease repository.Lease) *Sync {
+	return &Sync{
+		Trade: t,
+		Lease: lease,
+		Owner: uuid.NewV4().String(),
+		Rates: syncRates(env.Getenv("PAY_SYNC_RATE_LIMIT", "")),
+	}
+}
+
+// syncRates 解析通道查询频率 格式错误的配置忽略
+func syncRates(s string) map[string]int {
+	rates := map[string]int{}
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if rate, err := strconv.Atoi(kv[1]); err == nil && rate > 0 {
+			rates[kv[0]] = rate
+		}
+	}
+	return rates
+}
+
+// rate 通道每秒查询次数
+func (s *Sync) rate(channel string) int {
+	if rate, ok := s.Rates[channel]; ok {
+		return rate
+	}
+	if syncRate > 0 {
+		return syncRate
+	}
+	return 1
+}
+
+// Run 定时同步订单 stop 关闭后释放租约并退出
+func (s *Sync) Run(stop <-chan struct{}) {
+	worker.Run(syncInterval, stop, s.Scan)
+	if err := s.Lease.Release(syncLeaseName, s.Owner, worker.Format(time.Now())); err != nil {
+		log.Error(err)
+	}
+}
+
+// Scan 获取租约后同步一批订单 各通道按查询频率并行同步 租约到期前停止
+func (s *Sync) Scan() {
+	now := time.Now()
+	ok, err := s.Lease.Acquire(syncLeaseName, s.Owner, worker.Format(now), worker.Format(now.Add(syncLease)))
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if !ok {
+		return
+	}
+	orders, err := s.Trade.Repo.Pending(worker.Format(now.Add(-syncMaxAge)), worker.Format(now.Add(-syncDelay)), syncLimit)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	channels := map[string][]*orderPB.Order{}
+	for _, o := range orders {
+		channels[o.Channel] = append(channels[o.Channel], o)
+	}
+	deadline := now.Add(syncLease * 9 / 10)
+	var wg sync.WaitGroup
+	for channel, orders := range channels {
+		wg.Add(1)
+		go func(channel string, orders []*orderPB.Order) {
+			defer wg.Done()
+			ticker := time.NewTicker(time.Second / time.Duration(s.rate(channel)))
+			defer ticker.Stop()
+			for _, o := range orders {
+				if time.Now().After(deadline) {
+					return
+				}
+				<-ticker.C
+				if err := s.Resolve(o); err != nil {
+					log.Error(o, err)
+				}
+			}
+		}(channel, orders)
+	}
+	wg.Wait()
+}
+
+// Resolve 向支付通道查询订单并按查询结果更新订单 未完成的订单排到同步队列最后
+func (s *Sync) Resolve(repoOrder *orderPB.Order) (err error) {
+	res := &pb.Response{Content: &pb.Content{}}
+	defer func() {
+		if res.Content.ReturnCode == SUCCESS && (res.Content.Status == SUCCESS || res.Content.Status == CLOSED) {
+			return
+		}
+		if e := s.Trade.Repo.Touch(repoOrder, worker.Format(time.Now())); e != nil {
+			log.Error(repoOrder, e)
+		}
+	}()
+	con, err := s.Trade.storeConfig(repoOrder.StoreId)
+	if err != nil {
+		return fmt.Errorf("查询商户支付配置信息失败:%s", err.Error())
+	}
+	channel, err := s.Trade.channel(repoOrder.Channel, con)
+	if err != nil {
+		return err
+	}
+	if repoOrder.LinkId != "" {
+		originalOrder := &orderPB.Order{Id: repoOrder.LinkId}
+		if err = s.Trade.Repo.Get(originalOrder); err != nil {
+			return fmt.Errorf("获取原订单失败:%s", err.Error())
+		}
+		content, err := channel.RefundQuery(&pb.BizContent{
+			Channel:     repoOrder.Channel,
+			OutTradeNo:  originalOrder.OutTradeNo,
+			OutRefundNo: repoOrder.OutTradeNo,
+		})
+		if err != nil {
+			return err
+		}
+		return s.Trade.handerRefundQuery(con, content, res, repoOrder, originalOrder, orderEvent(state.SourcePoller, "Trade.Sync", content))
+	}
+	content, err := channel.Query(&pb.BizContent{
+		Channel:    repoOrder.Channel,
+		OutTradeNo: repoOrder.OutTradeNo,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Trade.handerQuery(con, content, res, repoOrder, orderEvent(state.SourcePoller, "Trade.Sync", content))
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/state"
+	"github.com/lecex/pay/service/worker"
+)
+
+// fakeLease 内存租约仓库
+type fakeLease struct {
+	mu     sync.Mutex
+	owners map[string][2]string // 持有实例和到期时间
+}
+
+func (repo *fakeLease) Acquire(name string, owner string, now string, expiredAt string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if l, ok := repo.owners[name]; ok && l[0] != owner && l[1] > now {
+		return false, nil
+	}
+	repo.owners[name] = [2]string{owner, expiredAt}
+	return true, nil
+}
+
+func (repo *fakeLease) Release(name string, owner string, now string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if l, ok := repo.owners[name]; ok && l[0] == owner {
+		repo.owners[name] = [2]string{owner, now}
+	}
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	h := newTestTrade(1)
+	for _, outTradeNo := range []string{"P1", "PT1", "S1"} {
+		h.AopF2F(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "同步测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+			},
+		}, &pb.Response{})
+	}
+	order := func(outTradeNo string) *orderPB.Order {
+		o := &orderPB.Order{StoreId: "store-0", OutTradeNo: outTradeNo}
+		h.Repo.StoreIdAndOutTradeNoGet(o)
+		return o
+	}
+	// 退款请求后调用方未查询退款结果
+	original := order("S1")
+	refund := &orderPB.Order{StoreId: "store-0", Channel: "fake", TotalFee: -40, OutTradeNo: "S1_R", LinkId: original.Id}
+	if err := h.createRefundOrder(original, refund, orderEvent(state.SourceRPC, "Trade.Refund", nil)); err != nil {
+		t.Fatal(err)
+	}
+	// 通道退款关闭的退款订单同步后关闭 不再占用可退款金额
+	closed := &orderPB.Order{StoreId: "store-0", Channel: "fake", TotalFee: -60, OutTradeNo: "S1_RCLOSE", LinkId: original.Id}
+	if err := h.createRefundOrder(original, closed, orderEvent(state.SourceRPC, "Trade.Refund", nil)); err != nil {
+		t.Fatal(err)
+	}
+	created := worker.Format(time.Now().Add(-syncDelay * 2))
+	for _, o := range h.Repo.(*fakeOrder).orders {
+		o.CreatedAt, o.UpdatedAt = created, created
+	}
+
+	lease := &fakeLease{owners: map[string][2]string{}}
+	s1, s2 := NewSync(h, lease), NewSync(h, lease)
+	s1.Rates = map[string]int{"fake": 100}
+	s2.Rates = s1.Rates
+	lease.Acquire(syncLeaseName, s1.Owner, worker.Format(time.Now()), worker.Format(time.Now().Add(syncLease)))
+	// 其它实例持有租约时不同步
+	s2.Scan()
+	if o := order("P1"); o.Status != 0 {
+		t.Fatalf("order synced without lease: %+v", o)
+	}
+	s1.Scan()
+	if o := order("P1"); o.State != state.Success || o.TradeNo != "key-store-0|P1" {
+		t.Fatalf("P1 = %+v", o)
+	}
+	if o := order("S1_R"); o.State != state.Success {
+		t.Fatalf("S1_R = %+v", o)
+	}
+	if o := order("S1_RCLOSE"); o.State != state.Closed {
+		t.Fatalf("S1_RCLOSE = %+v", o)
+	}
+	if o := order("S1"); o.State != state.PartiallyRefunded || o.RefundFee != 40 {
+		t.Fatalf("S1 = %+v", o)
+	}
+	if fee, _ := h.Repo.RefundingFee(order("S1")); fee != 40 {
+		t.Fatalf("refunding fee = %d", fee)
+	}
+	// 仍在支付中的订单排到同步队列最后 间隔 PAY_SYNC_DELAY 后再同步
+	if o := order("PT1"); o.Status != 0 || o.UpdatedAt <= created {
+		t.Fatalf("PT1 = %+v", o)
+	}
+	if orders, _ := h.Repo.Pending(worker.Format(time.Now().Add(-syncMaxAge)), worker.Format(time.Now().Add(-syncDelay)), syncLimit); len(orders) != 0 {
+		t.Fatalf("pending = %+v", orders)
+	}
+	events, _ := h.Repo.Events(order("P1"))
+	if e := events[len(events)-1]; e.Source != state.SourcePoller || e.Actor != "Trade.Sync" {
+		t.Fatalf("event = %+v", e)
+	}
+
+	// 租约释放后其它实例可以同步
+	lease.Release(syncLeaseName, s1.Owner, worker.Format(time.Now()))
+	if ok, _ := lease.Acquire(syncLeaseName, s2.Owner, worker.Format(time.Now()), worker.Format(time.Now().Add(syncLease))); !ok {
+		t.Fatal("lease not released")
+	}
+}
+
+func TestSyncRates(t *testing.T) {
+	rates := syncRates("alipay:20, wechat:5,icbc:x,bad")
+	if len(rates) != 2 || rates["alipay"] != 20 || rates["wechat"] != 5 {
+		t.Fatalf("rates = %v", rates)
+	}
+	s := &Sync{Rates: rates}
+	if s.rate("alipay") != 20 || s.rate("icbc") != syncRate {
+		t.Fatalf("rate = %d %d", s.rate("alipay"), s.rate("icbc"))
+	}
+}
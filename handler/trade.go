@@ -3,8 +3,11 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/clbanning/mxj"
 	"github.com/jinzhu/gorm"
@@ -16,12 +19,15 @@ import (
 	orderPB "github.com/lecex/pay/proto/order"
 	pb "github.com/lecex/pay/proto/trade"
 	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/state"
 	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/service/webhook"
 )
 
 // USERPAYING 待付款|待退款
 // SUCCESS 付款成功
 // CLOSED 订单关闭
+// 接口返回状态 订单状态见 service/state
 const (
 	CLOSED     = "CLOSED"     // -1
 	USERPAYING = "USERPAYING" // 0
@@ -29,14 +35,37 @@ const (
 	WAITING    = "WAITING"    // 1
 )
 
+// errRefundOrderExist 退款订单号已被其它订单使用
+var errRefundOrderExist = errors.New("退款订单号已存在:BizContent.OutRefundNo")
+
+// errIdempotencyConflict 订单号已存在且请求内容与原请求不一致
+var errIdempotencyConflict = errors.New("订单编号已存在且请求内容不一致")
+
+// refundExceedError 退款金额超出可退款金额
+type refundExceedError struct {
+	refundable int64
+}
+
+func (e *refundExceedError) Error() string {
+	return fmt.Sprintf("退款金额超出可退款金额,剩余可退款金额%d分", e.refundable)
+}
+
+// 轮询配置 用户支付中时后台按退避间隔查询 超过 PAY_POLLING_TIMEOUT 秒后自动撤销
+var (
+	pollingTimeout     = time.Duration(envInt("PAY_POLLING_TIMEOUT", 60)) * time.Second
+	pollingInterval    = time.Second
+	pollingMaxInterval = time.Second * 5
+)
+
 // Trade 支付结构
 type Trade struct {
-	Config repository.Config
-	Repo   repository.Order
-	Alipay *trade.Alipay
-	Wechat *trade.Wechat
-	Icbc   *trade.Icbc
-	con    *configPB.Config
+	Config  repository.Config
+	Repo    repository.Order
+	Webhook repository.Webhook
+	Sharing repository.Sharing
+	Repair  repository.Repair
+	Poller  *Poller
+	Cache   *trade.Cache // 商户配置和支付通道缓存 为空时每次读取配置并创建通道
 }
 
 // AopF2F 商家扫用户付款码
@@ -64,20 +93,27 @@ func (srv *Trade) AopF2F(ctx context.Context, req *pb.Request, res *pb.Response)
 		res.Content.ReturnMsg = "订单编号不允许为空:BizContent.OutTradeNo"
 		return
 	}
-	err = srv.userConfig(req)
+	con, err := srv.userConfig(req)
 	if err != nil {
 		res.Content.ReturnCode = "AopF2F.userConfig"
 		res.Content.ReturnMsg = "查询商户支付配置信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
-	if !srv.con.Status {
+	if !con.Status {
 		res.Content.ReturnCode = "AopF2F.Status"
 		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
+		return nil
+	}
+	channel, err := srv.channel(req.BizContent.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = "AopF2F.Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
 		return nil
 	}
-	repoOrder, err := srv.handerOrder(&orderPB.Order{
+	order := &orderPB.Order{
 		StoreId:    req.StoreId,               // 商户门店编号 收款账号ID userID
 		Channel:    req.BizContent.Channel,    // 付款方式 [支付宝、微信、银联等]
 		AuthCode:   req.BizContent.AuthCode,   // 付款码
@@ -86,43 +122,110 @@ func (srv *Trade) AopF2F(ctx context.Context, req *pb.Request, res *pb.Response)
 		OutTradeNo: req.BizContent.OutTradeNo, // 订单编号
 		OperatorId: req.BizContent.OperatorId, // 商户操作员编号
 		TerminalId: req.BizContent.TerminalId, // 商户机具终端编号
-		Status:     0,                         // 订单状态 默认状态未付款
 		Attach:     req.BizContent.Attach,     // 商户机具终端编号
-	}) //创建订单返回订单ID
+	}
+	fp := aopF2FFingerprint(order)
+	order.Fingerprint = fp
+	repoOrder, err := srv.handerOrder(order, orderEvent(state.SourceRPC, "Trade.AopF2F", nil)) //创建订单返回订单ID
 	if err != nil {
 		res.Content.ReturnCode = "AopF2F.handerOrder"
 		res.Content.ReturnMsg = "创建系统订单失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	if repoOrder.Channel != req.BizContent.Channel {
 		res.Content.ReturnCode = "AopF2F.handerOrder.BizContent.Channel"
 		res.Content.ReturnMsg = "请求支付通道方式和系统已存在订单支付通道不符"
-		log.Fatal(req, res)
+		log.Error(req, res)
 		return nil
 	}
-	content := mxj.New()
-	switch req.BizContent.Channel {
-	case "alipay":
-		srv.newAlipayClient() //实例化连支付宝连接
-		content, err = srv.Alipay.AopF2F(req.BizContent)
-	case "wechat":
-		srv.newWechatClient() //实例化微信连接
-		content, err = srv.Wechat.AopF2F(req.BizContent)
-	case "icbc":
-		srv.newIcbcClient() //实例化微信连接
-		content, err = srv.Icbc.AopF2F(req.BizContent)
+	if conflict(repoOrder, fp, func() string { return aopF2FFingerprint(repoOrder) }) {
+		res.Content.ReturnCode = "AopF2F.Idempotency.Conflict"
+		res.Content.ReturnMsg = errIdempotencyConflict.Error() + ":BizContent.OutTradeNo"
+		log.Error(req, res)
+		return nil
 	}
+	if repoOrder.Status != 0 { // 已处理完成的订单重复请求直接返回最终应答
+		res.Content = storedContent(repoOrder, nil)
+		return nil
+	}
+	if req.BizContent.Polling && srv.Poller != nil && srv.Poller.Running(repoOrder.Id) { // 订单正在后台轮询 重复请求直接应答等待
+		res.Content.ReturnCode = USERPAYING
+		res.Content.ReturnMsg = "用户支付中,等待支付结果"
+		res.Content.Status = WAITING
+		res.Content.Channel = repoOrder.Channel
+		res.Content.OutTradeNo = repoOrder.OutTradeNo
+		return nil
+	}
+	content, err := channel.AopF2F(req.BizContent)
 	if err != nil {
 		res.Content.ReturnCode = "AopF2F." + req.BizContent.Channel + ".Error"
 		res.Content.ReturnMsg = req.BizContent.Channel + "下单请求失败:" + err.Error()
-		log.Fatal(res, err)
+		log.Error(res, err)
 		return nil
 	}
-	srv.handerAopF2F(content, res, repoOrder)
+	srv.handerAopF2F(con, content, res, repoOrder)
+	if req.BizContent.Polling && res.Content.Status != SUCCESS && !trade.PayFailed(content) {
+		// 轮询时间超过 RPC 调用超时时间 立即应答等待并在后台轮询 商户通过查询接口或订单通知获取支付结果
+		// 通道明确返回失败(如付款码无效)时直接应答失败
+		res.Content.Status = WAITING
+		order := *repoOrder
+		if srv.Poller != nil {
+			srv.Poller.Start(order.Id, func(stop <-chan struct{}) { srv.polling(con, channel, &order, stop) })
+		} else {
+			go srv.polling(con, channel, &order, nil)
+		}
+	}
+	srv.saveResponse(repoOrder, res.Content)
 	return err
 }
 
+// polling 后台轮询用户支付中的订单直到支付成功或关闭 超时后撤销订单 保存最终应答
+// stop 关闭或服务重启中断轮询时由订单同步任务继续处理
+// https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=5_5&index=3
+func (srv *Trade) polling(con *configPB.Config, channel trade.Channel, repoOrder *orderPB.Order, stop <-chan struct{}) {
+	b := &pb.BizContent{
+		OutTradeNo: repoOrder.OutTradeNo,
+	}
+	deadline := time.Now().Add(pollingTimeout)
+	interval := pollingInterval
+	for time.Now().Add(interval).Before(deadline) {
+		select {
+		case <-stop:
+			return
+		case <-time.After(interval):
+		}
+		content, err := channel.Query(b)
+		if err != nil {
+			log.Error(repoOrder, err)
+		} else {
+			r := &pb.Response{Content: &pb.Content{}}
+			srv.handerQuery(con, content, r, repoOrder, orderEvent(state.SourcePoller, "Trade.AopF2F.Polling", content))
+			if r.Content.Status == SUCCESS || r.Content.Status == CLOSED {
+				srv.saveResponse(repoOrder, r.Content)
+				return
+			}
+		}
+		interval *= 2
+		if interval > pollingMaxInterval {
+			interval = pollingMaxInterval
+		}
+	}
+	content, err := channel.Cancel(b)
+	if err != nil {
+		log.Error(repoOrder, "等待用户支付超时,撤销订单失败:", err)
+		return
+	}
+	r := &pb.Response{Content: &pb.Content{}}
+	e := orderEvent(state.SourcePoller, "Trade.AopF2F.Polling", content)
+	e.Remark = "等待用户支付超时撤销订单"
+	srv.handerCancel(con, content, r, repoOrder, e)
+	if r.Content.Status == CLOSED {
+		r.Content.ReturnMsg = "等待用户支付超时,订单已撤销"
+	}
+	srv.saveResponse(repoOrder, r.Content)
+}
+
 // // Query 支付查询
 // // https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_2
 func (srv *Trade) Query(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
@@ -133,31 +236,31 @@ func (srv *Trade) Query(ctx context.Context, req *pb.Request, res *pb.Response)
 		res.Content.ReturnMsg = "订单编号不允许为空:BizContent.OutTradeNo"
 		return
 	}
-	err = srv.userConfig(req)
+	con, err := srv.userConfig(req)
 	if err != nil {
 		res.Content.ReturnCode = "Query.userConfig"
 		res.Content.ReturnMsg = "查询商户支付配置信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
-	if !srv.con.Status {
+	if !con.Status {
 		res.Content.ReturnCode = "Query.Status"
 		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	repoOrder, err := srv.getOrder(req) //
 	if err != nil {
 		res.Content.ReturnCode = "Query.GetOrder"
 		res.Content.ReturnMsg = "获取订单失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 
 	if repoOrder.TotalFee < 0 { // 退款查询时不进行是不进行实际查询等待系统自动结果
 		res.Content.ReturnCode = "Query.Return.Order"
 		res.Content.ReturnMsg = "退款订单请使用退款查询接口查询订单"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 
@@ -165,32 +268,246 @@ func (srv *Trade) Query(ctx context.Context, req *pb.Request, res *pb.Response)
 	// if repoOrder.RefundFee == repoOrder.TotalFee {
 	// 	res.Content.ReturnCode = "Query.RefundFee.Not.TotalFee"
 	// 	res.Content.ReturnMsg = "订单已退款不支持再次查询"
-	// 	log.Fatal(req, res, err)
+	// 	log.Error(req, res, err)
 	// 	return nil
 	// }
 
-	content := mxj.New()
-	switch repoOrder.Channel {
-	case "alipay":
-		srv.newAlipayClient() //实例化支付宝连接
-		content, err = srv.Alipay.Query(req.BizContent)
-	case "wechat":
-		srv.newWechatClient() //实例化微信连接
-		content, err = srv.Wechat.Query(req.BizContent)
-	case "icbc":
-		srv.newIcbcClient() //实例化微信连接
-		content, err = srv.Icbc.Query(req.BizContent)
+	channel, err := srv.channel(repoOrder.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = "Query.Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		return nil
 	}
+	content, err := channel.Query(req.BizContent)
 	if err != nil {
 		res.Content.ReturnCode = "Query." + req.BizContent.Channel + ".Error"
 		res.Content.ReturnMsg = req.BizContent.Channel + "查询请求失败:" + err.Error()
-		log.Fatal(res, err)
+		log.Error(res, err)
+		return nil
+	}
+	srv.handerQuery(con, content, res, repoOrder, orderEvent(state.SourceRPC, "Trade.Query", content))
+	return nil
+}
+
+// Cancel 撤销交易 用户支付中或支付成功的订单撤销后关闭订单
+// https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_11&index=3
+func (srv *Trade) Cancel(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	// 初始化回参
+	res.Content = &pb.Content{}
+	if req.BizContent.OutTradeNo == "" {
+		res.Content.ReturnCode = "Cancel.OutTradeNo.Not"
+		res.Content.ReturnMsg = "订单编号不允许为空:BizContent.OutTradeNo"
+		return
+	}
+	con, err := srv.userConfig(req)
+	if err != nil {
+		res.Content.ReturnCode = "Cancel.userConfig"
+		res.Content.ReturnMsg = "查询商户支付配置信息失败"
+		log.Error(req, res, err)
+		return nil
+	}
+	if !con.Status {
+		res.Content.ReturnCode = "Cancel.Status"
+		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
+		log.Error(req, res, err)
+		return nil
+	}
+	repoOrder, err := srv.getOrder(req)
+	if err != nil {
+		res.Content.ReturnCode = "Cancel.GetOrder"
+		res.Content.ReturnMsg = "获取订单失败"
+		log.Error(req, res, err)
+		return nil
+	}
+	if repoOrder.TotalFee < 0 {
+		res.Content.ReturnCode = "Cancel.Refund.Order"
+		res.Content.ReturnMsg = "退款订单不支持撤销"
+		log.Error(req, res)
 		return nil
 	}
-	srv.handerQuery(content, res, repoOrder)
+	if repoOrder.Status == -1 { // 已关闭订单无需再次撤销
+		res.Content.ReturnCode = SUCCESS
+		res.Content.Status = CLOSED
+		res.Content.Channel = repoOrder.Channel
+		res.Content.OutTradeNo = repoOrder.OutTradeNo
+		res.Content.TradeNo = repoOrder.TradeNo
+		res.Content.TotalFee = repoOrder.TotalFee
+		return nil
+	}
+	if !state.Can(state.Current(repoOrder), state.Reversed) {
+		res.Content.ReturnCode = "Cancel.State"
+		res.Content.ReturnMsg = "订单当前状态" + state.Current(repoOrder) + "不允许撤销"
+		log.Error(req, res)
+		return nil
+	}
+	channel, err := srv.channel(repoOrder.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = "Cancel.Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		return nil
+	}
+	content, err := channel.Cancel(req.BizContent)
+	if err != nil {
+		res.Content.ReturnCode = "Cancel." + repoOrder.Channel + ".Error"
+		res.Content.ReturnMsg = repoOrder.Channel + "撤销请求失败:" + err.Error()
+		log.Error(res, err)
+		return nil
+	}
+	srv.handerCancel(con, content, res, repoOrder, orderEvent(state.SourceRPC, "Trade.Cancel", content))
+	return nil
+}
+
+// Precreate 预下单 用户扫商家收款码 订单状态由异步通知或查询更新
+// https://pay.weixin.qq.com/wiki/doc/api/native.php?chapter=6_5&index=3
+func (srv *Trade) Precreate(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	content := srv.prepay("Precreate", req, res, func(channel trade.Channel) payFunc {
+		return channel.Precreate
+	})
+	if content != nil && content["return_code"] == "SUCCESS" {
+		res.Content.CodeUrl = content["code_url"].(string)
+	}
 	return nil
 }
 
+// JsapiPay 公众号/小程序支付 返回前端调起支付参数 订单状态由异步通知或查询更新
+// https://pay.weixin.qq.com/wiki/doc/api/jsapi.php?chapter=7_7&index=6
+func (srv *Trade) JsapiPay(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	content := srv.prepay("JsapiPay", req, res, func(channel trade.Channel) payFunc {
+		if payer, ok := channel.(trade.JsapiPayer); ok {
+			return payer.JsapiPay
+		}
+		return nil
+	})
+	if content != nil && content["return_code"] == "SUCCESS" {
+		res.Content.PayParams = content["pay_params"].(string)
+	}
+	return nil
+}
+
+// WebPay 手机网站/电脑网站支付 返回浏览器跳转地址 订单状态由异步通知或查询更新
+// https://opendocs.alipay.com/open/203/105285
+func (srv *Trade) WebPay(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	content := srv.prepay("WebPay", req, res, func(channel trade.Channel) payFunc {
+		if payer, ok := channel.(trade.WebPayer); ok {
+			return payer.WebPay
+		}
+		return nil
+	})
+	if content != nil && content["return_code"] == "SUCCESS" {
+		res.Content.PayUrl = content["pay_url"].(string)
+	}
+	return nil
+}
+
+// payFunc 通道下单方法
+type payFunc func(b *pb.BizContent) (mxj.Map, error)
+
+// prepay 创建待付款订单后向通道下单 用户完成支付前订单保持待付款状态
+// 参数校验或请求通道失败时返回 nil method 返回 nil 时通道不支持该支付方式 不创建订单
+func (srv *Trade) prepay(api string, req *pb.Request, res *pb.Response, method func(trade.Channel) payFunc) mxj.Map {
+	// 初始化回参
+	res.Content = &pb.Content{}
+	if req.BizContent.Title == "" {
+		res.Content.ReturnCode = api + ".Title.Not"
+		res.Content.ReturnMsg = "订单标题不允许为空:BizContent.Title"
+		return nil
+	}
+	if req.BizContent.TotalFee <= 0 {
+		res.Content.ReturnCode = api + ".TotalFee.Not"
+		res.Content.ReturnMsg = "订单金额不允许小于1:BizContent.TotalFee"
+		return nil
+	}
+	if req.BizContent.OutTradeNo == "" {
+		res.Content.ReturnCode = api + ".OutTradeNo.Not"
+		res.Content.ReturnMsg = "订单编号不允许为空:BizContent.OutTradeNo"
+		return nil
+	}
+	con, err := srv.userConfig(req)
+	if err != nil {
+		res.Content.ReturnCode = api + ".userConfig"
+		res.Content.ReturnMsg = "查询商户支付配置信息失败"
+		log.Error(req, res, err)
+		return nil
+	}
+	if !con.Status {
+		res.Content.ReturnCode = api + ".Status"
+		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
+		log.Error(req, res, err)
+		return nil
+	}
+	channel, err := srv.channel(req.BizContent.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = api + ".Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		return nil
+	}
+	pay := method(channel)
+	if pay == nil {
+		res.Content.ReturnCode = api + ".Channel.Unsupported"
+		res.Content.ReturnMsg = "支付通道不支持该支付方式:" + req.BizContent.Channel
+		return nil
+	}
+	order := &orderPB.Order{
+		StoreId:    req.StoreId,               // 商户门店编号 收款账号ID userID
+		Channel:    req.BizContent.Channel,    // 付款方式 [支付宝、微信、银联等]
+		Title:      req.BizContent.Title,      // 订单标题
+		TotalFee:   req.BizContent.TotalFee,   // 订单总金额
+		OutTradeNo: req.BizContent.OutTradeNo, // 订单编号
+		OperatorId: req.BizContent.OperatorId, // 商户操作员编号
+		TerminalId: req.BizContent.TerminalId, // 商户机具终端编号
+		Attach:     req.BizContent.Attach,     // 商户机具终端编号
+	}
+	fp := prepayFingerprint(order)
+	order.Fingerprint = fp
+	repoOrder, err := srv.handerOrder(order, orderEvent(state.SourceRPC, "Trade."+api, nil)) //创建订单返回订单ID
+	if err != nil {
+		res.Content.ReturnCode = api + ".handerOrder"
+		res.Content.ReturnMsg = "创建系统订单失败"
+		log.Error(req, res, err)
+		return nil
+	}
+	if repoOrder.Channel != req.BizContent.Channel {
+		res.Content.ReturnCode = api + ".handerOrder.BizContent.Channel"
+		res.Content.ReturnMsg = "请求支付通道方式和系统已存在订单支付通道不符"
+		log.Error(req, res)
+		return nil
+	}
+	// 未支付订单重新下单时金额和标题必须与已存在订单一致 避免按新金额向通道下单
+	if conflict(repoOrder, fp, func() string { return prepayFingerprint(repoOrder) }) {
+		res.Content.ReturnCode = api + ".Idempotency.Conflict"
+		res.Content.ReturnMsg = errIdempotencyConflict.Error() + ":BizContent.OutTradeNo"
+		log.Error(req, res)
+		return nil
+	}
+	if repoOrder.Status != 0 {
+		res.Content.ReturnCode = api + ".handerOrder.Status"
+		res.Content.ReturnMsg = "订单已支付或已关闭"
+		log.Error(req, res)
+		return nil
+	}
+	content, err := pay(req.BizContent)
+	if err != nil {
+		res.Content.ReturnCode = api + "." + req.BizContent.Channel + ".Error"
+		res.Content.ReturnMsg = req.BizContent.Channel + "下单请求失败:" + err.Error()
+		log.Error(res, err)
+		return nil
+	}
+	res.Content.ReturnCode = content["return_code"].(string)
+	res.Content.ReturnMsg = content["return_msg"].(string)
+	res.Content.Channel = repoOrder.Channel
+	res.Content.OutTradeNo = repoOrder.OutTradeNo
+	res.Content.TotalFee = repoOrder.TotalFee
+	if content["return_code"] == "SUCCESS" {
+		res.Content.Status = USERPAYING
+	}
+	c, _ := content["content"].(mxj.Map).Json()
+	res.Content.Content = string(c)
+	return content
+}
+
 // Refund 交易退款
 func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
 	// 初始化回参
@@ -205,18 +522,23 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 		res.Content.ReturnMsg = "订单编号不允许为空:BizContent.OutTradeNo"
 		return
 	}
+	if req.BizContent.RefundFee < 0 {
+		res.Content.ReturnCode = "Refund.RefundFee.Invalid"
+		res.Content.ReturnMsg = "退款金额不允许小于0:BizContent.RefundFee"
+		return
+	}
 
-	err = srv.userConfig(req)
+	con, err := srv.userConfig(req)
 	if err != nil {
 		res.Content.ReturnCode = "Refund.userConfig"
 		res.Content.ReturnMsg = "查询商户支付配置信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
-	if !srv.con.Status {
+	if !con.Status {
 		res.Content.ReturnCode = "Refund.Status"
 		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	originalOrder := &orderPB.Order{
@@ -224,7 +546,7 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 		OutTradeNo: req.BizContent.OutTradeNo, // 订单编号
 	}
 	// 工行银行扫码退款用工行的条码
-	if ok, _ := regexp.Match(srv.con.Icbc.MerId, []byte(req.BizContent.OutTradeNo)); ok && req.BizContent.Channel == "icbc" {
+	if ok, _ := regexp.Match(con.Icbc.MerId, []byte(req.BizContent.OutTradeNo)); ok && req.BizContent.Channel == "icbc" {
 		originalOrder.OutTradeNo = ""
 		originalOrder.TradeNo = req.BizContent.OutTradeNo
 		err = srv.Repo.StoreIdAndTradeNoGet(originalOrder) //获取订单返回订单ID
@@ -234,35 +556,119 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 	if err != nil {
 		res.Content.ReturnCode = "Refund.OriginalOrder.GetOrder"
 		res.Content.ReturnMsg = "获取订单失败,请先查询订单确认订单存在"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	if originalOrder.Status != 1 {
 		res.Content.ReturnCode = "Refund.OriginalOrder.Status"
 		res.Content.ReturnMsg = "订单未支付成功不允许退款,请先查询订单确认订单支付成功"
-		log.Fatal(req, originalOrder)
+		log.Error(req, originalOrder)
 		return nil
 	}
-	// 构建新的退款订单
-	if req.BizContent.RefundFee == 0 {
-		req.BizContent.TotalFee = -originalOrder.TotalFee // 全额退款
-	} else {
-		req.BizContent.TotalFee = -req.BizContent.RefundFee // 退款改为负数金额
+	channel, err := srv.channel(originalOrder.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = "Refund.Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		return nil
 	}
-	refundOrder, err := srv.handerOrder(&orderPB.Order{
-		StoreId:    req.StoreId,                // 商户门店编号 收款账号ID userID
-		Channel:    originalOrder.Channel,      // 付款方式 [支付宝、微信、银联等]
-		AuthCode:   originalOrder.AuthCode,     // 付款码
-		Title:      originalOrder.Title,        // 订单标题
-		TotalFee:   req.BizContent.TotalFee,    // 订单总金额
-		OutTradeNo: req.BizContent.OutRefundNo, // 订单编号
-		OperatorId: originalOrder.OperatorId,   // 商户操作员编号
-		TerminalId: originalOrder.TerminalId,   // 商户机具终端编号
-		LinkId:     originalOrder.Id,
-		Status:     0,                     // 订单状态 默认状态未付款
-		Attach:     req.BizContent.Attach, // 商户机具终端编号
-	}) //创建退款订单返回订单ID
-	res.Content = srv.handerRefund(refundOrder, originalOrder)
+	fp := refundFingerprint(req.BizContent)
+	exist := &orderPB.Order{StoreId: req.StoreId, OutTradeNo: req.BizContent.OutRefundNo}
+	switch err = srv.Repo.StoreIdAndOutTradeNoGet(exist); {
+	case err == nil: // 重复退款请求
+		if exist.LinkId != originalOrder.Id {
+			res.Content.ReturnCode = "Refund.OutRefundNo.Exist"
+			res.Content.ReturnMsg = errRefundOrderExist.Error()
+			log.Error(req, res)
+			return nil
+		}
+		if conflict(exist, fp, func() string {
+			if req.BizContent.RefundFee == 0 { // 历史全额退款无法确认请求金额
+				return fp
+			}
+			return refundFingerprint(&pb.BizContent{OutTradeNo: req.BizContent.OutTradeNo, OutRefundNo: exist.OutTradeNo, RefundFee: -exist.TotalFee})
+		}) {
+			res.Content.ReturnCode = "Refund.Idempotency.Conflict"
+			res.Content.ReturnMsg = errIdempotencyConflict.Error()
+			log.Error(req, res)
+			return nil
+		}
+		if exist.Status != 0 { // 已处理完成的退款重复请求直接返回最终应答
+			res.Content = storedContent(exist, originalOrder)
+			return nil
+		}
+	case err == gorm.ErrRecordNotFound:
+		// 新退款预先校验可退款金额 创建退款订单时加锁再次校验
+		refundingFee, err := srv.Repo.RefundingFee(originalOrder)
+		if err != nil {
+			res.Content.ReturnCode = "Refund.RefundingFee"
+			res.Content.ReturnMsg = "查询订单已退款金额失败"
+			log.Error(req, res, err)
+			return nil
+		}
+		if refundable := originalOrder.TotalFee - refundingFee; refundable <= 0 || refundable < req.BizContent.RefundFee {
+			res.Content.ReturnCode = "Refund.RefundFee.Exceed"
+			res.Content.ReturnMsg = (&refundExceedError{refundable}).Error()
+			return nil
+		}
+	default:
+		res.Content.ReturnCode = "Refund.RefundOrder.GetOrder"
+		res.Content.ReturnMsg = "获取退款订单失败"
+		log.Error(req, res, err)
+		return nil
+	}
+	// 构建新的退款订单 退款金额为0时退还剩余可退款金额
+	req.BizContent.TotalFee = -req.BizContent.RefundFee // 退款改为负数金额
+	refundOrder := &orderPB.Order{
+		StoreId:     req.StoreId,                // 商户门店编号 收款账号ID userID
+		Channel:     originalOrder.Channel,      // 付款方式 [支付宝、微信、银联等]
+		AuthCode:    originalOrder.AuthCode,     // 付款码
+		Title:       originalOrder.Title,        // 订单标题
+		TotalFee:    req.BizContent.TotalFee,    // 订单总金额
+		OutTradeNo:  req.BizContent.OutRefundNo, // 订单编号
+		OperatorId:  originalOrder.OperatorId,   // 商户操作员编号
+		TerminalId:  originalOrder.TerminalId,   // 商户机具终端编号
+		LinkId:      originalOrder.Id,
+		Attach:      req.BizContent.Attach, // 商户机具终端编号
+		Fingerprint: fp,
+	}
+	err = srv.createRefundOrder(originalOrder, refundOrder, orderEvent(state.SourceRPC, "Trade.Refund", nil)) //创建退款订单返回订单ID
+	if err != nil {
+		res.Content.ReturnCode = "Refund.handerOrder"
+		res.Content.ReturnMsg = "创建退款订单失败"
+		switch e := err.(type) {
+		case *refundExceedError:
+			res.Content.ReturnCode = "Refund.RefundFee.Exceed"
+			res.Content.ReturnMsg = e.Error()
+		default:
+			switch err {
+			case errRefundOrderExist:
+				res.Content.ReturnCode = "Refund.OutRefundNo.Exist"
+				res.Content.ReturnMsg = err.Error()
+			case errIdempotencyConflict:
+				res.Content.ReturnCode = "Refund.Idempotency.Conflict"
+				res.Content.ReturnMsg = err.Error()
+			}
+		}
+		log.Error(req, res, err)
+		return nil
+	}
+	if refundOrder.Status != 0 { // 并发的相同退款请求已处理完成
+		res.Content = storedContent(refundOrder, originalOrder)
+		return nil
+	}
+	// 退款订单占用可退款金额后回退分账 回退失败时关闭退款订单释放可退款金额
+	if err = srv.returnSplits(channel, refundOrder, originalOrder); err != nil {
+		res.Content.ReturnCode = "Refund.ProfitSharing.Return"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		if err = srv.closeRefund(con, refundOrder, originalOrder, orderEvent(state.SourceRPC, "Trade.Refund", nil)); err != nil {
+			srv.repair(refundOrder, state.Closed, nil, err)
+		}
+		return nil
+	}
+	res.Content = srv.handerRefund(con, channel, refundOrder, originalOrder)
+	srv.saveResponse(refundOrder, res.Content)
 	return nil
 }
 
@@ -273,14 +679,14 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 // 	if err != nil {
 // 		res.Error.Code = "AffirmRefund.userConfig"
 // 		res.Error.Detail = "退款是支付配置信息查询失败"
-// 		log.Fatal(req, res, err)
+// 		log.Error(req, res, err)
 // 		return nil
 // 	}
 // 	refundOrder, err := srv.getOrder(req.BizContent) //创建订单返回订单ID
 // 	if err != nil {
 // 		res.Error.Code = "AffirmRefund.getOrder"
 // 		res.Error.Detail = "确认退款获取订单失败"
-// 		log.Fatal(req, res, err)
+// 		log.Error(req, res, err)
 // 		return nil
 // 	}
 // 	originalOrder := &orderPB.Order{
@@ -290,7 +696,7 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 // 	if err != nil {
 // 		res.Error.Code = "AffirmRefund.Repo.Get"
 // 		res.Error.Detail = "确认退款获取原始订单失败"
-// 		log.Fatal(req, res, err)
+// 		log.Error(req, res, err)
 // 		return nil
 // 	}
 // 	res.Valid, res.Content, err = srv.handerRefund(config, refundOrder, originalOrder)
@@ -298,42 +704,38 @@ func (srv *Trade) Refund(ctx context.Context, req *pb.Request, res *pb.Response)
 // }
 
 // handerRefund 处理退款
-func (srv *Trade) handerRefund(refundOrder *orderPB.Order, originalOrder *orderPB.Order) (resContent *pb.Content) {
-	content := mxj.New()
+func (srv *Trade) handerRefund(con *configPB.Config, channel trade.Channel, refundOrder *orderPB.Order, originalOrder *orderPB.Order) (resContent *pb.Content) {
 	resContent = &pb.Content{}
-	var err error
-	switch refundOrder.Channel {
-	case "alipay":
-		srv.newAlipayClient() //实例化支付宝连接
-		content, err = srv.Alipay.Refund(refundOrder, originalOrder)
-	case "wechat":
-		srv.newWechatClient() //实例化微信连接
-		content, err = srv.Wechat.Refund(refundOrder, originalOrder)
-	case "icbc":
-		srv.newIcbcClient() //实例化微信连接
-		content, err = srv.Icbc.Refund(refundOrder, originalOrder)
-	}
+	content, err := channel.Refund(refundOrder, originalOrder)
 	if err != nil {
 		resContent.ReturnCode = "Refund." + refundOrder.Channel + ".Error"
 		resContent.ReturnMsg = refundOrder.Channel + "退款请求失败:" + err.Error()
-		log.Fatal(resContent, err)
+		log.Error(resContent, err)
 		return resContent
 	}
 	resContent.ReturnCode = content["return_code"].(string)
 	resContent.ReturnMsg = content["return_msg"].(string)
-	if content["return_code"] == "SUCCESS" {
-		err = srv.successOrder(refundOrder)
+	if content["return_code"] == "FAIL" {
+		// 通道明确退款失败 关闭退款订单释放占用的可退款金额
+		err = srv.closeRefund(con, refundOrder, originalOrder, orderEvent(state.SourceRPC, "Trade.Refund", content))
 		if err != nil {
 			resContent.Status = WAITING
-			resContent.ReturnCode = "Refund.Success.Update"
-			resContent.ReturnMsg = "退款成功,更新订单状态失败!"
+			resContent.ReturnCode = "Refund.Close.Update"
+			resContent.ReturnMsg = "退款失败,更新订单状态失败!"
+			srv.repair(refundOrder, state.Closed, content, err)
 			return resContent
 		}
-		err = srv.Repo.UpdateRefundFee(originalOrder)
+		resContent.Status = CLOSED
+		resContent.OutTradeNo = originalOrder.OutTradeNo
+		resContent.OutRefundNo = refundOrder.OutTradeNo
+	}
+	if content["return_code"] == "SUCCESS" {
+		err = srv.successRefund(con, refundOrder, originalOrder, orderEvent(state.SourceRPC, "Trade.Refund", content))
 		if err != nil {
 			resContent.Status = WAITING
-			resContent.ReturnCode = "Refund.Success.UpdateRefundFee"
-			resContent.ReturnMsg = "退款成功,更新原始订单退款金额失败!"
+			resContent.ReturnCode = "Refund.Success.Update"
+			resContent.ReturnMsg = "退款成功,更新订单状态失败!"
+			srv.repair(refundOrder, state.Success, content, err)
 			return resContent
 		}
 		resContent.Status = SUCCESS
@@ -366,17 +768,17 @@ func (srv *Trade) RefundQuery(ctx context.Context, req *pb.Request, res *pb.Resp
 		return
 	}
 
-	err = srv.userConfig(req)
+	con, err := srv.userConfig(req)
 	if err != nil {
 		res.Content.ReturnCode = "RefundQuery.userConfig"
 		res.Content.ReturnMsg = "查询商户支付配置信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
-	if !srv.con.Status {
+	if !con.Status {
 		res.Content.ReturnCode = "RefundQuery.Status"
 		res.Content.ReturnMsg = "支付功能被禁用！请联系管理员。"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	originalOrder := &orderPB.Order{
@@ -384,7 +786,7 @@ func (srv *Trade) RefundQuery(ctx context.Context, req *pb.Request, res *pb.Resp
 		OutTradeNo: req.BizContent.OutTradeNo, // 订单编号
 	}
 	// 工行银行扫码退款用工行的条码
-	if ok, _ := regexp.Match(srv.con.Icbc.MerId, []byte(req.BizContent.OutTradeNo)); ok && req.BizContent.Channel == "icbc" {
+	if ok, _ := regexp.Match(con.Icbc.MerId, []byte(req.BizContent.OutTradeNo)); ok && req.BizContent.Channel == "icbc" {
 		originalOrder.OutTradeNo = ""
 		originalOrder.TradeNo = req.BizContent.OutTradeNo
 		err = srv.Repo.StoreIdAndTradeNoGet(originalOrder) //获取订单返回订单ID
@@ -395,7 +797,7 @@ func (srv *Trade) RefundQuery(ctx context.Context, req *pb.Request, res *pb.Resp
 		res.Content.Status = CLOSED
 		res.Content.ReturnCode = "RefundQuery.OriginalOrder.StoreIdAndOutTradeNoGet"
 		res.Content.ReturnMsg = "获取订单失败,获取退款订单信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 	repoOrder := &orderPB.Order{
@@ -407,49 +809,39 @@ func (srv *Trade) RefundQuery(ctx context.Context, req *pb.Request, res *pb.Resp
 	if err != nil {
 		res.Content.ReturnCode = "RefundQuery.repoOrder.StoreIdAndOutTradeNoGet"
 		res.Content.ReturnMsg = "获取订单失败,获取订单相关退款订单信息失败"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
 
 	if repoOrder.TotalFee > 0 { // 退款查询时不进行是不进行实际查询等待系统自动结果
 		res.Content.ReturnCode = "RefundQuery.Query.Order"
 		res.Content.ReturnMsg = "普通订单请使用查询接口查询订单"
-		log.Fatal(req, res, err)
+		log.Error(req, res, err)
 		return nil
 	}
-	content := mxj.New()
-	switch repoOrder.Channel {
-	case "alipay":
-		srv.newAlipayClient() //实例化支付宝连接
-		content, err = srv.Alipay.RefundQuery(req.BizContent)
-	case "wechat":
-		srv.newWechatClient() //实例化微信连接
-		content, err = srv.Wechat.RefundQuery(req.BizContent)
-	case "icbc":
-		srv.newIcbcClient() //实例化微信连接
-		content, err = srv.Icbc.RefundQuery(req.BizContent)
+	channel, err := srv.channel(repoOrder.Channel, con)
+	if err != nil {
+		res.Content.ReturnCode = "RefundQuery.Channel"
+		res.Content.ReturnMsg = err.Error()
+		log.Error(req, res, err)
+		return nil
 	}
+	content, err := channel.RefundQuery(req.BizContent)
 	if err != nil {
 		res.Content.ReturnCode = "RefundQuery." + req.BizContent.Channel + ".Error"
 		res.Content.ReturnMsg = req.BizContent.Channel + "退款查询请求失败:" + err.Error()
-		log.Fatal(res, err)
+		log.Error(res, err)
 		return nil
 	}
-	srv.handerRefundQuery(content, res, repoOrder, originalOrder)
+	srv.handerRefundQuery(con, content, res, repoOrder, originalOrder, orderEvent(state.SourceRPC, "Trade.RefundQuery", content))
 	return nil
 }
 
-// userConfig 用户配置
-func (srv *Trade) userConfig(req *pb.Request) error {
-	if req.StoreId == "" {
-		return errors.New("商户ID不允许为空:StoreId")
-	}
-	config := &configPB.Config{
-		Id: req.StoreId,
-	}
-	err := srv.Config.Get(config)
+// userConfig 用户配置 每个请求独立获取避免并发请求之间互相覆盖
+func (srv *Trade) userConfig(req *pb.Request) (*configPB.Config, error) {
+	config, err := srv.storeConfig(req.StoreId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// 通道设置
 	if req.BizContent.Channel == "" {
@@ -466,6 +858,31 @@ func (srv *Trade) userConfig(req *pb.Request) error {
 			}
 		}
 	}
+	return config, err
+}
+
+// storeConfig 获取商户支付配置 配置由缓存共用 不能修改
+func (srv *Trade) storeConfig(storeId string) (*configPB.Config, error) {
+	if storeId == "" {
+		return nil, errors.New("商户ID不允许为空:StoreId")
+	}
+	return srv.Cache.Config(storeId, func() (*configPB.Config, error) { return srv.loadConfig(storeId) })
+}
+
+// channel 获取商户支付通道 缓存中的商户配置复用已创建的支付通道
+func (srv *Trade) channel(name string, con *configPB.Config) (trade.Channel, error) {
+	return srv.Cache.Channel(name, con)
+}
+
+// loadConfig 读取商户支付配置 子商户模式使用系统配置的服务商信息
+func (srv *Trade) loadConfig(storeId string) (*configPB.Config, error) {
+	config := &configPB.Config{
+		Id: storeId,
+	}
+	err := srv.Config.Get(config)
+	if err != nil {
+		return nil, err
+	}
 	if config.Alipay.AppAuthToken != "" { // 子商户模式需要通过系统配置进行设置服务商信息
 		config.Alipay.AppId = env.Getenv("PAY_ALIPAY_APPID", config.Alipay.AppId)
 		config.Alipay.PrivateKey = env.Getenv("PAY_ALIPAY_PRIVATE_KEY", config.Alipay.PrivateKey)
@@ -488,8 +905,16 @@ func (srv *Trade) userConfig(req *pb.Request) error {
 		config.Icbc.ReturnSignType = env.Getenv("PAY_ICBC_RETURN_SIGN_TYPE", config.Icbc.ReturnSignType)
 		config.Icbc.MerId = config.Icbc.SubMerId
 	}
-	srv.con = config
-	return err
+	return config, err
+}
+
+// envInt 获取整数环境变量
+func envInt(key string, fallback int) int {
+	i, err := strconv.Atoi(env.Getenv(key, strconv.Itoa(fallback)))
+	if err != nil {
+		return fallback
+	}
+	return i
 }
 
 // getOrder 获取订单
@@ -502,11 +927,13 @@ func (srv *Trade) getOrder(b *pb.Request) (repoOrder *orderPB.Order, err error)
 	return repoOrder, err
 }
 
-// handerOrder 处理订单
-func (srv *Trade) handerOrder(repoOrder *orderPB.Order) (*orderPB.Order, error) {
+// handerOrder 处理订单 订单不存在时创建待付款订单
+func (srv *Trade) handerOrder(repoOrder *orderPB.Order, e *orderPB.OrderEvent) (*orderPB.Order, error) {
 	err := srv.Repo.StoreIdAndOutTradeNoGet(repoOrder)
 	if err == gorm.ErrRecordNotFound {
-		err = srv.Repo.Create(repoOrder)
+		err = srv.Repo.Transaction(func(tx repository.Order) error {
+			return createOrder(tx, repoOrder, e)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -514,55 +941,21 @@ func (srv *Trade) handerOrder(repoOrder *orderPB.Order) (*orderPB.Order, error)
 	return repoOrder, err
 }
 
-// newAlipayClient 实例化支付宝付款方式连接
-func (srv *Trade) newAlipayClient() {
-	srv.Alipay.NewClient(map[string]string{
-		"AppId":                srv.con.Alipay.AppId,
-		"PrivateKey":           srv.con.Alipay.PrivateKey,
-		"AliPayPublicKey":      srv.con.Alipay.AliPayPublicKey,
-		"AppAuthToken":         srv.con.Alipay.AppAuthToken,
-		"SysServiceProviderId": srv.con.Alipay.SysServiceProviderId,
-		"SignType":             srv.con.Alipay.SignType,
-	}, srv.con.Alipay.Sandbox)
-}
-
-// newWechatClient 实例化微信付款方式连接
-func (srv *Trade) newWechatClient() {
-	srv.Wechat.NewClient(map[string]string{
-		"AppId":    srv.con.Wechat.AppId,
-		"MchId":    srv.con.Wechat.MchId,
-		"ApiKey":   srv.con.Wechat.ApiKey,
-		"SubAppId": srv.con.Wechat.SubAppId,
-		"SubMchId": srv.con.Wechat.SubMchId,
-		"PemCert":  srv.con.Wechat.PemCert,
-		"PemKey":   srv.con.Wechat.PemKey,
-	}, srv.con.Wechat.Sandbox)
-}
-
-// newIcbcClient 实例化微信付款方式连接
-func (srv *Trade) newIcbcClient() {
-	srv.Icbc.NewClient(map[string]string{
-		"AppId":          srv.con.Icbc.AppId,
-		"PrivateKey":     srv.con.Icbc.PrivateKey,
-		"IcbcPublicKey":  srv.con.Icbc.IcbcPublicKey,
-		"SignType":       srv.con.Icbc.SignType,
-		"ReturnSignType": srv.con.Icbc.ReturnSignType,
-		"MerId":          srv.con.Icbc.MerId,
-	})
-}
-
 // handerAopF2F 处理扫码支付回调信息
-func (srv *Trade) handerAopF2F(content mxj.Map, res *pb.Response, repoOrder *orderPB.Order) (err error) {
+func (srv *Trade) handerAopF2F(con *configPB.Config, content mxj.Map, res *pb.Response, repoOrder *orderPB.Order) (err error) {
 	res.Content.Channel = repoOrder.Channel
 	res.Content.ReturnCode = content["return_code"].(string)
 	res.Content.ReturnMsg = content["return_msg"].(string)
 	if content["return_code"] == "SUCCESS" {
 		repoOrder.TradeNo = content["trade_no"].(string)
-		err = srv.successOrder(repoOrder)
+		err = srv.successOrder(con, repoOrder, orderEvent(state.SourceRPC, "Trade.AopF2F", content))
 		if err != nil {
 			res.Content.Status = WAITING
 			res.Content.ReturnCode = "AopF2.Update.Success"
 			res.Content.ReturnMsg = "支付成功,更新订单状态失败!"
+			res.Content.OutTradeNo = repoOrder.OutTradeNo
+			srv.repair(repoOrder, state.Success, content, err)
+			return nil
 		}
 		res.Content.Status = SUCCESS
 		res.Content.Channel = content["channel"].(string)
@@ -590,7 +983,7 @@ func (srv *Trade) handerAopF2F(content mxj.Map, res *pb.Response, repoOrder *ord
 }
 
 // handerQuery 处理订单查询支付回调信息
-func (srv *Trade) handerQuery(content mxj.Map, res *pb.Response, repoOrder *orderPB.Order) (err error) {
+func (srv *Trade) handerQuery(con *configPB.Config, content mxj.Map, res *pb.Response, repoOrder *orderPB.Order, e *orderPB.OrderEvent) (err error) {
 	res.Content.ReturnCode = content["return_code"].(string)
 	res.Content.ReturnMsg = content["return_msg"].(string)
 	res.Content.Channel = repoOrder.Channel
@@ -598,24 +991,31 @@ func (srv *Trade) handerQuery(content mxj.Map, res *pb.Response, repoOrder *orde
 		switch content["status"].(string) {
 		case SUCCESS:
 			repoOrder.TradeNo = content["trade_no"].(string)
-			err = srv.successOrder(repoOrder)
+			err = srv.successOrder(con, repoOrder, e)
 			if err != nil {
 				res.Content.Status = WAITING
 				res.Content.ReturnCode = "Query.Update.Success"
 				res.Content.ReturnMsg = "支付成功,更新订单状态失败!"
+				res.Content.OutTradeNo = repoOrder.OutTradeNo
+				srv.repair(repoOrder, state.Success, content, err)
+				return nil
 			}
 			res.Content.Status = SUCCESS
 		case CLOSED:
-			repoOrder.Fee = 0
-			repoOrder.Status = -1
-			err = srv.Repo.Update(repoOrder)
+			err = srv.closeOrder(con, repoOrder, state.Closed, e)
 			if err != nil {
 				res.Content.Status = WAITING
 				res.Content.ReturnCode = "Query.Update.Close"
 				res.Content.ReturnMsg = "支付失败,更新订单状态失败!"
+				res.Content.OutTradeNo = repoOrder.OutTradeNo
+				srv.repair(repoOrder, state.Closed, content, err)
+				return nil
 			}
 			res.Content.Status = CLOSED
 		case USERPAYING:
+			if err = srv.payingOrder(repoOrder, e); err != nil {
+				log.Error(repoOrder, err)
+			}
 			res.Content.Status = USERPAYING
 		case WAITING:
 			res.Content.Status = WAITING
@@ -643,24 +1043,56 @@ func (srv *Trade) handerQuery(content mxj.Map, res *pb.Response, repoOrder *orde
 	return nil
 }
 
-// handerRefundQuery 处理订单退款查询支付回调信息
-func (srv *Trade) handerRefundQuery(content mxj.Map, res *pb.Response, refundOrder *orderPB.Order, originalOrder *orderPB.Order) (err error) {
-	res.Content.Status = WAITING
+// handerCancel 处理撤销交易回调信息
+func (srv *Trade) handerCancel(con *configPB.Config, content mxj.Map, res *pb.Response, repoOrder *orderPB.Order, e *orderPB.OrderEvent) (err error) {
 	res.Content.ReturnCode = content["return_code"].(string)
 	res.Content.ReturnMsg = content["return_msg"].(string)
+	res.Content.Channel = repoOrder.Channel
 	if content["return_code"] == "SUCCESS" {
-		err = srv.successOrder(refundOrder)
+		err = srv.closeOrder(con, repoOrder, state.Reversed, e)
 		if err != nil {
 			res.Content.Status = WAITING
-			res.Content.ReturnCode = "Refund.Success.Update"
-			res.Content.ReturnMsg = "退款成功,更新订单状态失败!"
+			res.Content.ReturnCode = "Cancel.Update.Close"
+			res.Content.ReturnMsg = "撤销成功,更新订单状态失败!"
+			srv.repair(repoOrder, state.Reversed, content, err)
+		} else {
+			res.Content.Status = CLOSED
+		}
+		res.Content.OutTradeNo = repoOrder.OutTradeNo
+		res.Content.TradeNo = repoOrder.TradeNo
+		res.Content.TotalFee = repoOrder.TotalFee
+	}
+	if c, ok := content["content"].(mxj.Map); ok {
+		j, _ := c.Json()
+		res.Content.Content = string(j)
+	}
+	return nil
+}
+
+// handerRefundQuery 处理订单退款查询支付回调信息
+func (srv *Trade) handerRefundQuery(con *configPB.Config, content mxj.Map, res *pb.Response, refundOrder *orderPB.Order, originalOrder *orderPB.Order, e *orderPB.OrderEvent) (err error) {
+	res.Content.Status = WAITING
+	res.Content.ReturnCode = content["return_code"].(string)
+	res.Content.ReturnMsg = content["return_msg"].(string)
+	switch trade.RefundStatus(content) {
+	case CLOSED:
+		err = srv.closeRefund(con, refundOrder, originalOrder, e)
+		if err != nil {
+			res.Content.ReturnCode = "Refund.Close.Update"
+			res.Content.ReturnMsg = "退款失败,更新订单状态失败!"
+			srv.repair(refundOrder, state.Closed, content, err)
 			return
 		}
-		err = srv.Repo.UpdateRefundFee(originalOrder)
+		res.Content.Status = CLOSED
+		res.Content.OutTradeNo = originalOrder.OutTradeNo
+		res.Content.OutRefundNo = refundOrder.OutTradeNo
+	case SUCCESS:
+		err = srv.successRefund(con, refundOrder, originalOrder, e)
 		if err != nil {
 			res.Content.Status = WAITING
-			res.Content.ReturnCode = "Refund.Success.UpdateRefundFee"
-			res.Content.ReturnMsg = "退款成功,更新原始订单退款金额失败!"
+			res.Content.ReturnCode = "Refund.Success.Update"
+			res.Content.ReturnMsg = "退款成功,更新订单状态失败!"
+			srv.repair(refundOrder, state.Success, content, err)
 			return
 		}
 		res.Content.Status = SUCCESS
@@ -676,22 +1108,218 @@ func (srv *Trade) handerRefundQuery(content mxj.Map, res *pb.Response, refundOrd
 	return nil
 }
 
-// successOrder 支付成功订单处理
-func (srv *Trade) successOrder(repoOrder *orderPB.Order) (err error) {
+// successOrder 支付成功订单处理 订单已支付时不再重复处理
+func (srv *Trade) successOrder(con *configPB.Config, repoOrder *orderPB.Order, e *orderPB.OrderEvent) (err error) {
+	if state.Status(state.Current(repoOrder)) == 1 {
+		return nil
+	}
+	successFee(con, repoOrder)
+	var changed bool
+	err = srv.Repo.Transaction(func(tx repository.Order) (err error) {
+		changed, err = transit(tx, repoOrder, state.Success, e)
+		return err
+	})
+	if err != nil || !changed {
+		return err
+	}
+	srv.merchantNotify(con, webhook.EventSuccess, repoOrder)
+	srv.createSplits(con, repoOrder)
+	return nil
+}
+
+// successFee 计算订单手续费
+func successFee(con *configPB.Config, repoOrder *orderPB.Order) {
 	var fee int64
 	switch repoOrder.Channel {
 	case "alipay":
-		fee = srv.con.Alipay.Fee
+		fee = con.Alipay.Fee
 	case "wechat":
-		fee = srv.con.Wechat.Fee
+		fee = con.Wechat.Fee
 	case "icbc":
-		fee = srv.con.Icbc.Fee
+		fee = con.Icbc.Fee
 	}
-	repoOrder.Status = 1
 	repoOrder.Fee = int64(math.Floor(float64(repoOrder.TotalFee*fee)/10000 + 0.5)) // 相乘后转浮点型乘以万分之一然后四舍五入 【+0.5四舍五入取整】
-	err = srv.Repo.Update(repoOrder)
-	if err != nil {
+}
+
+// payingOrder 用户支付中 待付款订单记录用户支付中状态
+func (srv *Trade) payingOrder(repoOrder *orderPB.Order, e *orderPB.OrderEvent) error {
+	if state.Current(repoOrder) != state.Created {
+		return nil
+	}
+	return srv.Repo.Transaction(func(tx repository.Order) error {
+		_, err := transit(tx, repoOrder, state.UserPaying, e)
+		return err
+	})
+}
+
+// createRefundOrder 锁定原订单后校验可退款金额并创建待退款订单 退款金额为0时退还剩余可退款金额
+// 待退款订单占用可退款金额 并发退款时后提交的退款会因可退款金额不足被拒绝 退款订单已存在时直接返回
+func (srv *Trade) createRefundOrder(originalOrder *orderPB.Order, refundOrder *orderPB.Order, e *orderPB.OrderEvent) error {
+	return srv.Repo.Transaction(func(tx repository.Order) error {
+		if err := tx.LockGet(originalOrder); err != nil {
+			return err
+		}
+		exist := &orderPB.Order{StoreId: refundOrder.StoreId, OutTradeNo: refundOrder.OutTradeNo}
+		err := tx.StoreIdAndOutTradeNoGet(exist)
+		if err == nil {
+			if exist.LinkId != originalOrder.Id {
+				return errRefundOrderExist
+			}
+			if exist.Fingerprint != "" && exist.Fingerprint != refundOrder.Fingerprint {
+				return errIdempotencyConflict
+			}
+			*refundOrder = *exist
+			return nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		refundingFee, err := tx.RefundingFee(originalOrder)
+		if err != nil {
+			return err
+		}
+		refundable := originalOrder.TotalFee - refundingFee
+		if refundOrder.TotalFee == 0 {
+			refundOrder.TotalFee = -refundable
+		}
+		if refundable <= 0 || -refundOrder.TotalFee > refundable {
+			return &refundExceedError{refundable}
+		}
+		if err = createOrder(tx, refundOrder, e); err != nil {
+			return err
+		}
+		_, err = transit(tx, originalOrder, state.Refunding, e)
+		return err
+	})
+}
+
+// successRefund 退款成功 退款订单状态和原订单退款金额在同一事务中更新
+func (srv *Trade) successRefund(con *configPB.Config, refundOrder *orderPB.Order, originalOrder *orderPB.Order, e *orderPB.OrderEvent) error {
+	successFee(con, refundOrder)
+	changed, err := srv.finishRefund(refundOrder, originalOrder, state.Success, e)
+	if err != nil || !changed {
 		return err
 	}
-	return err
+	srv.merchantNotify(con, webhook.EventRefunded, refundOrder)
+	return nil
+}
+
+// closeRefund 退款失败 关闭退款订单释放占用的可退款金额并重新计算原订单退款金额
+func (srv *Trade) closeRefund(con *configPB.Config, refundOrder *orderPB.Order, originalOrder *orderPB.Order, e *orderPB.OrderEvent) error {
+	refundOrder.Fee = 0
+	changed, err := srv.finishRefund(refundOrder, originalOrder, state.Closed, e)
+	if err != nil || !changed {
+		return err
+	}
+	// 退款前已扣减的待分账金额恢复分账
+	if err = srv.restoreSplits(originalOrder); err != nil {
+		log.Error(refundOrder, err)
+	}
+	srv.merchantNotify(con, webhook.EventClosed, refundOrder)
+	return nil
+}
+
+// finishRefund 锁定原订单后更新退款订单状态 并根据已退款金额和待退款金额更新原订单退款金额和状态
+func (srv *Trade) finishRefund(refundOrder *orderPB.Order, originalOrder *orderPB.Order, to string, e *orderPB.OrderEvent) (changed bool, err error) {
+	err = srv.Repo.Transaction(func(tx repository.Order) (err error) {
+		if err = tx.LockGet(originalOrder); err != nil {
+			return err
+		}
+		if changed, err = transit(tx, refundOrder, to, e); err != nil {
+			return err
+		}
+		if err = tx.UpdateRefundFee(originalOrder); err != nil {
+			return err
+		}
+		refundingFee, err := tx.RefundingFee(originalOrder)
+		if err != nil {
+			return err
+		}
+		_, err = transit(tx, originalOrder, state.Refund(originalOrder, refundingFee), e)
+		return err
+	})
+	return changed, err
+}
+
+// closeOrder 关闭订单处理 to 为 CLOSED 订单关闭或 REVERSED 订单撤销
+func (srv *Trade) closeOrder(con *configPB.Config, repoOrder *orderPB.Order, to string, e *orderPB.OrderEvent) (err error) {
+	repoOrder.Fee = 0
+	var changed bool
+	err = srv.Repo.Transaction(func(tx repository.Order) (err error) {
+		changed, err = transit(tx, repoOrder, to, e)
+		return err
+	})
+	if err != nil || !changed {
+		return err
+	}
+	srv.merchantNotify(con, webhook.EventClosed, repoOrder)
+	return nil
+}
+
+// orderEvent 订单状态变更来源 content 为通道返回内容
+func orderEvent(source string, actor string, content mxj.Map) *orderPB.OrderEvent {
+	e := &orderPB.OrderEvent{
+		Source: source,
+		Actor:  actor,
+	}
+	if c, ok := content["content"].(mxj.Map); ok {
+		j, _ := c.Json()
+		e.Payload = string(j)
+	}
+	return e
+}
+
+// createOrder 创建待付款|待退款订单并记录订单创建
+func createOrder(tx repository.Order, repoOrder *orderPB.Order, e *orderPB.OrderEvent) error {
+	repoOrder.State = state.Created
+	repoOrder.Status = state.Status(state.Created)
+	if err := tx.Create(repoOrder); err != nil {
+		return err
+	}
+	event := *e
+	event.OrderId = repoOrder.Id
+	event.StoreId = repoOrder.StoreId
+	event.OutTradeNo = repoOrder.OutTradeNo
+	event.ToState = state.Created
+	return tx.CreateEvent(&event)
+}
+
+// transit 锁定订单后校验订单状态流转并更新订单 状态变更时记录变更事件 返回状态是否变更
+// 以数据库中的最新状态为准 避免异步通知和查询同时处理时重复变更
+func transit(tx repository.Order, repoOrder *orderPB.Order, to string, e *orderPB.OrderEvent) (bool, error) {
+	current := &orderPB.Order{Id: repoOrder.Id}
+	if err := tx.LockGet(current); err != nil {
+		return false, err
+	}
+	repoOrder.State = current.State
+	repoOrder.Status = current.Status
+	event, err := state.Transit(repoOrder, to, e)
+	if err != nil {
+		return false, err
+	}
+	if err = tx.Update(repoOrder); err != nil {
+		return false, err
+	}
+	if event == nil {
+		return false, nil
+	}
+	return true, tx.CreateEvent(event)
+}
+
+// merchantNotify 订单状态变更后创建商户通知 由后台任务推送 创建失败不影响订单处理
+func (srv *Trade) merchantNotify(con *configPB.Config, event string, repoOrder *orderPB.Order) {
+	if srv.Webhook == nil {
+		return
+	}
+	w, err := webhook.NewWebhook(con, event, repoOrder)
+	if err != nil {
+		log.Error(repoOrder, err)
+		return
+	}
+	if w == nil { // 商户未配置通知地址
+		return
+	}
+	if err = srv.Webhook.Create(w); err != nil {
+		log.Error(repoOrder, err)
+	}
 }
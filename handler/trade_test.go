@@ -0,0 +1,753 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clbanning/mxj"
+	"github.com/jinzhu/gorm"
+
+	configPB "github.com/lecex/pay/proto/config"
+	notifyPB "github.com/lecex/pay/proto/notify"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/sharing"
+	"github.com/lecex/pay/service/trade"
+	"github.com/lecex/pay/util"
+)
+
+// fakeConfig 内存商户配置仓库
+type fakeConfig struct {
+	configs map[string]*configPB.Config
+}
+
+func (repo *fakeConfig) List(req *configPB.ListQuery) ([]*configPB.Config, error) { return nil, nil }
+func (repo *fakeConfig) Total(req *configPB.ListQuery) (int64, error)             { return 0, nil }
+func (repo *fakeConfig) Create(config *configPB.Config) error                     { return nil }
+func (repo *fakeConfig) Delete(config *configPB.Config) (bool, error)             { return true, nil }
+func (repo *fakeConfig) Update(config *configPB.Config) error                     { return nil }
+func (repo *fakeConfig) Exist(config *configPB.Config) bool                       { return true }
+
+func (repo *fakeConfig) Get(config *configPB.Config) error {
+	c, ok := repo.configs[config.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	// 每次返回新的副本 模拟数据库查询
+	*config = *c
+	alipay, wechat, icbc := *c.Alipay, *c.Wechat, *c.Icbc
+	config.Alipay, config.Wechat, config.Icbc = &alipay, &wechat, &icbc
+	return nil
+}
+
+// fakeOrder 内存订单仓库
+type fakeOrder struct {
+	tx     sync.Mutex // 模拟事务中原订单行锁 事务串行执行
+	mu     sync.Mutex
+	seq    int
+	orders map[string]*orderPB.Order
+	events []*orderPB.OrderEvent
+}
+
+func newFakeOrder() *fakeOrder {
+	return &fakeOrder{orders: map[string]*orderPB.Order{}}
+}
+
+func (repo *fakeOrder) Amount(req *orderPB.ListQuery) (int64, error)          { return 0, nil }
+func (repo *fakeOrder) Fee(req *orderPB.ListQuery) (int64, error)             { return 0, nil }
+func (repo *fakeOrder) List(req *orderPB.ListQuery) ([]*orderPB.Order, error) { return nil, nil }
+func (repo *fakeOrder) Total(req *orderPB.ListQuery) (int64, error)           { return 0, nil }
+func (repo *fakeOrder) Delete(order *orderPB.Order) (bool, error)             { return true, nil }
+func (repo *fakeOrder) Exist(order *orderPB.Order) bool                       { return true }
+func (repo *fakeOrder) StoreIdAndTradeNoGet(order *orderPB.Order) error {
+	return gorm.ErrRecordNotFound
+}
+
+func (repo *fakeOrder) find(fn func(o *orderPB.Order) bool) (*orderPB.Order, bool) {
+	for _, o := range repo.orders {
+		if fn(o) {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+func (repo *fakeOrder) Create(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.seq++
+	order.Id = fmt.Sprintf("order-%d", repo.seq)
+	o := *order
+	repo.orders[order.Id] = &o
+	return nil
+}
+
+func (repo *fakeOrder) Update(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	o := *order
+	repo.orders[order.Id] = &o
+	return nil
+}
+
+func (repo *fakeOrder) Get(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	o, ok := repo.orders[order.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*order = *o
+	return nil
+}
+
+func (repo *fakeOrder) StoreIdAndOutTradeNoGet(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	o, ok := repo.find(func(o *orderPB.Order) bool {
+		return o.StoreId == order.StoreId && o.OutTradeNo == order.OutTradeNo
+	})
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*order = *o
+	return nil
+}
+
+func (repo *fakeOrder) UpdateRefundFee(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var refundFee int64
+	for _, o := range repo.orders {
+		if o.LinkId == order.Id && o.Status == 1 {
+			refundFee -= o.TotalFee
+		}
+	}
+	order.RefundFee = refundFee
+	o := *order
+	repo.orders[order.Id] = &o
+	return nil
+}
+
+func (repo *fakeOrder) LockGet(order *orderPB.Order) error {
+	return repo.Get(order)
+}
+
+func (repo *fakeOrder) RefundingFee(order *orderPB.Order) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	var refundingFee int64
+	for _, o := range repo.orders {
+		if o.LinkId == order.Id && (o.Status == 0 || o.Status == 1) {
+			refundingFee -= o.TotalFee
+		}
+	}
+	return refundingFee, nil
+}
+
+func (repo *fakeOrder) CreateEvent(event *orderPB.OrderEvent) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	event.Id = int64(len(repo.events) + 1)
+	repo.events = append(repo.events, event)
+	return nil
+}
+
+func (repo *fakeOrder) Events(order *orderPB.Order) (events []*orderPB.OrderEvent, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, e := range repo.events {
+		if e.OrderId == order.Id {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (repo *fakeOrder) UpdateResponse(order *orderPB.Order) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if o, ok := repo.orders[order.Id]; ok {
+		o.Response = order.Response
+	}
+	return nil
+}
+
+func (repo *fakeOrder) Pending(from string, to string, limit int64) (orders []*orderPB.Order, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, o := range repo.orders {
+		if o.Status == 0 && o.CreatedAt > from && o.UpdatedAt <= to {
+			c := *o
+			orders = append(orders, &c)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].UpdatedAt < orders[j].UpdatedAt })
+	if int64(len(orders)) > limit {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
+func (repo *fakeOrder) Touch(order *orderPB.Order, updatedAt string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if o, ok := repo.orders[order.Id]; ok {
+		o.UpdatedAt = updatedAt
+	}
+	return nil
+}
+
+func (repo *fakeOrder) Transaction(fn func(tx repository.Order) error) error {
+	repo.tx.Lock()
+	defer repo.tx.Unlock()
+	return fn(repo)
+}
+
+// fakeChannel 测试支付通道 交易号带上商户私钥用于校验是否串用配置
+// 订单号以 P 开头时付款返回用户支付中, 以 PT 开头时查询一直返回用户支付中
+// 以 PF 开头时付款返回付款码无效, 以 PE 开头时付款返回系统错误
+type fakeChannel struct {
+	key string
+}
+
+func (c *fakeChannel) content(outTradeNo string) mxj.Map {
+	return mxj.Map{
+		"return_code":  "SUCCESS",
+		"return_msg":   "OK",
+		"channel":      "fake",
+		"status":       SUCCESS,
+		"out_trade_no": outTradeNo,
+		"trade_no":     c.key + "|" + outTradeNo,
+		"time_end":     "20200101000000",
+		"content":      mxj.Map{},
+	}
+}
+
+func (c *fakeChannel) AopF2F(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	switch {
+	case strings.HasPrefix(b.OutTradeNo, "PF"):
+		content["return_code"], content["return_msg"] = "FAIL", "付款码无效"
+		content["content"] = mxj.Map{"err_code": "AUTH_CODE_INVALID"}
+	case strings.HasPrefix(b.OutTradeNo, "PE"):
+		content["return_code"], content["return_msg"] = "FAIL", "系统错误"
+		content["content"] = mxj.Map{"err_code": "SYSTEMERROR"}
+	case strings.HasPrefix(b.OutTradeNo, "P"):
+		content["return_code"] = USERPAYING
+	}
+	return content, nil
+}
+
+func (c *fakeChannel) Query(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	if strings.HasPrefix(b.OutTradeNo, "PT") {
+		content["status"] = USERPAYING
+	}
+	return content, nil
+}
+
+func (c *fakeChannel) Precreate(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	content["code_url"] = "https://qr.example.com/" + b.OutTradeNo
+	return content, nil
+}
+
+func (c *fakeChannel) JsapiPay(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	content["pay_params"] = `{"package":"prepay_id=` + b.OutTradeNo + `"}`
+	return content, nil
+}
+
+func (c *fakeChannel) WebPay(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	content["pay_url"] = "https://pay.example.com/" + b.OutTradeNo
+	return content, nil
+}
+
+// Cancel 订单号包含 FAIL 时撤销失败
+func (c *fakeChannel) Cancel(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	if strings.Contains(b.OutTradeNo, "FAIL") {
+		content["return_code"], content["return_msg"] = "FAIL", "撤销失败"
+	}
+	return content, nil
+}
+
+// RefundQuery 退款订单号包含 CLOSE 时退款关闭
+func (c *fakeChannel) RefundQuery(b *pb.BizContent) (mxj.Map, error) {
+	content := c.content(b.OutTradeNo)
+	if strings.Contains(b.OutRefundNo, "CLOSE") {
+		content["status"] = CLOSED
+	}
+	return content, nil
+}
+func (c *fakeChannel) Notify(req *notifyPB.Request) (mxj.Map, error) {
+	content := c.content(util.Get(req, "out_trade_no"))
+	content["total_fee"] = int64(100)
+	return content, nil
+}
+
+// Bill 对账单包含 B0001 和系统不存在的 B0009
+func (c *fakeChannel) Bill(date string) ([]*bill.Record, error) {
+	return []*bill.Record{
+		{OutTradeNo: "B0001", TradeNo: c.key + "|B0001", TotalFee: 100, Status: SUCCESS},
+		{OutTradeNo: "B0009", TradeNo: c.key + "|B0009", TotalFee: 100, Status: SUCCESS},
+	}, nil
+}
+
+// ProfitSharing 接收方账号为 fail 时分账失败
+func (c *fakeChannel) ProfitSharing(order *orderPB.Order, outOrderNo string, receivers []*sharing.Receiver) (mxj.Map, error) {
+	content := c.content(order.OutTradeNo)
+	for _, r := range receivers {
+		if r.Account == "fail" {
+			content["return_code"], content["return_msg"] = "FAIL", "分账失败"
+		}
+	}
+	return content, nil
+}
+
+func (c *fakeChannel) ProfitSharingFinish(order *orderPB.Order, outOrderNo string) (mxj.Map, error) {
+	return c.content(order.OutTradeNo), nil
+}
+
+// ProfitSharingReturn 接收方类型不是商户号时回退失败
+func (c *fakeChannel) ProfitSharingReturn(outOrderNo string, outReturnNo string, receiver *sharing.Receiver) (mxj.Map, error) {
+	content := c.content(outOrderNo)
+	if receiver.Type != "MERCHANT_ID" {
+		content["return_code"], content["return_msg"] = "FAIL", "回退失败"
+	}
+	return content, nil
+}
+
+func (c *fakeChannel) NotifyReply(content mxj.Map, err error) string {
+	if err != nil {
+		return "fail"
+	}
+	return "success"
+}
+
+// Refund 退款订单号包含 FAIL 时通道拒绝退款
+func (c *fakeChannel) Refund(refundOrder *orderPB.Order, originalOrder *orderPB.Order) (mxj.Map, error) {
+	content := c.content(originalOrder.OutTradeNo)
+	if strings.Contains(refundOrder.OutTradeNo, "FAIL") {
+		content["return_code"], content["return_msg"] = "FAIL", "退款失败"
+	}
+	return content, nil
+}
+
+func init() {
+	trade.Register("fake", func(con *configPB.Config) trade.Channel {
+		return &fakeChannel{key: con.Alipay.PrivateKey}
+	})
+	trade.Register("royalty", func(con *configPB.Config) trade.Channel {
+		return &royaltyChannel{&fakeChannel{key: con.Alipay.PrivateKey}}
+	})
+}
+
+// royaltyChannel 支持分账但不支持分账回退的通道 模拟支付宝
+type royaltyChannel struct {
+	trade.Channel
+}
+
+func (c *royaltyChannel) ProfitSharing(order *orderPB.Order, outOrderNo string, receivers []*sharing.Receiver) (mxj.Map, error) {
+	return c.Channel.(trade.ProfitSharer).ProfitSharing(order, outOrderNo, receivers)
+}
+
+// newTestTrade 创建带多个商户配置的测试支付服务
+func newTestTrade(stores int) *Trade {
+	configs := map[string]*configPB.Config{}
+	for i := 0; i < stores; i++ {
+		id := fmt.Sprintf("store-%d", i)
+		configs[id] = &configPB.Config{
+			Id:     id,
+			Status: true,
+			Alipay: &configPB.Alipay{PrivateKey: "key-" + id},
+			Wechat: &configPB.Wechat{},
+			Icbc:   &configPB.Icbc{},
+		}
+	}
+	return &Trade{
+		Config: &fakeConfig{configs: configs},
+		Repo:   newFakeOrder(),
+		Poller: NewPoller(),
+	}
+}
+
+func TestTradeConcurrentStores(t *testing.T) {
+	const stores, orders = 8, 10
+	h := newTestTrade(stores)
+	// 并发使用缓存的商户配置和支付通道 不同商户不串用
+	h.Cache = trade.NewCache(time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < stores; i++ {
+		for j := 0; j < orders; j++ {
+			wg.Add(1)
+			go func(storeId, outTradeNo string) {
+				defer wg.Done()
+				key := "key-" + storeId + "|"
+				res := &pb.Response{}
+				h.AopF2F(context.TODO(), &pb.Request{
+					StoreId: storeId,
+					BizContent: &pb.BizContent{
+						Channel:    "fake",
+						AuthCode:   "134567890123456789",
+						Title:      "并发测试",
+						TotalFee:   100,
+						OutTradeNo: outTradeNo,
+					},
+				}, res)
+				if res.Content.Status != SUCCESS || !strings.HasPrefix(res.Content.TradeNo, key) {
+					t.Errorf("AopF2F %s %s: %+v", storeId, outTradeNo, res.Content)
+					return
+				}
+				res = &pb.Response{}
+				h.Refund(context.TODO(), &pb.Request{
+					StoreId: storeId,
+					BizContent: &pb.BizContent{
+						OutTradeNo:  outTradeNo,
+						OutRefundNo: outTradeNo + "_R",
+						RefundFee:   40,
+					},
+				}, res)
+				if res.Content.Status != SUCCESS || !strings.HasPrefix(res.Content.TradeNo, key) {
+					t.Errorf("Refund %s %s: %+v", storeId, outTradeNo, res.Content)
+				}
+			}(fmt.Sprintf("store-%d", i), fmt.Sprintf("T%d%03d", i, j))
+		}
+	}
+	wg.Wait()
+}
+
+func TestTradeRefundExceed(t *testing.T) {
+	h := newTestTrade(1)
+	res := &pb.Response{}
+	h.AopF2F(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "退款测试",
+			TotalFee:   100,
+			OutTradeNo: "T1",
+		},
+	}, res)
+	if res.Content.Status != SUCCESS {
+		t.Fatalf("AopF2F: %+v", res.Content)
+	}
+	refund := func(outRefundNo string, refundFee int64) *pb.Content {
+		res := &pb.Response{}
+		h.Refund(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				OutTradeNo:  "T1",
+				OutRefundNo: outRefundNo,
+				RefundFee:   refundFee,
+			},
+		}, res)
+		return res.Content
+	}
+	// 并发退款 可退款金额只够两笔成功
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	success := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			content := refund(fmt.Sprintf("T1_R%d", i), 40)
+			switch {
+			case content.Status == SUCCESS:
+				mu.Lock()
+				success++
+				mu.Unlock()
+			case content.ReturnCode != "Refund.RefundFee.Exceed":
+				t.Errorf("Refund %d: %+v", i, content)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if success != 2 {
+		t.Fatalf("success refunds = %d, want 2", success)
+	}
+	// 退款金额为0时退还剩余可退款金额
+	if content := refund("T1_RALL", 0); content.Status != SUCCESS || content.RefundFee != 20 {
+		t.Fatalf("Refund remaining: %+v", content)
+	}
+	if content := refund("T1_RMORE", 0); content.ReturnCode != "Refund.RefundFee.Exceed" {
+		t.Fatalf("Refund after full refund: %+v", content)
+	}
+	original := &orderPB.Order{StoreId: "store-0", OutTradeNo: "T1"}
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	if original.RefundFee != 100 {
+		t.Fatalf("RefundFee = %d, want 100", original.RefundFee)
+	}
+}
+
+func TestTradeRefundFail(t *testing.T) {
+	h := newTestTrade(1)
+	h.AopF2F(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "退款测试",
+			TotalFee:   100,
+			OutTradeNo: "T2",
+		},
+	}, &pb.Response{})
+	refund := func(outRefundNo string) *pb.Content {
+		res := &pb.Response{}
+		h.Refund(context.TODO(), &pb.Request{
+			StoreId:    "store-0",
+			BizContent: &pb.BizContent{OutTradeNo: "T2", OutRefundNo: outRefundNo},
+		}, res)
+		return res.Content
+	}
+	// 通道拒绝退款后关闭退款订单 释放可退款金额
+	if content := refund("T2_RFAIL"); content.Status != CLOSED {
+		t.Fatalf("Refund FAIL: %+v", content)
+	}
+	refundOrder := &orderPB.Order{StoreId: "store-0", OutTradeNo: "T2_RFAIL"}
+	h.Repo.StoreIdAndOutTradeNoGet(refundOrder)
+	if refundOrder.Status != -1 {
+		t.Fatalf("refund order = %+v", refundOrder)
+	}
+	if content := refund("T2_R"); content.Status != SUCCESS || content.RefundFee != 100 {
+		t.Fatalf("Refund after FAIL: %+v", content)
+	}
+}
+
+func TestTradeIdempotency(t *testing.T) {
+	h := newTestTrade(1)
+	aopF2F := func(authCode string, totalFee int64) *pb.Content {
+		res := &pb.Response{}
+		h.AopF2F(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				Channel:    "fake",
+				AuthCode:   authCode,
+				Title:      "幂等测试",
+				TotalFee:   totalFee,
+				OutTradeNo: "I1",
+			},
+		}, res)
+		return res.Content
+	}
+	refund := func(refundFee int64) *pb.Content {
+		res := &pb.Response{}
+		h.Refund(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				OutTradeNo:  "I1",
+				OutRefundNo: "I1_R",
+				RefundFee:   refundFee,
+			},
+		}, res)
+		return res.Content
+	}
+	first := aopF2F("134567890123456789", 100)
+	if first.Status != SUCCESS {
+		t.Fatalf("AopF2F: %+v", first)
+	}
+	refunded := refund(40)
+	if refunded.Status != SUCCESS {
+		t.Fatalf("Refund: %+v", refunded)
+	}
+	// 更换通道秘钥 重放请求如果再次请求通道交易号会变化
+	h.Config.(*fakeConfig).configs["store-0"].Alipay.PrivateKey = "key-changed"
+	if content := aopF2F("134567890123456789", 100); content.Status != SUCCESS || content.TradeNo != first.TradeNo {
+		t.Fatalf("AopF2F replay: %+v, want %+v", content, first)
+	}
+	if content := refund(40); content.Status != SUCCESS || content.TradeNo != refunded.TradeNo || content.RefundFee != 40 {
+		t.Fatalf("Refund replay: %+v, want %+v", content, refunded)
+	}
+	// 请求内容不一致
+	if content := aopF2F("134567890123456789", 200); content.ReturnCode != "AopF2F.Idempotency.Conflict" {
+		t.Fatalf("AopF2F conflict: %+v", content)
+	}
+	if content := aopF2F("134567890123450000", 100); content.ReturnCode != "AopF2F.Idempotency.Conflict" {
+		t.Fatalf("AopF2F conflict: %+v", content)
+	}
+	if content := refund(50); content.ReturnCode != "Refund.Idempotency.Conflict" {
+		t.Fatalf("Refund conflict: %+v", content)
+	}
+	original := &orderPB.Order{StoreId: "store-0", OutTradeNo: "I1"}
+	h.Repo.StoreIdAndOutTradeNoGet(original)
+	if original.RefundFee != 40 {
+		t.Fatalf("RefundFee = %d, want 40", original.RefundFee)
+	}
+}
+
+func TestTradeCancel(t *testing.T) {
+	h := newTestTrade(1)
+	req := &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			AuthCode:   "134567890123456789",
+			Title:      "撤销测试",
+			TotalFee:   100,
+			OutTradeNo: "C0001",
+		},
+	}
+	h.AopF2F(context.TODO(), req, &pb.Response{})
+	res := &pb.Response{}
+	h.Cancel(context.TODO(), &pb.Request{
+		StoreId:    "store-0",
+		BizContent: &pb.BizContent{OutTradeNo: "C0001"},
+	}, res)
+	if res.Content.Status != CLOSED {
+		t.Fatalf("Cancel: %+v", res.Content)
+	}
+	order := &orderPB.Order{StoreId: "store-0", OutTradeNo: "C0001"}
+	h.Repo.StoreIdAndOutTradeNoGet(order)
+	if order.Status != -1 || order.Fee != 0 {
+		t.Fatalf("order not closed: %+v", order)
+	}
+}
+
+func TestTradePolling(t *testing.T) {
+	pollingTimeout, pollingInterval, pollingMaxInterval = time.Millisecond*50, time.Millisecond, time.Millisecond*5
+	h := newTestTrade(1)
+	aopF2F := func(outTradeNo string) *pb.Content {
+		res := &pb.Response{}
+		h.AopF2F(context.TODO(), &pb.Request{
+			StoreId: "store-0",
+			BizContent: &pb.BizContent{
+				Channel:    "fake",
+				AuthCode:   "134567890123456789",
+				Title:      "轮询测试",
+				TotalFee:   100,
+				OutTradeNo: outTradeNo,
+				Polling:    true,
+			},
+		}, res)
+		return res.Content
+	}
+	for outTradeNo, status := range map[string]int64{"PS0001": 1, "PT0001": -1, "PE0001": 1} {
+		content := aopF2F(outTradeNo)
+		// 立即应答等待 后台轮询更新订单
+		if content.Status != WAITING {
+			t.Errorf("%s: want %s got %+v", outTradeNo, WAITING, content)
+		}
+		order := &orderPB.Order{StoreId: "store-0", OutTradeNo: outTradeNo}
+		for deadline := time.Now().Add(time.Second); order.Status != status && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond * 5)
+			h.Repo.StoreIdAndOutTradeNoGet(order)
+		}
+		if order.Status != status {
+			t.Errorf("%s: want status %d got %+v", outTradeNo, status, order)
+		}
+	}
+	// 通道明确返回失败时直接应答失败 不轮询
+	failed := &orderPB.Order{StoreId: "store-0", OutTradeNo: "PF0001"}
+	if content := aopF2F("PF0001"); content.ReturnCode != "FAIL" || content.Status == WAITING {
+		t.Errorf("PF0001: %+v", content)
+	}
+	if h.Repo.StoreIdAndOutTradeNoGet(failed); h.Poller.Running(failed.Id) {
+		t.Errorf("PF0001 polling: %+v", failed)
+	}
+	// 同一订单只运行一个轮询 关闭后轮询立即退出 不撤销订单
+	pollingTimeout = time.Minute
+	aopF2F("PT0002")
+	order := &orderPB.Order{StoreId: "store-0", OutTradeNo: "PT0002"}
+	h.Repo.StoreIdAndOutTradeNoGet(order)
+	if content := aopF2F("PT0002"); content.Status != WAITING || !h.Poller.Running(order.Id) || h.Poller.Start(order.Id, func(<-chan struct{}) {}) {
+		t.Errorf("PT0002 again: %+v", content)
+	}
+	closed := make(chan struct{})
+	go func() {
+		h.Poller.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("poller not stopped")
+	}
+	if h.Repo.StoreIdAndOutTradeNoGet(order); order.Status != 0 {
+		t.Errorf("PT0002 after close: %+v", order)
+	}
+}
+
+func TestTradePrecreate(t *testing.T) {
+	h := newTestTrade(1)
+	req := &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			Title:      "预下单测试",
+			TotalFee:   100,
+			OutTradeNo: "Q0001",
+		},
+	}
+	res := &pb.Response{}
+	h.Precreate(context.TODO(), req, res)
+	if res.Content.Status != USERPAYING || res.Content.CodeUrl != "https://qr.example.com/Q0001" {
+		t.Fatalf("Precreate: %+v", res.Content)
+	}
+	order := &orderPB.Order{StoreId: "store-0", OutTradeNo: "Q0001"}
+	h.Repo.StoreIdAndOutTradeNoGet(order)
+	if order.Status != 0 {
+		t.Fatalf("order should wait for payment: %+v", order)
+	}
+	// 查询到支付成功后更新订单 已支付订单不允许重复预下单
+	h.Query(context.TODO(), &pb.Request{StoreId: "store-0", BizContent: &pb.BizContent{OutTradeNo: "Q0001"}}, &pb.Response{})
+	res = &pb.Response{}
+	h.Precreate(context.TODO(), req, res)
+	if res.Content.ReturnCode != "Precreate.handerOrder.Status" {
+		t.Fatalf("Precreate paid order: %+v", res.Content)
+	}
+	// 未支付订单重新下单 金额或标题与已存在订单不一致
+	req.BizContent.OutTradeNo = "Q0002"
+	h.Precreate(context.TODO(), req, &pb.Response{})
+	res = &pb.Response{}
+	h.Precreate(context.TODO(), req, res)
+	if res.Content.Status != USERPAYING {
+		t.Fatalf("Precreate again: %+v", res.Content)
+	}
+	for _, b := range []*pb.BizContent{
+		{Channel: "fake", Title: "预下单测试", TotalFee: 1, OutTradeNo: "Q0002"},
+		{Channel: "fake", Title: "其它商品", TotalFee: 100, OutTradeNo: "Q0002"},
+	} {
+		res = &pb.Response{}
+		h.JsapiPay(context.TODO(), &pb.Request{StoreId: "store-0", BizContent: b}, res)
+		if res.Content.ReturnCode != "JsapiPay.Idempotency.Conflict" {
+			t.Fatalf("JsapiPay conflict: %+v", res.Content)
+		}
+	}
+}
+
+func TestTradeJsapiPay(t *testing.T) {
+	h := newTestTrade(1)
+	res := &pb.Response{}
+	h.JsapiPay(context.TODO(), &pb.Request{
+		StoreId: "store-0",
+		BizContent: &pb.BizContent{
+			Channel:    "fake",
+			Title:      "小程序测试",
+			TotalFee:   100,
+			OutTradeNo: "J0001",
+			OpenId:     "oUpF8uN95-Pteags6E_roPHg7AG",
+		},
+	}, res)
+	if res.Content.Status != USERPAYING || res.Content.PayParams != `{"package":"prepay_id=J0001"}` {
+		t.Fatalf("JsapiPay: %+v", res.Content)
+	}
+	res = &pb.Response{}
+	h.JsapiPay(context.TODO(), &pb.Request{
+		StoreId:    "store-0",
+		BizContent: &pb.BizContent{Channel: "fake", TotalFee: 100, OutTradeNo: "J0002"},
+	}, res)
+	if res.Content.ReturnCode != "JsapiPay.Title.Not" {
+		t.Fatalf("JsapiPay without title: %+v", res.Content)
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/lecex/pay/proto/webhook"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/webhook"
+)
+
+// Webhook 商户通知
+type Webhook struct {
+	Repo   repository.Webhook
+	Worker *webhook.Worker
+}
+
+// List 获取所有通知
+func (srv *Webhook) List(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	webhooks, err := srv.Repo.List(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	total, err := srv.Repo.Total(req.ListQuery)
+	if err != nil {
+		return err
+	}
+	res.Total = total
+	res.Webhooks = webhooks
+	return err
+}
+
+// Get 获取通知及推送记录
+func (srv *Webhook) Get(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	err = srv.Repo.Get(req.Webhook)
+	if err != nil {
+		return err
+	}
+	logs, err := srv.Repo.Logs(req.Webhook)
+	if err != nil {
+		return err
+	}
+	res.Webhook = req.Webhook
+	res.Logs = logs
+	return err
+}
+
+// Resend 立即重新推送通知 推送失败的通知未达最大次数时继续自动重试
+func (srv *Webhook) Resend(ctx context.Context, req *pb.Request, res *pb.Response) (err error) {
+	if req.Webhook == nil || req.Webhook.Id == "" {
+		return fmt.Errorf("请传入通知id")
+	}
+	err = srv.Repo.Get(req.Webhook)
+	if err != nil {
+		return err
+	}
+	webhookLog, err := srv.Worker.Deliver(req.Webhook)
+	res.Webhook = req.Webhook
+	res.Logs = []*pb.WebhookLog{webhookLog}
+	res.Valid = err == nil
+	return nil
+}
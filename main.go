@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	// 公共引入
 	_ "github.com/lecex/core/plugins"
 	"github.com/micro/go-micro/v2"
@@ -9,10 +11,28 @@ import (
 	// 执行数据迁移
 	"github.com/lecex/pay/config"
 	"github.com/lecex/pay/handler"
+	db "github.com/lecex/pay/providers/database"
 	_ "github.com/lecex/pay/providers/migrations"
+	"github.com/lecex/pay/service/repository"
 )
 
 func main() {
+	// pay rotate-key 轮换主密钥后使用当前主密钥重新加密全部商户秘钥和应用秘钥
+	if len(os.Args) > 1 && os.Args[1] == "rotate-key" {
+		count, err := (&repository.ConfigRepository{db.DB}).EncryptSecrets(true)
+		if err != nil {
+			log.Fatal(err)
+			os.Exit(1)
+		}
+		log.Infof("重新加密商户秘钥 %d 条", count)
+		count, err = (&repository.AppRepository{db.DB}).EncryptSecrets(true)
+		if err != nil {
+			log.Fatal(err)
+			os.Exit(1)
+		}
+		log.Infof("重新加密应用秘钥 %d 条", count)
+		return
+	}
 	var Conf = config.Conf
 	service := micro.NewService(
 		micro.Name(Conf.Name),
@@ -22,11 +42,14 @@ func main() {
 	// 注册服务
 	h := handler.Handler{
 		Server: service.Server(),
+		Client: service.Client(),
 	}
 	h.Register()
+	h.Run()
 	// Run the server
 	log.Fatal("serviser run ... Version:" + Conf.Version)
 	if err := service.Run(); err != nil {
 		log.Fatal(err)
 	}
+	h.Stop()
 }
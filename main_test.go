@@ -10,7 +10,6 @@ import (
 	tradePB "github.com/lecex/pay/proto/trade"
 	db "github.com/lecex/pay/providers/database"
 	"github.com/lecex/pay/service/repository"
-	"github.com/lecex/pay/service/trade"
 
 	"github.com/lecex/pay/handler"
 )
@@ -62,9 +61,6 @@ func TestAopF2FWechat(t *testing.T) {
 	h := handler.Trade{
 		Config: &repository.ConfigRepository{db.DB},
 		Repo:   &repository.OrderRepository{db.DB},
-		Alipay: &trade.Alipay{},
-		Wechat: &trade.Wechat{},
-		Icbc:   &trade.Icbc{},
 	}
 	req := &tradePB.Request{
 		StoreId: "7b490bb0-c04d-4fd8-9bf9-ef4f2239d3a0",
@@ -90,9 +86,6 @@ func TestQuery(t *testing.T) {
 	h := handler.Trade{
 		Config: &repository.ConfigRepository{db.DB},
 		Repo:   &repository.OrderRepository{db.DB},
-		Alipay: &trade.Alipay{},
-		Wechat: &trade.Wechat{},
-		Icbc:   &trade.Icbc{},
 	}
 	req := &tradePB.Request{
 		StoreId: "7b490bb0-c04d-4fd8-9bf9-ef4f2239d3a0",
@@ -112,9 +105,6 @@ func TestRefund(t *testing.T) {
 	h := handler.Trade{
 		Config: &repository.ConfigRepository{db.DB},
 		Repo:   &repository.OrderRepository{db.DB},
-		Alipay: &trade.Alipay{},
-		Wechat: &trade.Wechat{},
-		Icbc:   &trade.Icbc{},
 	}
 	req := &tradePB.Request{
 		StoreId: "7b490bb0-c04d-4fd8-9bf9-ef4f2239d3a0",
@@ -134,9 +124,6 @@ func TestRefundQuery(t *testing.T) {
 	h := handler.Trade{
 		Config: &repository.ConfigRepository{db.DB},
 		Repo:   &repository.OrderRepository{db.DB},
-		Alipay: &trade.Alipay{},
-		Wechat: &trade.Wechat{},
-		Icbc:   &trade.Icbc{},
 	}
 	req := &tradePB.Request{
 		StoreId: "7b490bb0-c04d-4fd8-9bf9-ef4f2239d3a0",
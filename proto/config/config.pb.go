@@ -131,16 +131,17 @@ func (m *Alipay) GetSandbox() bool {
 }
 
 type Wechat struct {
-	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AppId    string `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
-	MchId    string `protobuf:"bytes,3,opt,name=mch_id,json=mchId,proto3" json:"mch_id,omitempty"`
-	ApiKey   string `protobuf:"bytes,4,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	SubAppId string `protobuf:"bytes,5,opt,name=sub_app_id,json=subAppId,proto3" json:"sub_app_id,omitempty"`
-	SubMchId string `protobuf:"bytes,6,opt,name=sub_mch_id,json=subMchId,proto3" json:"sub_mch_id,omitempty"`
-	PemCert  string `protobuf:"bytes,7,opt,name=pem_cert,json=pemCert,proto3" json:"pem_cert,omitempty"`
-	PemKey   string `protobuf:"bytes,8,opt,name=pem_key,json=pemKey,proto3" json:"pem_key,omitempty"`
-	Fee      int64  `protobuf:"varint,9,opt,name=fee,proto3" json:"fee,omitempty"`
-	Sandbox  bool   `protobuf:"varint,10,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	Id        int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AppId     string `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	MchId     string `protobuf:"bytes,3,opt,name=mch_id,json=mchId,proto3" json:"mch_id,omitempty"`
+	ApiKey    string `protobuf:"bytes,4,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	SubAppId  string `protobuf:"bytes,5,opt,name=sub_app_id,json=subAppId,proto3" json:"sub_app_id,omitempty"`
+	SubMchId  string `protobuf:"bytes,6,opt,name=sub_mch_id,json=subMchId,proto3" json:"sub_mch_id,omitempty"`
+	PemCert   string `protobuf:"bytes,7,opt,name=pem_cert,json=pemCert,proto3" json:"pem_cert,omitempty"`
+	PemKey    string `protobuf:"bytes,8,opt,name=pem_key,json=pemKey,proto3" json:"pem_key,omitempty"`
+	Fee       int64  `protobuf:"varint,9,opt,name=fee,proto3" json:"fee,omitempty"`
+	Sandbox   bool   `protobuf:"varint,10,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	AppSecret string `protobuf:"bytes,11,opt,name=app_secret,json=appSecret,proto3" json:"app_secret,omitempty"`
 }
 
 func (m *Wechat) Reset()         { *m = Wechat{} }
@@ -246,6 +247,13 @@ func (m *Wechat) GetSandbox() bool {
 	return false
 }
 
+func (m *Wechat) GetAppSecret() string {
+	if m != nil {
+		return m.AppSecret
+	}
+	return ""
+}
+
 type Icbc struct {
 	Id             int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	AppId          string `protobuf:"bytes,2,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
@@ -363,18 +371,22 @@ func (m *Icbc) GetSandbox() bool {
 }
 
 type Config struct {
-	Id        string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	StoreName string  `protobuf:"bytes,2,opt,name=store_name,json=storeName,proto3" json:"store_name,omitempty"`
-	AlipayId  int64   `protobuf:"varint,3,opt,name=alipay_id,json=alipayId,proto3" json:"alipay_id,omitempty"`
-	WechatId  int64   `protobuf:"varint,4,opt,name=wechat_id,json=wechatId,proto3" json:"wechat_id,omitempty"`
-	IcbcId    int64   `protobuf:"varint,5,opt,name=icbc_id,json=icbcId,proto3" json:"icbc_id,omitempty"`
-	Channel   string  `protobuf:"bytes,6,opt,name=channel,proto3" json:"channel,omitempty"`
-	Status    bool    `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
-	Alipay    *Alipay `protobuf:"bytes,8,opt,name=alipay,proto3" json:"alipay,omitempty"`
-	Wechat    *Wechat `protobuf:"bytes,9,opt,name=wechat,proto3" json:"wechat,omitempty"`
-	Icbc      *Icbc   `protobuf:"bytes,10,opt,name=icbc,proto3" json:"icbc,omitempty"`
-	CreatedAt string  `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt string  `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Id            string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StoreName     string  `protobuf:"bytes,2,opt,name=store_name,json=storeName,proto3" json:"store_name,omitempty"`
+	AlipayId      int64   `protobuf:"varint,3,opt,name=alipay_id,json=alipayId,proto3" json:"alipay_id,omitempty"`
+	WechatId      int64   `protobuf:"varint,4,opt,name=wechat_id,json=wechatId,proto3" json:"wechat_id,omitempty"`
+	IcbcId        int64   `protobuf:"varint,5,opt,name=icbc_id,json=icbcId,proto3" json:"icbc_id,omitempty"`
+	Channel       string  `protobuf:"bytes,6,opt,name=channel,proto3" json:"channel,omitempty"`
+	Status        bool    `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	Alipay        *Alipay `protobuf:"bytes,8,opt,name=alipay,proto3" json:"alipay,omitempty"`
+	Wechat        *Wechat `protobuf:"bytes,9,opt,name=wechat,proto3" json:"wechat,omitempty"`
+	Icbc          *Icbc   `protobuf:"bytes,10,opt,name=icbc,proto3" json:"icbc,omitempty"`
+	CreatedAt     string  `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string  `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	NotifyUrl     string  `protobuf:"bytes,13,opt,name=notify_url,json=notifyUrl,proto3" json:"notify_url,omitempty"`
+	NotifySecret  string  `protobuf:"bytes,14,opt,name=notify_secret,json=notifySecret,proto3" json:"notify_secret,omitempty"`
+	ReturnUrl     string  `protobuf:"bytes,15,opt,name=return_url,json=returnUrl,proto3" json:"return_url,omitempty"`
+	ProfitSharing bool    `protobuf:"varint,16,opt,name=profit_sharing,json=profitSharing,proto3" json:"profit_sharing,omitempty"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -494,6 +506,34 @@ func (m *Config) GetUpdatedAt() string {
 	return ""
 }
 
+func (m *Config) GetNotifyUrl() string {
+	if m != nil {
+		return m.NotifyUrl
+	}
+	return ""
+}
+
+func (m *Config) GetNotifySecret() string {
+	if m != nil {
+		return m.NotifySecret
+	}
+	return ""
+}
+
+func (m *Config) GetReturnUrl() string {
+	if m != nil {
+		return m.ReturnUrl
+	}
+	return ""
+}
+
+func (m *Config) GetProfitSharing() bool {
+	if m != nil {
+		return m.ProfitSharing
+	}
+	return false
+}
+
 type ListQuery struct {
 	Limit  int64   `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
 	Page   int64   `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
@@ -623,10 +663,11 @@ func (m *Request) GetConfig() *Config {
 }
 
 type Response struct {
-	Valid   bool      `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	Total   int64     `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Config  *Config   `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
-	Configs []*Config `protobuf:"bytes,4,rep,name=configs,proto3" json:"configs,omitempty"`
+	Valid   bool          `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Total   int64         `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Config  *Config       `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Configs []*Config     `protobuf:"bytes,4,rep,name=configs,proto3" json:"configs,omitempty"`
+	Errors  []*FieldError `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
@@ -690,6 +731,119 @@ func (m *Response) GetConfigs() []*Config {
 	return nil
 }
 
+func (m *Response) GetErrors() []*FieldError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// 配置校验错误
+type FieldError struct {
+	Field   string `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *FieldError) Reset()         { *m = FieldError{} }
+func (m *FieldError) String() string { return proto.CompactTextString(m) }
+func (*FieldError) ProtoMessage()    {}
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2f7e909880fb5ba3, []int{7}
+}
+func (m *FieldError) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *FieldError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_FieldError.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *FieldError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldError.Merge(m, src)
+}
+func (m *FieldError) XXX_Size() int {
+	return m.Size()
+}
+func (m *FieldError) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldError proto.InternalMessageInfo
+
+func (m *FieldError) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *FieldError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+// 商户配置变更事件 通过消息代理通知其它实例清除支付通道缓存
+type Event struct {
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2f7e909880fb5ba3, []int{8}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return m.Size()
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Event) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Alipay)(nil), "config.Alipay")
 	proto.RegisterType((*Wechat)(nil), "config.Wechat")
@@ -698,65 +852,76 @@ func init() {
 	proto.RegisterType((*ListQuery)(nil), "config.ListQuery")
 	proto.RegisterType((*Request)(nil), "config.Request")
 	proto.RegisterType((*Response)(nil), "config.Response")
+	proto.RegisterType((*FieldError)(nil), "config.FieldError")
+	proto.RegisterType((*Event)(nil), "config.Event")
 }
 
 func init() { proto.RegisterFile("proto/config/config.proto", fileDescriptor_2f7e909880fb5ba3) }
 
 var fileDescriptor_2f7e909880fb5ba3 = []byte{
-	// 837 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xcb, 0x8e, 0xe3, 0x44,
-	0x14, 0x8d, 0xed, 0xc4, 0xb1, 0x6f, 0x9a, 0x74, 0x53, 0x9a, 0x61, 0x3c, 0x3c, 0x42, 0x14, 0xa1,
-	0x51, 0xc4, 0x68, 0x06, 0x08, 0xe2, 0x03, 0x42, 0x23, 0xa1, 0x88, 0x87, 0x1a, 0xf7, 0x20, 0x96,
-	0x56, 0xc5, 0xbe, 0xdd, 0x29, 0x8d, 0x1f, 0x35, 0xae, 0x72, 0x06, 0x7f, 0x01, 0x2c, 0xf9, 0x02,
-	0x16, 0xf0, 0x33, 0x2c, 0x67, 0xc9, 0x12, 0x75, 0x6f, 0xf9, 0x03, 0x36, 0xa8, 0x1e, 0xee, 0x04,
-	0xd1, 0x6a, 0x05, 0x89, 0x55, 0x7c, 0xcf, 0x39, 0xae, 0x7b, 0xef, 0xf1, 0xb1, 0x03, 0x0f, 0x79,
-	0x5d, 0xc9, 0xea, 0x83, 0xb4, 0x2a, 0x2f, 0xd8, 0xa5, 0xfd, 0x79, 0xaa, 0x31, 0xe2, 0x9b, 0x6a,
-	0xf6, 0xab, 0x0b, 0xfe, 0x32, 0x67, 0x9c, 0xb6, 0x64, 0x0c, 0x2e, 0xcb, 0x22, 0x67, 0xea, 0xcc,
-	0xbd, 0xd8, 0x65, 0x19, 0xb9, 0x0f, 0x3e, 0xe5, 0x3c, 0x61, 0x59, 0xe4, 0x4e, 0x9d, 0x79, 0x18,
-	0x0f, 0x28, 0xe7, 0xab, 0x8c, 0xbc, 0x0b, 0x23, 0x5e, 0xb3, 0x2d, 0x95, 0x98, 0x3c, 0xc7, 0x36,
-	0xf2, 0x34, 0x07, 0x16, 0xfa, 0x02, 0x5b, 0xf2, 0x18, 0x08, 0xcd, 0x59, 0xc2, 0x69, 0x9b, 0xf0,
-	0x66, 0x9d, 0xb3, 0x54, 0xeb, 0xfa, 0x5a, 0x77, 0x4c, 0x73, 0x76, 0x46, 0xdb, 0x33, 0x8d, 0x2b,
-	0xf1, 0x5b, 0x10, 0x0a, 0x76, 0x59, 0x26, 0xb2, 0xe5, 0x18, 0x0d, 0xb4, 0x26, 0x50, 0xc0, 0xb3,
-	0x96, 0x23, 0x79, 0x0f, 0xc6, 0x6a, 0x02, 0xda, 0xc8, 0x4d, 0x22, 0xab, 0xe7, 0x58, 0x46, 0xbe,
-	0x56, 0x1c, 0x51, 0xce, 0x97, 0x8d, 0xdc, 0x3c, 0x53, 0x18, 0xf9, 0x04, 0x1e, 0x88, 0x56, 0x24,
-	0x02, 0xeb, 0x2d, 0x4b, 0x31, 0xe1, 0x75, 0xb5, 0x65, 0x19, 0xd6, 0x6a, 0xf0, 0xa1, 0x96, 0xdf,
-	0x13, 0xad, 0x38, 0x37, 0xec, 0x99, 0x25, 0x57, 0x19, 0x39, 0x01, 0xef, 0x02, 0x31, 0x0a, 0xf4,
-	0xbe, 0xea, 0x92, 0x44, 0x30, 0x14, 0xb4, 0xcc, 0xd6, 0xd5, 0xf7, 0x51, 0x38, 0x75, 0xe6, 0x41,
-	0xdc, 0x95, 0xb3, 0xbf, 0x1c, 0xf0, 0xbf, 0xc3, 0x74, 0x43, 0xe5, 0xa1, 0x2e, 0xdd, 0x07, 0xbf,
-	0x48, 0x37, 0x0a, 0x36, 0x06, 0x0d, 0x8a, 0x74, 0xb3, 0xca, 0xc8, 0x03, 0x18, 0x52, 0xce, 0xf6,
-	0x0c, 0xf1, 0x29, 0x67, 0xca, 0x87, 0xb7, 0x01, 0x44, 0xb3, 0x4e, 0xec, 0x51, 0x9d, 0x11, 0xcd,
-	0x7a, 0xa9, 0x4f, 0xb3, 0xac, 0x3d, 0xd1, 0xbf, 0x61, 0xbf, 0xd2, 0x87, 0x3e, 0x84, 0x80, 0x63,
-	0x91, 0xa4, 0x58, 0x4b, 0xbb, 0xf1, 0x90, 0x63, 0x71, 0x8a, 0xb5, 0x54, 0xfd, 0x14, 0xa5, 0xfa,
-	0x05, 0xa6, 0x1f, 0xc7, 0x42, 0xf5, 0xb3, 0xdb, 0x87, 0xb7, 0x6e, 0x0f, 0xff, 0xdc, 0xfe, 0x67,
-	0x17, 0xfa, 0xab, 0x74, 0x9d, 0xfe, 0x6f, 0x09, 0x79, 0x04, 0xc7, 0x2c, 0x5d, 0xa7, 0xff, 0x8e,
-	0xc7, 0x6b, 0x0a, 0x3e, 0x30, 0x1c, 0x73, 0x38, 0xa9, 0x51, 0x36, 0x75, 0x99, 0xec, 0x34, 0xc6,
-	0x99, 0xb1, 0xc1, 0xcf, 0x3b, 0xa5, 0x7a, 0x16, 0xfb, 0x79, 0x18, 0x14, 0x3a, 0x00, 0x9d, 0xa9,
-	0x86, 0x0a, 0x76, 0xa6, 0xee, 0xc7, 0xe3, 0x20, 0x83, 0xfe, 0x74, 0xc1, 0x3f, 0xd5, 0xef, 0xd3,
-	0x9e, 0x45, 0xa1, 0xb6, 0xe8, 0x1d, 0x00, 0x21, 0xab, 0x1a, 0x93, 0x92, 0x16, 0x68, 0x6d, 0x0a,
-	0x35, 0xf2, 0x35, 0x2d, 0x50, 0x6d, 0x48, 0xf5, 0xdb, 0xd7, 0x25, 0xc5, 0x8b, 0x03, 0x03, 0xac,
-	0x32, 0x45, 0xbe, 0xd4, 0xa1, 0x53, 0x64, 0xdf, 0x90, 0x06, 0x30, 0x49, 0xd2, 0x1e, 0xda, 0xb4,
-	0x78, 0xb1, 0xaf, 0xca, 0x55, 0xa6, 0xc6, 0x4c, 0x37, 0xb4, 0x2c, 0x31, 0xb7, 0x76, 0x74, 0x25,
-	0x79, 0x03, 0x7c, 0x21, 0xa9, 0x6c, 0x84, 0xf6, 0x21, 0x88, 0x6d, 0x45, 0x1e, 0x81, 0x6f, 0x7a,
-	0x6a, 0x13, 0x46, 0x8b, 0xf1, 0x53, 0xfb, 0xa9, 0x30, 0x1f, 0x86, 0xd8, 0xb2, 0x4a, 0x67, 0xda,
-	0x6b, 0x57, 0xf6, 0x74, 0xe6, 0xd5, 0x88, 0x2d, 0x4b, 0xa6, 0xd0, 0x57, 0xb3, 0x68, 0x97, 0x46,
-	0x8b, 0xa3, 0x4e, 0xa5, 0x22, 0x14, 0x6b, 0x46, 0xb9, 0x92, 0xd6, 0x48, 0x25, 0x66, 0x09, 0x95,
-	0xd1, 0xc8, 0xb8, 0x62, 0x91, 0xa5, 0x54, 0x74, 0xc3, 0xb3, 0x8e, 0x3e, 0x32, 0xb4, 0x45, 0x96,
-	0x72, 0xf6, 0x83, 0x03, 0xe1, 0x97, 0x4c, 0xc8, 0x6f, 0x1a, 0xac, 0x5b, 0x72, 0x0f, 0x06, 0x39,
-	0x2b, 0x98, 0xb4, 0xb9, 0x34, 0x05, 0x21, 0xd0, 0xe7, 0xf4, 0xd2, 0x38, 0xee, 0xc5, 0xfa, 0x5a,
-	0x61, 0xa2, 0xaa, 0xa5, 0x0d, 0xa4, 0xbe, 0x56, 0x77, 0xbf, 0xdc, 0x60, 0x8d, 0x36, 0x80, 0xa6,
-	0x50, 0x9b, 0x9a, 0xa1, 0xb5, 0xb7, 0x7b, 0x9b, 0x9a, 0xa7, 0x1c, 0x77, 0x5f, 0xcf, 0x14, 0x86,
-	0x31, 0xbe, 0x68, 0x50, 0x48, 0xf2, 0x21, 0x40, 0xce, 0x84, 0x4c, 0x5e, 0xa8, 0xa1, 0xf4, 0x2c,
-	0xa3, 0xc5, 0xeb, 0xdd, 0x6d, 0x37, 0xd3, 0xc6, 0x61, 0x7e, 0x33, 0xf8, 0xae, 0x89, 0x7b, 0x67,
-	0x93, 0x1f, 0x1d, 0x08, 0x62, 0x14, 0xbc, 0x2a, 0x05, 0xaa, 0x79, 0xb7, 0x34, 0xb7, 0x11, 0x0b,
-	0x62, 0x53, 0x28, 0x54, 0x56, 0x92, 0xe6, 0x76, 0x5d, 0x53, 0xec, 0x35, 0xf0, 0xee, 0x6a, 0x40,
-	0xe6, 0x30, 0x34, 0x57, 0x22, 0xea, 0x4f, 0xbd, 0x5b, 0x84, 0x1d, 0xbd, 0xf8, 0xc5, 0x85, 0xa1,
-	0xc1, 0x04, 0xf9, 0x08, 0xe0, 0x1c, 0xf3, 0x8b, 0x6f, 0xf5, 0x63, 0x21, 0xc7, 0xdd, 0x2d, 0xd6,
-	0x8f, 0x37, 0x4f, 0x76, 0x80, 0x19, 0x7d, 0xd6, 0x23, 0x8f, 0xa1, 0xaf, 0x9c, 0x38, 0x4c, 0xfc,
-	0x3e, 0x78, 0x9f, 0xe3, 0x81, 0xda, 0x27, 0xe0, 0x9f, 0xea, 0xf4, 0x1c, 0x2c, 0xff, 0x2f, 0x63,
-	0x3f, 0x01, 0xff, 0x33, 0xcc, 0xf1, 0x40, 0xf9, 0xa7, 0xd1, 0x6f, 0x57, 0x13, 0xe7, 0xd5, 0xd5,
-	0xc4, 0xf9, 0xe3, 0x6a, 0xe2, 0xfc, 0x74, 0x3d, 0xe9, 0xbd, 0xba, 0x9e, 0xf4, 0x7e, 0xbf, 0x9e,
-	0xf4, 0xd6, 0xbe, 0xfe, 0xef, 0xfd, 0xf8, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0xdd, 0x64, 0x08,
-	0x18, 0x98, 0x07, 0x00, 0x00,
+	// 980 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcd, 0x8e, 0x1b, 0x45,
+	0x10, 0x5e, 0x7b, 0xec, 0xf1, 0x4c, 0x6d, 0xd6, 0xbb, 0xb4, 0xf2, 0x33, 0x01, 0x62, 0x56, 0x06,
+	0x22, 0x2b, 0x51, 0x12, 0x58, 0xc4, 0x8d, 0xcb, 0xb2, 0x04, 0x64, 0xf1, 0xa3, 0x65, 0x9c, 0x88,
+	0xe3, 0xa8, 0x3d, 0x53, 0x5e, 0xb7, 0x32, 0x3f, 0x9d, 0xee, 0x1e, 0x87, 0x79, 0x02, 0xae, 0x9c,
+	0x39, 0x70, 0x80, 0x57, 0xe0, 0x21, 0x38, 0xe6, 0xc8, 0x11, 0xed, 0xbe, 0x03, 0x67, 0xd4, 0x3f,
+	0xb3, 0xf6, 0x2a, 0x51, 0x64, 0x24, 0x4e, 0x9e, 0xfa, 0xbe, 0x6f, 0xaa, 0xaa, 0xbf, 0xae, 0x1a,
+	0x19, 0x6e, 0x73, 0x51, 0xa9, 0xea, 0x51, 0x5a, 0x95, 0x0b, 0x76, 0xe6, 0x7e, 0x1e, 0x1a, 0x8c,
+	0xf8, 0x36, 0x1a, 0xff, 0xde, 0x05, 0xff, 0x38, 0x67, 0x9c, 0x36, 0x64, 0x08, 0x5d, 0x96, 0x45,
+	0x9d, 0xc3, 0xce, 0xc4, 0x8b, 0xbb, 0x2c, 0x23, 0x37, 0xc0, 0xa7, 0x9c, 0x27, 0x2c, 0x8b, 0xba,
+	0x87, 0x9d, 0x49, 0x18, 0xf7, 0x29, 0xe7, 0xd3, 0x8c, 0xbc, 0x07, 0xbb, 0x5c, 0xb0, 0x15, 0x55,
+	0x98, 0x3c, 0xc3, 0x26, 0xf2, 0x0c, 0x07, 0x0e, 0xfa, 0x1a, 0x1b, 0x72, 0x1f, 0x08, 0xcd, 0x59,
+	0xc2, 0x69, 0x93, 0xf0, 0x7a, 0x9e, 0xb3, 0xd4, 0xe8, 0x7a, 0x46, 0xb7, 0x4f, 0x73, 0x76, 0x4a,
+	0x9b, 0x53, 0x83, 0x6b, 0xf1, 0x3b, 0x10, 0x4a, 0x76, 0x56, 0x26, 0xaa, 0xe1, 0x18, 0xf5, 0x8d,
+	0x26, 0xd0, 0xc0, 0x93, 0x86, 0x23, 0xf9, 0x00, 0x86, 0xba, 0x03, 0x5a, 0xab, 0x65, 0xa2, 0xaa,
+	0x67, 0x58, 0x46, 0xbe, 0x51, 0x5c, 0xa3, 0x9c, 0x1f, 0xd7, 0x6a, 0xf9, 0x44, 0x63, 0xe4, 0x53,
+	0xb8, 0x25, 0x1b, 0x99, 0x48, 0x14, 0x2b, 0x96, 0x62, 0xc2, 0x45, 0xb5, 0x62, 0x19, 0x0a, 0xdd,
+	0xf8, 0xc0, 0xc8, 0xaf, 0xcb, 0x46, 0xce, 0x2c, 0x7b, 0xea, 0xc8, 0x69, 0x46, 0x0e, 0xc0, 0x5b,
+	0x20, 0x46, 0x81, 0x39, 0xaf, 0x7e, 0x24, 0x11, 0x0c, 0x24, 0x2d, 0xb3, 0x79, 0xf5, 0x63, 0x14,
+	0x1e, 0x76, 0x26, 0x41, 0xdc, 0x86, 0xe3, 0x5f, 0xba, 0xe0, 0xff, 0x80, 0xe9, 0x92, 0xaa, 0x6d,
+	0x5d, 0xba, 0x01, 0x7e, 0x91, 0x2e, 0x35, 0x6c, 0x0d, 0xea, 0x17, 0xe9, 0x72, 0x9a, 0x91, 0x5b,
+	0x30, 0xa0, 0x9c, 0x6d, 0x18, 0xe2, 0x53, 0xce, 0xb4, 0x0f, 0xef, 0x02, 0xc8, 0x7a, 0x9e, 0xb8,
+	0x54, 0xad, 0x11, 0xf5, 0xfc, 0xd8, 0x64, 0x73, 0xac, 0xcb, 0xe8, 0x5f, 0xb2, 0xdf, 0x9a, 0xa4,
+	0xb7, 0x21, 0xe0, 0x58, 0x24, 0x29, 0x0a, 0xe5, 0x4e, 0x3c, 0xe0, 0x58, 0x9c, 0xa0, 0x50, 0xba,
+	0x9e, 0xa6, 0x74, 0xbd, 0xc0, 0xd6, 0xe3, 0x58, 0xe8, 0x7a, 0xee, 0xf4, 0xe1, 0x6b, 0x4f, 0x0f,
+	0x57, 0x4e, 0x4f, 0xee, 0x00, 0xe8, 0xbe, 0x24, 0xa6, 0x02, 0x55, 0xb4, 0x6b, 0xf2, 0x84, 0x94,
+	0xf3, 0x99, 0x01, 0xc6, 0xbf, 0x76, 0xa1, 0x37, 0x4d, 0xe7, 0xe9, 0xff, 0x36, 0x40, 0x77, 0x61,
+	0x9f, 0xa5, 0xf3, 0xf4, 0xd5, 0xe9, 0xd9, 0xd3, 0xf0, 0x96, 0xb3, 0x33, 0x81, 0x03, 0x81, 0xaa,
+	0x16, 0x65, 0xb2, 0xd6, 0x58, 0xe3, 0x86, 0x16, 0x9f, 0xb5, 0x4a, 0x7d, 0x55, 0x9b, 0xe3, 0xd2,
+	0x2f, 0x50, 0x6c, 0x78, 0x6e, 0xa9, 0x60, 0xed, 0xf9, 0xe6, 0xf4, 0x6c, 0xe3, 0xdf, 0xf8, 0x1f,
+	0x0f, 0xfc, 0x13, 0xb3, 0x6e, 0x1b, 0x16, 0x85, 0xc6, 0xa2, 0x3b, 0x00, 0x52, 0x55, 0x02, 0x93,
+	0x92, 0x16, 0xe8, 0x6c, 0x0a, 0x0d, 0xf2, 0x1d, 0x2d, 0x50, 0x9f, 0x90, 0x9a, 0xe5, 0x6c, 0x07,
+	0xc9, 0x8b, 0x03, 0x0b, 0x4c, 0x33, 0x4d, 0xbe, 0x30, 0x33, 0xa9, 0xc9, 0x9e, 0x25, 0x2d, 0x60,
+	0x07, 0xcd, 0x78, 0xe8, 0x86, 0xc9, 0x8b, 0x7d, 0x1d, 0x4e, 0x33, 0xdd, 0x66, 0xba, 0xa4, 0x65,
+	0x89, 0xb9, 0xb3, 0xa3, 0x0d, 0xc9, 0x4d, 0xf0, 0xa5, 0xa2, 0xaa, 0x96, 0xc6, 0x87, 0x20, 0x76,
+	0x11, 0xb9, 0x0b, 0xbe, 0xad, 0x69, 0x4c, 0xd8, 0x3d, 0x1a, 0x3e, 0x74, 0x5f, 0x12, 0xfb, 0xdd,
+	0x88, 0x1d, 0xab, 0x75, 0xb6, 0x7c, 0x14, 0x5e, 0xd5, 0xd9, 0xcd, 0x89, 0x1d, 0x4b, 0x0e, 0xa1,
+	0xa7, 0x7b, 0x31, 0x2e, 0xed, 0x1e, 0x5d, 0x6b, 0x55, 0x7a, 0x84, 0x62, 0xc3, 0x68, 0x57, 0x52,
+	0x81, 0x54, 0x61, 0x96, 0xd0, 0xcb, 0x81, 0x73, 0xc8, 0xb1, 0xd2, 0x74, 0xcd, 0xb3, 0x96, 0xbe,
+	0x66, 0x69, 0x87, 0x58, 0xba, 0xac, 0x14, 0x5b, 0x34, 0x49, 0x2d, 0xf2, 0x68, 0xcf, 0xd2, 0x16,
+	0x79, 0x2a, 0x72, 0xf2, 0x3e, 0xec, 0x39, 0xda, 0x0d, 0xf4, 0xd0, 0x7e, 0x53, 0x2c, 0x68, 0x67,
+	0x5a, 0xe7, 0x70, 0xd3, 0xa3, 0x73, 0xec, 0xdb, 0x1c, 0x16, 0xd1, 0x39, 0x3e, 0x84, 0x21, 0x17,
+	0xd5, 0x82, 0xa9, 0x44, 0x2e, 0xa9, 0x60, 0xe5, 0x59, 0x74, 0x60, 0x2c, 0xdb, 0xb3, 0xe8, 0xcc,
+	0x82, 0xe3, 0x9f, 0x3a, 0x10, 0x7e, 0xc3, 0xa4, 0xfa, 0xbe, 0x46, 0xd1, 0x90, 0xeb, 0xd0, 0xcf,
+	0x59, 0xc1, 0x94, 0xdb, 0x10, 0x1b, 0x10, 0x02, 0x3d, 0x4e, 0xcf, 0xec, 0xdd, 0x7b, 0xb1, 0x79,
+	0xd6, 0x98, 0xac, 0x84, 0x72, 0xab, 0x61, 0x9e, 0xf5, 0xdb, 0x2f, 0x96, 0x28, 0xd0, 0xad, 0x82,
+	0x0d, 0xb4, 0xe7, 0xd6, 0xbe, 0xa8, 0x7f, 0xd5, 0x73, 0x3b, 0x6f, 0x71, 0xfb, 0x99, 0x4f, 0x61,
+	0x10, 0xe3, 0xf3, 0x1a, 0xa5, 0x22, 0x1f, 0x01, 0xe4, 0x4c, 0xaa, 0xe4, 0xb9, 0x6e, 0xca, 0xf4,
+	0xb2, 0x7b, 0xf4, 0x56, 0xfb, 0xda, 0x65, 0xb7, 0x71, 0x98, 0x5f, 0x36, 0xbe, 0x2e, 0xd2, 0x7d,
+	0x63, 0x91, 0x3f, 0x3a, 0x10, 0xc4, 0x28, 0x79, 0x55, 0x4a, 0xd4, 0xfd, 0xae, 0x68, 0xee, 0x86,
+	0x3d, 0x88, 0x6d, 0xa0, 0x51, 0x55, 0x29, 0x9a, 0xbb, 0xe3, 0xda, 0x60, 0xa3, 0x80, 0xf7, 0xa6,
+	0x02, 0x64, 0x02, 0x03, 0xfb, 0x24, 0xa3, 0xde, 0xa1, 0xf7, 0x1a, 0x61, 0x4b, 0x93, 0x7b, 0xe0,
+	0xa3, 0x10, 0x95, 0x90, 0x51, 0xdf, 0x08, 0x49, 0x2b, 0xfc, 0x92, 0x61, 0x9e, 0x3d, 0xd6, 0x54,
+	0xec, 0x14, 0xe3, 0xcf, 0x00, 0xd6, 0xa8, 0xee, 0x70, 0xa1, 0x23, 0xb7, 0xa4, 0x36, 0xd0, 0x5b,
+	0x53, 0xa0, 0x94, 0xed, 0x45, 0x85, 0x71, 0x1b, 0x8e, 0x1f, 0x41, 0xff, 0xf1, 0x0a, 0x4b, 0xf5,
+	0xca, 0x6a, 0xdf, 0x04, 0x9f, 0xa6, 0x8a, 0x55, 0xa5, 0x7b, 0xc3, 0x45, 0x47, 0xbf, 0x75, 0x61,
+	0x70, 0xe2, 0xda, 0xfc, 0x18, 0x60, 0x86, 0xf9, 0xe2, 0xa9, 0x99, 0x5d, 0xb2, 0xdf, 0x36, 0xe9,
+	0xae, 0xea, 0xed, 0x83, 0x35, 0x60, 0x5d, 0x1d, 0xef, 0x90, 0xfb, 0xd0, 0xd3, 0x97, 0xb4, 0x9d,
+	0xf8, 0x1e, 0x78, 0x5f, 0xe1, 0x96, 0xda, 0x07, 0xe0, 0x9f, 0x98, 0x15, 0xdb, 0x5a, 0xfe, 0x5f,
+	0xda, 0x7e, 0x00, 0xfe, 0x17, 0x98, 0xe3, 0x96, 0xf2, 0xcf, 0xa3, 0x3f, 0xcf, 0x47, 0x9d, 0x97,
+	0xe7, 0xa3, 0xce, 0xdf, 0xe7, 0xa3, 0xce, 0xcf, 0x17, 0xa3, 0x9d, 0x97, 0x17, 0xa3, 0x9d, 0xbf,
+	0x2e, 0x46, 0x3b, 0x73, 0xdf, 0xfc, 0x7f, 0xf9, 0xe4, 0xdf, 0x01, 0x00, 0x94, 0xdd, 0xcd, 0x74,
+	0xdc, 0x08, 0x00, 0x00,
 }
 
 func (m *Alipay) Marshal() (dAtA []byte, err error) {
@@ -864,6 +1029,13 @@ func (m *Wechat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.AppSecret) > 0 {
+		i -= len(m.AppSecret)
+		copy(dAtA[i:], m.AppSecret)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.AppSecret)))
+		i--
+		dAtA[i] = 0x5a
+	}
 	if m.Sandbox {
 		i--
 		if m.Sandbox {
@@ -1048,6 +1220,39 @@ func (m *Config) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ProfitSharing {
+		i--
+		if m.ProfitSharing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if len(m.ReturnUrl) > 0 {
+		i -= len(m.ReturnUrl)
+		copy(dAtA[i:], m.ReturnUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.ReturnUrl)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.NotifySecret) > 0 {
+		i -= len(m.NotifySecret)
+		copy(dAtA[i:], m.NotifySecret)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.NotifySecret)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if len(m.NotifyUrl) > 0 {
+		i -= len(m.NotifyUrl)
+		copy(dAtA[i:], m.NotifyUrl)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.NotifyUrl)))
+		i--
+		dAtA[i] = 0x6a
+	}
 	if len(m.UpdatedAt) > 0 {
 		i -= len(m.UpdatedAt)
 		copy(dAtA[i:], m.UpdatedAt)
@@ -1273,6 +1478,20 @@ func (m *Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Errors) > 0 {
+		for iNdEx := len(m.Errors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Errors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintConfig(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if len(m.Configs) > 0 {
 		for iNdEx := len(m.Configs) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1317,6 +1536,80 @@ func (m *Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *FieldError) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FieldError) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *FieldError) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Event) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Event) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Event) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Action) > 0 {
+		i -= len(m.Action)
+		copy(dAtA[i:], m.Action)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Action)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintConfig(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintConfig(dAtA []byte, offset int, v uint64) int {
 	offset -= sovConfig(v)
 	base := offset
@@ -1413,6 +1706,10 @@ func (m *Wechat) Size() (n int) {
 	if m.Sandbox {
 		n += 2
 	}
+	l = len(m.AppSecret)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -1512,6 +1809,21 @@ func (m *Config) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovConfig(uint64(l))
 	}
+	l = len(m.NotifyUrl)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.NotifySecret)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.ReturnUrl)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	if m.ProfitSharing {
+		n += 3
+	}
 	return n
 }
 
@@ -1581,6 +1893,46 @@ func (m *Response) Size() (n int) {
 			n += 1 + l + sovConfig(uint64(l))
 		}
 	}
+	if len(m.Errors) > 0 {
+		for _, e := range m.Errors {
+			l = e.Size()
+			n += 1 + l + sovConfig(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *FieldError) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	return n
+}
+
+func (m *Event) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
+	l = len(m.Action)
+	if l > 0 {
+		n += 1 + l + sovConfig(uint64(l))
+	}
 	return n
 }
 
@@ -2204,6 +2556,38 @@ func (m *Wechat) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Sandbox = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppSecret", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppSecret = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -2937,10 +3321,126 @@ func (m *Config) Unmarshal(dAtA []byte) error {
 			}
 			m.UpdatedAt = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipConfig(dAtA[iNdEx:])
-			if err != nil {
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotifyUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NotifyUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NotifySecret", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NotifySecret = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReturnUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProfitSharing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ProfitSharing = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
 				return err
 			}
 			if skippy < 0 {
@@ -3415,6 +3915,274 @@ func (m *Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Errors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Errors = append(m.Errors, &FieldError{})
+			if err := m.Errors[len(m.Errors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FieldError) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FieldError: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FieldError: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipConfig(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Event) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowConfig
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Event: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Event: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Action", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowConfig
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthConfig
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthConfig
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Action = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipConfig(dAtA[iNdEx:])
@@ -243,32 +243,35 @@ func init() {
 func init() { proto.RegisterFile("proto/notify/notify.proto", fileDescriptor_b4f26e246bead917) }
 
 var fileDescriptor_b4f26e246bead917 = []byte{
-	// 393 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0xcb, 0x4e, 0xf2, 0x40,
-	0x14, 0xc7, 0x7b, 0xa3, 0xc0, 0xe1, 0x4b, 0x3e, 0x32, 0x0b, 0x33, 0xa0, 0x69, 0x48, 0x57, 0xc4,
-	0x04, 0x34, 0xe0, 0xc2, 0xb8, 0xf3, 0x42, 0x70, 0x65, 0x48, 0x37, 0xae, 0x8b, 0x1d, 0x2c, 0xb1,
-	0x32, 0xb5, 0x33, 0x35, 0xe9, 0x5b, 0xf8, 0x42, 0x2e, 0xdc, 0xb9, 0x64, 0xe9, 0xd2, 0xc0, 0x8b,
-	0x98, 0x99, 0x0e, 0x97, 0x34, 0x2c, 0x8c, 0xba, 0xe2, 0x5c, 0xfe, 0xe7, 0xf0, 0x9f, 0xdf, 0x49,
-	0xa1, 0x11, 0x27, 0x94, 0xd3, 0xa3, 0x19, 0xe5, 0xd3, 0x49, 0xa6, 0x7e, 0xba, 0xb2, 0x86, 0xec,
-	0x3c, 0x73, 0x8f, 0xc1, 0x1a, 0xf9, 0xd3, 0x04, 0xd5, 0xc1, 0x7c, 0x20, 0x19, 0xd6, 0x5b, 0x7a,
-	0xbb, 0xea, 0x89, 0x10, 0xed, 0x81, 0xfd, 0xec, 0x47, 0x29, 0x61, 0xd8, 0x68, 0x99, 0xed, 0xaa,
-	0xa7, 0x32, 0xf7, 0xcd, 0x84, 0xb2, 0x47, 0x9e, 0x52, 0xc2, 0xb8, 0xd0, 0x3c, 0x12, 0x1e, 0xd2,
-	0x40, 0x0d, 0xaa, 0x0c, 0x21, 0xb0, 0x62, 0x9f, 0x87, 0xd8, 0x90, 0x55, 0x19, 0xa3, 0x3e, 0xd8,
-	0x21, 0xf1, 0x03, 0x92, 0x60, 0xb3, 0x65, 0xb6, 0x6b, 0xbd, 0xfd, 0xae, 0x32, 0xa4, 0x96, 0x75,
-	0xaf, 0x65, 0x77, 0x30, 0xe3, 0x49, 0xe6, 0x29, 0x29, 0x3a, 0x04, 0xf3, 0x9e, 0x70, 0x6c, 0xc9,
-	0x09, 0x5c, 0x9c, 0x18, 0x12, 0x9e, 0xcb, 0x85, 0x08, 0x75, 0xc0, 0x8a, 0x29, 0xe3, 0xb8, 0x24,
-	0xc5, 0x8d, 0xa2, 0x78, 0x44, 0x99, 0x52, 0x4b, 0x99, 0xf0, 0x38, 0xa6, 0x41, 0x86, 0xed, 0xdc,
-	0xa3, 0x88, 0x05, 0x85, 0x34, 0x89, 0x70, 0x39, 0xa7, 0x90, 0x26, 0x51, 0x73, 0x08, 0xb5, 0x2d,
-	0x5f, 0x3b, 0x30, 0xb9, 0x50, 0x92, 0x60, 0xe4, 0x5b, 0x6b, 0xbd, 0x7f, 0xab, 0xbf, 0x15, 0x54,
-	0xbd, 0xbc, 0x75, 0x66, 0x9c, 0xea, 0xcd, 0x2b, 0xa8, 0xac, 0xec, 0xfe, 0x62, 0xcb, 0x00, 0xaa,
-	0xeb, 0x77, 0xfc, 0x7c, 0x8d, 0xfb, 0xaa, 0x43, 0xc5, 0x23, 0x2c, 0xa6, 0x33, 0x46, 0x90, 0x03,
-	0xc0, 0xb8, 0xcf, 0x53, 0x76, 0x49, 0x03, 0x22, 0xb7, 0x95, 0xbc, 0xad, 0x0a, 0x3a, 0x59, 0x1f,
-	0xce, 0x90, 0x64, 0x0f, 0x36, 0x64, 0xf3, 0x0d, 0x3b, 0x2f, 0xb7, 0xc2, 0x6b, 0x6e, 0xf0, 0xfe,
-	0x19, 0xcc, 0xde, 0x04, 0xec, 0x1b, 0xd9, 0x41, 0x1d, 0xb0, 0xcf, 0xa3, 0x69, 0xec, 0x67, 0xe8,
-	0x7f, 0xe1, 0xe0, 0xcd, 0x7a, 0xd1, 0xa7, 0xab, 0x09, 0xf9, 0x2d, 0xb9, 0x0b, 0x7d, 0xfe, 0x2d,
-	0xf9, 0x05, 0x7e, 0x5f, 0x38, 0xfa, 0x7c, 0xe1, 0xe8, 0x9f, 0x0b, 0x47, 0x7f, 0x59, 0x3a, 0xda,
-	0x7c, 0xe9, 0x68, 0x1f, 0x4b, 0x47, 0x1b, 0xdb, 0xf2, 0x33, 0xea, 0x7f, 0x05, 0x00, 0x00, 0xff,
-	0xff, 0x00, 0x6e, 0x23, 0x92, 0x63, 0x03, 0x00, 0x00,
+	// 438 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xbf, 0x6f, 0xd3, 0x40,
+	0x14, 0xce, 0xe5, 0x1c, 0xa7, 0x79, 0xa9, 0x44, 0x75, 0x03, 0xba, 0x06, 0x64, 0x45, 0x9e, 0xa2,
+	0x42, 0x03, 0x4a, 0x18, 0x10, 0x1b, 0x94, 0xaa, 0x74, 0x41, 0xd1, 0x2d, 0xcc, 0x97, 0xf8, 0x15,
+	0x47, 0x04, 0x9f, 0xf1, 0x9d, 0x11, 0xfe, 0x2f, 0xf8, 0x87, 0x18, 0xd8, 0x3a, 0x76, 0x64, 0x44,
+	0xc9, 0x3f, 0x82, 0xee, 0x7c, 0x69, 0xab, 0x28, 0x83, 0x05, 0x4c, 0x7e, 0x3f, 0xbe, 0xf7, 0xdd,
+	0xf7, 0xbe, 0x27, 0x19, 0x8e, 0xf3, 0x42, 0x19, 0xf5, 0x2c, 0x53, 0x66, 0x79, 0x55, 0xf9, 0xcf,
+	0xd8, 0xd5, 0x58, 0x58, 0x67, 0xf1, 0x73, 0x08, 0x66, 0x72, 0x59, 0xb0, 0x23, 0xa0, 0x9f, 0xb0,
+	0xe2, 0x64, 0x48, 0x46, 0x3d, 0x61, 0x43, 0xf6, 0x10, 0xc2, 0xaf, 0x72, 0x55, 0xa2, 0xe6, 0xed,
+	0x21, 0x1d, 0xf5, 0x84, 0xcf, 0xe2, 0x9f, 0x14, 0xba, 0x02, 0xbf, 0x94, 0xa8, 0x8d, 0xc5, 0x7c,
+	0x46, 0x93, 0xaa, 0xc4, 0x0f, 0xfa, 0x8c, 0x31, 0x08, 0x72, 0x69, 0x52, 0xde, 0x76, 0x55, 0x17,
+	0xb3, 0x29, 0x84, 0x29, 0xca, 0x04, 0x0b, 0x4e, 0x87, 0x74, 0xd4, 0x9f, 0x3c, 0x1a, 0x7b, 0x41,
+	0x9e, 0x6c, 0xfc, 0xce, 0x75, 0xcf, 0x33, 0x53, 0x54, 0xc2, 0x43, 0xd9, 0x09, 0xd0, 0x8f, 0x68,
+	0x78, 0xe0, 0x26, 0xf8, 0xee, 0xc4, 0x05, 0x9a, 0x1a, 0x6e, 0x41, 0xec, 0x14, 0x82, 0x5c, 0x69,
+	0xc3, 0x3b, 0x0e, 0x7c, 0xbc, 0x0b, 0x9e, 0x29, 0xed, 0xd1, 0x0e, 0x66, 0x35, 0xce, 0x55, 0x52,
+	0xf1, 0xb0, 0xd6, 0x68, 0x63, 0xeb, 0x42, 0x59, 0xac, 0x78, 0xb7, 0x76, 0xa1, 0x2c, 0x56, 0x83,
+	0x0b, 0xe8, 0xdf, 0xd3, 0xb5, 0xc7, 0xa6, 0x18, 0x3a, 0xce, 0x18, 0xb7, 0x6b, 0x7f, 0x72, 0xb8,
+	0x7d, 0xd6, 0xba, 0x2a, 0xea, 0xd6, 0xab, 0xf6, 0x4b, 0x32, 0x78, 0x0b, 0x07, 0x5b, 0xb9, 0xff,
+	0xc0, 0x72, 0x0e, 0xbd, 0xdb, 0x3d, 0xfe, 0x9e, 0x26, 0xfe, 0x41, 0xe0, 0x40, 0xa0, 0xce, 0x55,
+	0xa6, 0x91, 0x45, 0x00, 0xda, 0x48, 0x53, 0xea, 0x33, 0x95, 0xa0, 0x63, 0xeb, 0x88, 0x7b, 0x15,
+	0xf6, 0xe2, 0xf6, 0x70, 0x6d, 0xe7, 0xec, 0xe3, 0x3b, 0x67, 0x6b, 0x86, 0xbd, 0x97, 0xdb, 0xda,
+	0x4b, 0xef, 0xec, 0xfd, 0x6f, 0x66, 0x4e, 0xae, 0x09, 0x84, 0xef, 0x5d, 0x8b, 0x9d, 0x42, 0xf8,
+	0x7a, 0xb5, 0xcc, 0x65, 0xc5, 0x1e, 0xec, 0x5c, 0x7c, 0x70, 0xb4, 0x2b, 0x34, 0x6e, 0x59, 0xf8,
+	0x07, 0x5c, 0xa4, 0xd2, 0x34, 0x83, 0x3f, 0x81, 0xe0, 0x72, 0x31, 0x5f, 0x34, 0x03, 0x4f, 0xe1,
+	0xb0, 0xe6, 0x16, 0x78, 0x55, 0x66, 0x49, 0xa3, 0xa1, 0xc9, 0x02, 0xba, 0x67, 0x52, 0xa7, 0x4b,
+	0x2c, 0xd8, 0x53, 0xe8, 0x5c, 0x66, 0x09, 0x7e, 0x6b, 0xf6, 0xda, 0x09, 0xd0, 0x59, 0xc3, 0xad,
+	0xdf, 0xf0, 0xeb, 0x75, 0x44, 0x6e, 0xd6, 0x11, 0xf9, 0xbd, 0x8e, 0xc8, 0xf7, 0x4d, 0xd4, 0xba,
+	0xd9, 0x44, 0xad, 0x5f, 0x9b, 0xa8, 0x35, 0x0f, 0xdd, 0xef, 0x60, 0xfa, 0x67, 0x00, 0xfb, 0x36,
+	0x0b, 0xb2, 0x2b, 0x04, 0x00, 0x00,
 }
 
 func (m *Pair) Marshal() (dAtA []byte, err error) {
@@ -38,6 +38,10 @@ type NotifyService interface {
 	Alipay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 	// Wechat 异步通知
 	Wechat(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	// Icbc 异步通知
+	Icbc(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	// WechatRefund 微信退款异步通知
+	WechatRefund(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 }
 
 type notifyService struct {
@@ -72,6 +76,26 @@ func (c *notifyService) Wechat(ctx context.Context, in *Request, opts ...client.
 	return out, nil
 }
 
+func (c *notifyService) Icbc(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Notify.Icbc", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notifyService) WechatRefund(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Notify.WechatRefund", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Notify service
 
 type NotifyHandler interface {
@@ -79,12 +103,18 @@ type NotifyHandler interface {
 	Alipay(context.Context, *Request, *Response) error
 	// Wechat 异步通知
 	Wechat(context.Context, *Request, *Response) error
+	// Icbc 异步通知
+	Icbc(context.Context, *Request, *Response) error
+	// WechatRefund 微信退款异步通知
+	WechatRefund(context.Context, *Request, *Response) error
 }
 
 func RegisterNotifyHandler(s server.Server, hdlr NotifyHandler, opts ...server.HandlerOption) error {
 	type notify interface {
 		Alipay(ctx context.Context, in *Request, out *Response) error
 		Wechat(ctx context.Context, in *Request, out *Response) error
+		Icbc(ctx context.Context, in *Request, out *Response) error
+		WechatRefund(ctx context.Context, in *Request, out *Response) error
 	}
 	type Notify struct {
 		notify
@@ -104,3 +134,85 @@ func (h *notifyHandler) Alipay(ctx context.Context, in *Request, out *Response)
 func (h *notifyHandler) Wechat(ctx context.Context, in *Request, out *Response) error {
 	return h.NotifyHandler.Wechat(ctx, in, out)
 }
+
+func (h *notifyHandler) Icbc(ctx context.Context, in *Request, out *Response) error {
+	return h.NotifyHandler.Icbc(ctx, in, out)
+}
+
+func (h *notifyHandler) WechatRefund(ctx context.Context, in *Request, out *Response) error {
+	return h.NotifyHandler.WechatRefund(ctx, in, out)
+}
+
+// Client API for Cashier service
+
+type CashierService interface {
+	// Index 收银台首页 根据扫码客户端授权并展示输入金额页面
+	Index(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	// Pay 收银台下单 返回调起支付参数或支付跳转地址
+	Pay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+}
+
+type cashierService struct {
+	c    client.Client
+	name string
+}
+
+func NewCashierService(name string, c client.Client) CashierService {
+	return &cashierService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *cashierService) Index(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Cashier.Index", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cashierService) Pay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Cashier.Pay", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Cashier service
+
+type CashierHandler interface {
+	// Index 收银台首页 根据扫码客户端授权并展示输入金额页面
+	Index(context.Context, *Request, *Response) error
+	// Pay 收银台下单 返回调起支付参数或支付跳转地址
+	Pay(context.Context, *Request, *Response) error
+}
+
+func RegisterCashierHandler(s server.Server, hdlr CashierHandler, opts ...server.HandlerOption) error {
+	type cashier interface {
+		Index(ctx context.Context, in *Request, out *Response) error
+		Pay(ctx context.Context, in *Request, out *Response) error
+	}
+	type Cashier struct {
+		cashier
+	}
+	h := &cashierHandler{hdlr}
+	return s.Handle(s.NewHandler(&Cashier{h}, opts...))
+}
+
+type cashierHandler struct {
+	CashierHandler
+}
+
+func (h *cashierHandler) Index(ctx context.Context, in *Request, out *Response) error {
+	return h.CashierHandler.Index(ctx, in, out)
+}
+
+func (h *cashierHandler) Pay(ctx context.Context, in *Request, out *Response) error {
+	return h.CashierHandler.Pay(ctx, in, out)
+}
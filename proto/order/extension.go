@@ -41,3 +41,21 @@ func (p *Order) BeforeUpdate(scope *gorm.Scope) (err error) {
 	}
 	return nil
 }
+
+// BeforeCreate 插入前数据处理
+func (p *OrderEvent) BeforeCreate(scope *gorm.Scope) (err error) {
+	err = scope.SetColumn("CreatedAt", dateTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// BeforeCreate 插入前数据处理
+func (p *ChannelLog) BeforeCreate(scope *gorm.Scope) (err error) {
+	err = scope.SetColumn("CreatedAt", dateTime())
+	if err != nil {
+		return err
+	}
+	return nil
+}
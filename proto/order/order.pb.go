@@ -23,23 +23,26 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Order struct {
-	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	StoreId    string `protobuf:"bytes,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
-	Channel    string `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
-	AuthCode   string `protobuf:"bytes,4,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`
-	Title      string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
-	TotalFee   int64  `protobuf:"varint,6,opt,name=total_fee,json=totalFee,proto3" json:"total_fee,omitempty"`
-	Fee        int64  `protobuf:"varint,7,opt,name=fee,proto3" json:"fee,omitempty"`
-	OutTradeNo string `protobuf:"bytes,8,opt,name=out_trade_no,json=outTradeNo,proto3" json:"out_trade_no,omitempty"`
-	TradeNo    string `protobuf:"bytes,9,opt,name=trade_no,json=tradeNo,proto3" json:"trade_no,omitempty"`
-	OperatorId string `protobuf:"bytes,10,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
-	TerminalId string `protobuf:"bytes,11,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
-	Status     int64  `protobuf:"varint,12,opt,name=status,proto3" json:"status,omitempty"`
-	LinkId     string `protobuf:"bytes,13,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
-	RefundFee  int64  `protobuf:"varint,14,opt,name=refund_fee,json=refundFee,proto3" json:"refund_fee,omitempty"`
-	Attach     string `protobuf:"bytes,15,opt,name=attach,proto3" json:"attach,omitempty"`
-	CreatedAt  string `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt  string `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StoreId     string `protobuf:"bytes,2,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	Channel     string `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
+	AuthCode    string `protobuf:"bytes,4,opt,name=auth_code,json=authCode,proto3" json:"auth_code,omitempty"`
+	Title       string `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	TotalFee    int64  `protobuf:"varint,6,opt,name=total_fee,json=totalFee,proto3" json:"total_fee,omitempty"`
+	Fee         int64  `protobuf:"varint,7,opt,name=fee,proto3" json:"fee,omitempty"`
+	OutTradeNo  string `protobuf:"bytes,8,opt,name=out_trade_no,json=outTradeNo,proto3" json:"out_trade_no,omitempty"`
+	TradeNo     string `protobuf:"bytes,9,opt,name=trade_no,json=tradeNo,proto3" json:"trade_no,omitempty"`
+	OperatorId  string `protobuf:"bytes,10,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	TerminalId  string `protobuf:"bytes,11,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
+	Status      int64  `protobuf:"varint,12,opt,name=status,proto3" json:"status,omitempty"`
+	LinkId      string `protobuf:"bytes,13,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	RefundFee   int64  `protobuf:"varint,14,opt,name=refund_fee,json=refundFee,proto3" json:"refund_fee,omitempty"`
+	Attach      string `protobuf:"bytes,15,opt,name=attach,proto3" json:"attach,omitempty"`
+	CreatedAt   string `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   string `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	State       string `protobuf:"bytes,18,opt,name=state,proto3" json:"state,omitempty"`
+	Fingerprint string `protobuf:"bytes,19,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Response    string `protobuf:"bytes,20,opt,name=response,proto3" json:"response,omitempty"`
 }
 
 func (m *Order) Reset()         { *m = Order{} }
@@ -194,6 +197,275 @@ func (m *Order) GetUpdatedAt() string {
 	return ""
 }
 
+func (m *Order) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Order) GetFingerprint() string {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return ""
+}
+
+func (m *Order) GetResponse() string {
+	if m != nil {
+		return m.Response
+	}
+	return ""
+}
+
+type OrderEvent struct {
+	Id         int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId    string `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	StoreId    string `protobuf:"bytes,3,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	OutTradeNo string `protobuf:"bytes,4,opt,name=out_trade_no,json=outTradeNo,proto3" json:"out_trade_no,omitempty"`
+	FromState  string `protobuf:"bytes,5,opt,name=from_state,json=fromState,proto3" json:"from_state,omitempty"`
+	ToState    string `protobuf:"bytes,6,opt,name=to_state,json=toState,proto3" json:"to_state,omitempty"`
+	Source     string `protobuf:"bytes,7,opt,name=source,proto3" json:"source,omitempty"`
+	Actor      string `protobuf:"bytes,8,opt,name=actor,proto3" json:"actor,omitempty"`
+	Remark     string `protobuf:"bytes,9,opt,name=remark,proto3" json:"remark,omitempty"`
+	Payload    string `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	CreatedAt  string `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (m *OrderEvent) Reset()         { *m = OrderEvent{} }
+func (m *OrderEvent) String() string { return proto.CompactTextString(m) }
+func (*OrderEvent) ProtoMessage()    {}
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_986e030a471601a2, []int{1}
+}
+func (m *OrderEvent) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OrderEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OrderEvent.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OrderEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderEvent.Merge(m, src)
+}
+func (m *OrderEvent) XXX_Size() int {
+	return m.Size()
+}
+func (m *OrderEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderEvent proto.InternalMessageInfo
+
+func (m *OrderEvent) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *OrderEvent) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetStoreId() string {
+	if m != nil {
+		return m.StoreId
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetOutTradeNo() string {
+	if m != nil {
+		return m.OutTradeNo
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetFromState() string {
+	if m != nil {
+		return m.FromState
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetToState() string {
+	if m != nil {
+		return m.ToState
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetRemark() string {
+	if m != nil {
+		return m.Remark
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
+type ChannelLog struct {
+	Id         int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId    string `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	StoreId    string `protobuf:"bytes,3,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
+	OutTradeNo string `protobuf:"bytes,4,opt,name=out_trade_no,json=outTradeNo,proto3" json:"out_trade_no,omitempty"`
+	Channel    string `protobuf:"bytes,5,opt,name=channel,proto3" json:"channel,omitempty"`
+	ApiName    string `protobuf:"bytes,6,opt,name=api_name,json=apiName,proto3" json:"api_name,omitempty"`
+	Request    string `protobuf:"bytes,7,opt,name=request,proto3" json:"request,omitempty"`
+	Response   string `protobuf:"bytes,8,opt,name=response,proto3" json:"response,omitempty"`
+	Error      string `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Latency    int64  `protobuf:"varint,10,opt,name=latency,proto3" json:"latency,omitempty"`
+	CreatedAt  string `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (m *ChannelLog) Reset()         { *m = ChannelLog{} }
+func (m *ChannelLog) String() string { return proto.CompactTextString(m) }
+func (*ChannelLog) ProtoMessage()    {}
+func (*ChannelLog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_986e030a471601a2, []int{2}
+}
+func (m *ChannelLog) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChannelLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChannelLog.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ChannelLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelLog.Merge(m, src)
+}
+func (m *ChannelLog) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChannelLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelLog.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelLog proto.InternalMessageInfo
+
+func (m *ChannelLog) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ChannelLog) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetStoreId() string {
+	if m != nil {
+		return m.StoreId
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetOutTradeNo() string {
+	if m != nil {
+		return m.OutTradeNo
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetApiName() string {
+	if m != nil {
+		return m.ApiName
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetRequest() string {
+	if m != nil {
+		return m.Request
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetResponse() string {
+	if m != nil {
+		return m.Response
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ChannelLog) GetLatency() int64 {
+	if m != nil {
+		return m.Latency
+	}
+	return 0
+}
+
+func (m *ChannelLog) GetCreatedAt() string {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return ""
+}
+
 type ListQuery struct {
 	Limit int64  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
 	Page  int64  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
@@ -206,7 +478,7 @@ func (m *ListQuery) Reset()         { *m = ListQuery{} }
 func (m *ListQuery) String() string { return proto.CompactTextString(m) }
 func (*ListQuery) ProtoMessage()    {}
 func (*ListQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_986e030a471601a2, []int{1}
+	return fileDescriptor_986e030a471601a2, []int{3}
 }
 func (m *ListQuery) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -279,7 +551,7 @@ func (m *Request) Reset()         { *m = Request{} }
 func (m *Request) String() string { return proto.CompactTextString(m) }
 func (*Request) ProtoMessage()    {}
 func (*Request) Descriptor() ([]byte, []int) {
-	return fileDescriptor_986e030a471601a2, []int{2}
+	return fileDescriptor_986e030a471601a2, []int{4}
 }
 func (m *Request) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -323,17 +595,19 @@ func (m *Request) GetOrder() *Order {
 }
 
 type Response struct {
-	Valid  bool     `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	Total  int64    `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Order  *Order   `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
-	Orders []*Order `protobuf:"bytes,4,rep,name=orders,proto3" json:"orders,omitempty"`
+	Valid       bool          `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Total       int64         `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Order       *Order        `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"`
+	Orders      []*Order      `protobuf:"bytes,4,rep,name=orders,proto3" json:"orders,omitempty"`
+	Events      []*OrderEvent `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	ChannelLogs []*ChannelLog `protobuf:"bytes,6,rep,name=channel_logs,json=channelLogs,proto3" json:"channel_logs,omitempty"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_986e030a471601a2, []int{3}
+	return fileDescriptor_986e030a471601a2, []int{5}
 }
 func (m *Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -390,8 +664,24 @@ func (m *Response) GetOrders() []*Order {
 	return nil
 }
 
+func (m *Response) GetEvents() []*OrderEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *Response) GetChannelLogs() []*ChannelLog {
+	if m != nil {
+		return m.ChannelLogs
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Order)(nil), "order.Order")
+	proto.RegisterType((*OrderEvent)(nil), "order.OrderEvent")
+	proto.RegisterType((*ChannelLog)(nil), "order.ChannelLog")
 	proto.RegisterType((*ListQuery)(nil), "order.ListQuery")
 	proto.RegisterType((*Request)(nil), "order.Request")
 	proto.RegisterType((*Response)(nil), "order.Response")
@@ -400,43 +690,60 @@ func init() {
 func init() { proto.RegisterFile("proto/order/order.proto", fileDescriptor_986e030a471601a2) }
 
 var fileDescriptor_986e030a471601a2 = []byte{
-	// 576 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcf, 0x6a, 0x14, 0x4d,
-	0x10, 0xdf, 0xd9, 0xd9, 0x9d, 0xdd, 0xa9, 0xcd, 0x97, 0xe4, 0x6b, 0x24, 0x69, 0x15, 0xc7, 0x65,
-	0xf1, 0x10, 0x09, 0x24, 0x10, 0x9f, 0x20, 0x46, 0x94, 0x05, 0x51, 0x1c, 0xf4, 0xea, 0xd0, 0x6e,
-	0x57, 0xdc, 0xc1, 0xc9, 0xf4, 0xa6, 0xa7, 0x46, 0xf1, 0x26, 0xf8, 0x02, 0xbe, 0x8f, 0x2f, 0xe0,
-	0x31, 0xe0, 0xc5, 0xa3, 0x24, 0x2f, 0x22, 0x5d, 0xdd, 0x59, 0x0f, 0x12, 0x98, 0xcb, 0xd2, 0xbf,
-	0x3f, 0x55, 0x54, 0x4d, 0xfd, 0x58, 0xd8, 0x5d, 0x59, 0x43, 0xe6, 0xd0, 0x58, 0x8d, 0xd6, 0xff,
-	0x1e, 0x30, 0x23, 0x86, 0x0c, 0x66, 0x3f, 0x63, 0x18, 0xbe, 0x74, 0x2f, 0xb1, 0x09, 0xfd, 0x52,
-	0xcb, 0x68, 0x1a, 0xed, 0xa5, 0x79, 0xbf, 0xd4, 0xe2, 0x36, 0x8c, 0x1b, 0x32, 0x16, 0x8b, 0x52,
-	0xcb, 0x3e, 0xb3, 0x23, 0xc6, 0x73, 0x2d, 0x24, 0x8c, 0x16, 0x4b, 0x55, 0xd7, 0x58, 0xc9, 0xd8,
-	0x2b, 0x01, 0x8a, 0xbb, 0x90, 0xaa, 0x96, 0x96, 0xc5, 0xc2, 0x68, 0x94, 0x03, 0xd6, 0xc6, 0x8e,
-	0x38, 0x31, 0x1a, 0xc5, 0x2d, 0x18, 0x52, 0x49, 0x15, 0xca, 0x21, 0x0b, 0x1e, 0xb8, 0x12, 0x32,
-	0xa4, 0xaa, 0xe2, 0x14, 0x51, 0x26, 0xd3, 0x68, 0x2f, 0xce, 0xc7, 0x4c, 0x3c, 0x45, 0x14, 0xdb,
-	0x10, 0x3b, 0x7a, 0xc4, 0xb4, 0x7b, 0x8a, 0x29, 0x6c, 0x98, 0x96, 0x0a, 0xb2, 0x4a, 0x63, 0x51,
-	0x1b, 0x39, 0xe6, 0x5e, 0x60, 0x5a, 0x7a, 0xed, 0xa8, 0x17, 0xc6, 0x0d, 0xbe, 0x56, 0x53, 0x3f,
-	0x1e, 0x05, 0xe9, 0x3e, 0x4c, 0xcc, 0x0a, 0xad, 0x22, 0x63, 0xdd, 0x5a, 0x10, 0x6a, 0x03, 0x35,
-	0xd7, 0xce, 0x40, 0x68, 0xcf, 0xca, 0x5a, 0x55, 0xce, 0x30, 0xf1, 0x86, 0x6b, 0x6a, 0xae, 0xc5,
-	0x0e, 0x24, 0x0d, 0x29, 0x6a, 0x1b, 0xb9, 0xc1, 0x33, 0x05, 0x24, 0x76, 0x61, 0x54, 0x95, 0xf5,
-	0x07, 0x57, 0xf4, 0x1f, 0x17, 0x25, 0x0e, 0xce, 0xb5, 0xb8, 0x07, 0x60, 0xf1, 0xb4, 0xad, 0x35,
-	0xef, 0xb7, 0xc9, 0x45, 0xa9, 0x67, 0xdc, 0x82, 0x3b, 0x90, 0x28, 0x22, 0xb5, 0x58, 0xca, 0x2d,
-	0x5f, 0xe6, 0x91, 0x2b, 0x5b, 0x58, 0x54, 0x84, 0xba, 0x50, 0x24, 0xb7, 0x59, 0x4b, 0x03, 0x73,
-	0x4c, 0x4e, 0x6e, 0x57, 0xfa, 0x5a, 0xfe, 0xdf, 0xcb, 0x81, 0x39, 0xa6, 0xd9, 0xd7, 0x08, 0xd2,
-	0xe7, 0x65, 0x43, 0xaf, 0x5a, 0xb4, 0x9f, 0xdd, 0x77, 0xaf, 0xca, 0xb3, 0x92, 0xf8, 0xb8, 0x71,
-	0xee, 0x81, 0x10, 0x30, 0x58, 0xa9, 0xf7, 0xc8, 0xb7, 0x8d, 0x73, 0x7e, 0x3b, 0xae, 0x31, 0x96,
-	0xc2, 0x55, 0xf9, 0xed, 0xaa, 0x3f, 0x2d, 0xd1, 0x5e, 0x9f, 0xd3, 0x03, 0x31, 0x03, 0x1f, 0x20,
-	0xbe, 0xe5, 0xe4, 0x68, 0xe3, 0xc0, 0x67, 0x8b, 0xa3, 0x94, 0x87, 0x6c, 0xbd, 0x85, 0x51, 0x8e,
-	0xe7, 0x2d, 0x36, 0x24, 0x0e, 0x01, 0xaa, 0xb2, 0xa1, 0xe2, 0xdc, 0x0d, 0xc4, 0x73, 0x4c, 0x8e,
-	0xb6, 0x43, 0xcd, 0x7a, 0xd0, 0x3c, 0xad, 0xd6, 0x33, 0xaf, 0xfb, 0xf7, 0x6f, 0xee, 0xff, 0x25,
-	0x82, 0x71, 0x8e, 0xcd, 0xca, 0xd4, 0x0d, 0x87, 0xeb, 0xa3, 0xaa, 0x42, 0x82, 0xc7, 0xb9, 0x07,
-	0x1c, 0x39, 0x97, 0xa5, 0xb0, 0xa5, 0x07, 0x7f, 0x9b, 0xc7, 0x37, 0x36, 0x17, 0x0f, 0x20, 0xe1,
-	0x47, 0x23, 0x07, 0xd3, 0xf8, 0x1f, 0x53, 0xd0, 0x8e, 0xbe, 0xf7, 0x21, 0x61, 0xa6, 0x11, 0xfb,
-	0x90, 0x1c, 0x9f, 0x99, 0xb6, 0x26, 0xb1, 0x19, 0xac, 0x61, 0xf9, 0x3b, 0x5b, 0x6b, 0xec, 0x67,
-	0x9d, 0xf5, 0xc4, 0x1e, 0xc4, 0xee, 0xfa, 0x1d, 0x9c, 0x0f, 0x61, 0xe0, 0x3e, 0x50, 0xc7, 0xa6,
-	0xcf, 0xb0, 0x93, 0x73, 0x1f, 0x92, 0x13, 0xce, 0x52, 0x47, 0xf3, 0x1b, 0x4e, 0x56, 0x47, 0xf3,
-	0x13, 0xac, 0xb0, 0x93, 0xf9, 0xb1, 0xfc, 0x71, 0x99, 0x45, 0x17, 0x97, 0x59, 0xf4, 0xfb, 0x32,
-	0x8b, 0xbe, 0x5d, 0x65, 0xbd, 0x8b, 0xab, 0xac, 0xf7, 0xeb, 0x2a, 0xeb, 0xbd, 0x4b, 0xf8, 0x4f,
-	0xea, 0xd1, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd8, 0x48, 0x39, 0x1c, 0xbf, 0x04, 0x00, 0x00,
+	// 838 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x4f, 0x6f, 0xdc, 0x44,
+	0x14, 0xcf, 0xae, 0x77, 0xbd, 0xf6, 0x73, 0x68, 0xd3, 0xa1, 0x6a, 0xa7, 0x45, 0x5d, 0x56, 0x11,
+	0x87, 0x54, 0x95, 0x52, 0x14, 0xf8, 0x02, 0x21, 0x14, 0xb4, 0x52, 0xd5, 0x0a, 0x03, 0x57, 0xac,
+	0xc1, 0x7e, 0x49, 0xac, 0x7a, 0x3d, 0xee, 0x78, 0x5c, 0x94, 0x33, 0x5f, 0x80, 0x33, 0x5f, 0x80,
+	0x13, 0x7c, 0x0e, 0x8e, 0x3d, 0x72, 0x84, 0xe4, 0x8b, 0xa0, 0xf7, 0x66, 0xbc, 0xd9, 0x4d, 0x55,
+	0xe1, 0x13, 0x97, 0x68, 0x7e, 0xbf, 0xf7, 0x27, 0x6f, 0x7e, 0xef, 0xe7, 0xd1, 0xc2, 0xfd, 0xc6,
+	0x68, 0xab, 0x9f, 0x6a, 0x53, 0xa0, 0x71, 0x7f, 0x0f, 0x99, 0x11, 0x53, 0x06, 0xfb, 0xbf, 0x4d,
+	0x60, 0xfa, 0x92, 0x4e, 0xe2, 0x16, 0x8c, 0xcb, 0x42, 0x8e, 0x16, 0xa3, 0x83, 0x38, 0x1d, 0x97,
+	0x85, 0x78, 0x00, 0x51, 0x6b, 0xb5, 0xc1, 0xac, 0x2c, 0xe4, 0x98, 0xd9, 0x19, 0xe3, 0x65, 0x21,
+	0x24, 0xcc, 0xf2, 0x73, 0x55, 0xd7, 0x58, 0xc9, 0xc0, 0x45, 0x3c, 0x14, 0x1f, 0x41, 0xac, 0x3a,
+	0x7b, 0x9e, 0xe5, 0xba, 0x40, 0x39, 0xe1, 0x58, 0x44, 0xc4, 0x89, 0x2e, 0x50, 0xdc, 0x85, 0xa9,
+	0x2d, 0x6d, 0x85, 0x72, 0xca, 0x01, 0x07, 0xa8, 0xc4, 0x6a, 0xab, 0xaa, 0xec, 0x14, 0x51, 0x86,
+	0x8b, 0xd1, 0x41, 0x90, 0x46, 0x4c, 0x7c, 0x85, 0x28, 0xf6, 0x20, 0x20, 0x7a, 0xc6, 0x34, 0x1d,
+	0xc5, 0x02, 0x76, 0x75, 0x67, 0x33, 0x6b, 0x54, 0x81, 0x59, 0xad, 0x65, 0xc4, 0xbd, 0x40, 0x77,
+	0xf6, 0x3b, 0xa2, 0x5e, 0x68, 0x1a, 0x7c, 0x1d, 0x8d, 0xdd, 0x78, 0xd6, 0x87, 0x3e, 0x86, 0x44,
+	0x37, 0x68, 0x94, 0xd5, 0x86, 0xae, 0x05, 0xbe, 0xd6, 0x53, 0xcb, 0x82, 0x12, 0x2c, 0x9a, 0x55,
+	0x59, 0xab, 0x8a, 0x12, 0x12, 0x97, 0xd0, 0x53, 0xcb, 0x42, 0xdc, 0x83, 0xb0, 0xb5, 0xca, 0x76,
+	0xad, 0xdc, 0xe5, 0x99, 0x3c, 0x12, 0xf7, 0x61, 0x56, 0x95, 0xf5, 0x2b, 0x2a, 0xfa, 0x80, 0x8b,
+	0x42, 0x82, 0xcb, 0x42, 0x3c, 0x02, 0x30, 0x78, 0xda, 0xd5, 0x05, 0xdf, 0xef, 0x16, 0x17, 0xc5,
+	0x8e, 0xa1, 0x0b, 0xde, 0x83, 0x50, 0x59, 0xab, 0xf2, 0x73, 0x79, 0xdb, 0x95, 0x39, 0x44, 0x65,
+	0xb9, 0x41, 0x65, 0xb1, 0xc8, 0x94, 0x95, 0x7b, 0x1c, 0x8b, 0x3d, 0x73, 0x6c, 0x29, 0xdc, 0x35,
+	0x45, 0x1f, 0xbe, 0xe3, 0xc2, 0x9e, 0x39, 0xb6, 0xa4, 0x34, 0xcd, 0x85, 0x52, 0x38, 0xa5, 0x19,
+	0x88, 0x05, 0x24, 0xa7, 0x65, 0x7d, 0x86, 0xa6, 0x31, 0x65, 0x6d, 0xe5, 0x87, 0x1c, 0xdb, 0xa4,
+	0xc4, 0x43, 0x88, 0x0c, 0xb6, 0x8d, 0xae, 0x5b, 0x94, 0x77, 0xdd, 0xf6, 0x7a, 0xbc, 0xff, 0xfb,
+	0x18, 0x80, 0x9d, 0xf2, 0xec, 0x0d, 0xd6, 0x76, 0xc3, 0x2e, 0x41, 0x6f, 0x17, 0x76, 0xd4, 0x86,
+	0x5d, 0x18, 0x2f, 0xb7, 0x9d, 0x14, 0x6c, 0x3b, 0xe9, 0xe6, 0x36, 0x27, 0xef, 0x6c, 0xf3, 0x11,
+	0xc0, 0xa9, 0xd1, 0xab, 0xcc, 0xdd, 0xc7, 0x39, 0x27, 0x26, 0xe6, 0x5b, 0xbe, 0x13, 0x2d, 0x5b,
+	0xfb, 0x60, 0xe8, 0x97, 0xad, 0x5d, 0x88, 0x56, 0xa5, 0x3b, 0x93, 0x3b, 0xfb, 0xc4, 0xa9, 0x47,
+	0x24, 0x8e, 0xca, 0xad, 0x36, 0xde, 0x3a, 0x0e, 0x50, 0xb6, 0xc1, 0x95, 0x32, 0xaf, 0xbc, 0x67,
+	0x3c, 0x22, 0xaf, 0x37, 0xea, 0xa2, 0xd2, 0xaa, 0xb7, 0x4b, 0x0f, 0x6f, 0xac, 0x28, 0xb9, 0xb1,
+	0xa2, 0xfd, 0x3f, 0xc6, 0x00, 0x27, 0xee, 0xb3, 0x78, 0xae, 0xcf, 0xfe, 0x37, 0xbd, 0x36, 0xbe,
+	0xcd, 0xe9, 0xf6, 0xb7, 0xf9, 0x00, 0x22, 0xd5, 0x94, 0x59, 0xad, 0x56, 0x6b, 0xa9, 0x54, 0x53,
+	0xbe, 0x50, 0x2b, 0xa4, 0x22, 0x83, 0xaf, 0x3b, 0x6c, 0xad, 0xd7, 0xaa, 0x87, 0x5b, 0x8e, 0x88,
+	0xb6, 0x1d, 0x41, 0x42, 0xa2, 0x31, 0xda, 0x78, 0xc5, 0x1c, 0xa0, 0x5e, 0x95, 0xb2, 0x58, 0xe7,
+	0x17, 0x2c, 0x58, 0x90, 0xf6, 0xf0, 0xbf, 0x04, 0xfb, 0x79, 0x04, 0xf1, 0xf3, 0xb2, 0xb5, 0xdf,
+	0x74, 0x68, 0x2e, 0xa8, 0x79, 0x55, 0xae, 0x4a, 0xeb, 0x25, 0x73, 0x40, 0x08, 0x98, 0x34, 0xea,
+	0x0c, 0x59, 0xb1, 0x20, 0xe5, 0x33, 0x71, 0xad, 0x36, 0xd6, 0x4b, 0xc5, 0x67, 0xaa, 0xfe, 0xe9,
+	0x1c, 0x4d, 0xff, 0x06, 0x39, 0x20, 0xf6, 0xc1, 0xbd, 0x7a, 0xac, 0x4c, 0x72, 0xb4, 0x7b, 0xc8,
+	0xe8, 0x90, 0x5d, 0x9d, 0xfa, 0x07, 0xf1, 0x07, 0x98, 0xa5, 0xfe, 0xee, 0x4f, 0x01, 0xaa, 0xb2,
+	0xb5, 0xd9, 0x6b, 0x1a, 0x88, 0xe7, 0x48, 0x8e, 0xf6, 0x7c, 0xcd, 0x7a, 0xd0, 0x34, 0xae, 0xd6,
+	0x33, 0xaf, 0xfb, 0x8f, 0xdf, 0xdf, 0xff, 0x9f, 0x11, 0x44, 0xe9, 0x86, 0x82, 0x6f, 0x54, 0xe5,
+	0x7d, 0x11, 0xa5, 0x0e, 0x10, 0xcb, 0x0f, 0xa0, 0xbf, 0xa5, 0x03, 0xd7, 0xcd, 0x83, 0xf7, 0x36,
+	0x17, 0x9f, 0x40, 0xc8, 0x87, 0x56, 0x4e, 0x16, 0xc1, 0x3b, 0x49, 0x3e, 0x26, 0x1e, 0x43, 0x88,
+	0xf4, 0x0d, 0xb7, 0x72, 0xca, 0x59, 0x77, 0x36, 0xb3, 0xf8, 0xeb, 0x4e, 0x7d, 0x82, 0xf8, 0x1c,
+	0x76, 0xbd, 0x7d, 0xb2, 0x4a, 0x9f, 0xb5, 0x32, 0xdc, 0x2a, 0xb8, 0xb6, 0x77, 0x9a, 0xe4, 0xeb,
+	0x73, 0x7b, 0xf4, 0x6b, 0x00, 0xe1, 0x4b, 0xf7, 0xbf, 0x9e, 0x40, 0x78, 0xbc, 0xd2, 0x1d, 0x3d,
+	0x18, 0xbe, 0xc8, 0xab, 0xfb, 0xf0, 0xf6, 0x1a, 0xfb, 0x07, 0x66, 0x47, 0x1c, 0x40, 0x40, 0x6f,
+	0xe2, 0x80, 0xcc, 0xc7, 0x30, 0xa1, 0x0d, 0x0c, 0x6c, 0xfa, 0x35, 0x0e, 0xca, 0x7c, 0x02, 0xe1,
+	0x09, 0xbb, 0x71, 0x60, 0xf2, 0xf7, 0x4d, 0x31, 0x3c, 0xf9, 0x4b, 0xac, 0x70, 0x70, 0xf2, 0x33,
+	0xa7, 0xfe, 0x80, 0xe4, 0x4f, 0x21, 0xb9, 0xde, 0xc2, 0x90, 0x8a, 0x2f, 0xe4, 0x9f, 0x97, 0xf3,
+	0xd1, 0xdb, 0xcb, 0xf9, 0xe8, 0xef, 0xcb, 0xf9, 0xe8, 0x97, 0xab, 0xf9, 0xce, 0xdb, 0xab, 0xf9,
+	0xce, 0x5f, 0x57, 0xf3, 0x9d, 0x1f, 0x43, 0xfe, 0x65, 0xf0, 0xd9, 0xbf, 0x03, 0x00, 0xb1, 0xa9,
+	0x7c, 0x30, 0x34, 0x08, 0x00, 0x00,
 }
 
 func (m *Order) Marshal() (dAtA []byte, err error) {
@@ -459,6 +766,33 @@ func (m *Order) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Response) > 0 {
+		i -= len(m.Response)
+		copy(dAtA[i:], m.Response)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Response)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
+	if len(m.Fingerprint) > 0 {
+		i -= len(m.Fingerprint)
+		copy(dAtA[i:], m.Fingerprint)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Fingerprint)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if len(m.State) > 0 {
+		i -= len(m.State)
+		copy(dAtA[i:], m.State)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.State)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
 	if len(m.UpdatedAt) > 0 {
 		i -= len(m.UpdatedAt)
 		copy(dAtA[i:], m.UpdatedAt)
@@ -577,7 +911,7 @@ func (m *Order) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *ListQuery) Marshal() (dAtA []byte, err error) {
+func (m *OrderEvent) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -587,56 +921,95 @@ func (m *ListQuery) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *ListQuery) MarshalTo(dAtA []byte) (int, error) {
+func (m *OrderEvent) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *ListQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *OrderEvent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Order != nil {
-		{
-			size, err := m.Order.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintOrder(dAtA, i, uint64(size))
-		}
+	if len(m.CreatedAt) > 0 {
+		i -= len(m.CreatedAt)
+		copy(dAtA[i:], m.CreatedAt)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.CreatedAt)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.Payload) > 0 {
+		i -= len(m.Payload)
+		copy(dAtA[i:], m.Payload)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Payload)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Remark) > 0 {
+		i -= len(m.Remark)
+		copy(dAtA[i:], m.Remark)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Remark)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Actor) > 0 {
+		i -= len(m.Actor)
+		copy(dAtA[i:], m.Actor)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Actor)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Source) > 0 {
+		i -= len(m.Source)
+		copy(dAtA[i:], m.Source)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Source)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.ToState) > 0 {
+		i -= len(m.ToState)
+		copy(dAtA[i:], m.ToState)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.ToState)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.FromState) > 0 {
+		i -= len(m.FromState)
+		copy(dAtA[i:], m.FromState)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.FromState)))
 		i--
 		dAtA[i] = 0x2a
 	}
-	if len(m.Where) > 0 {
-		i -= len(m.Where)
-		copy(dAtA[i:], m.Where)
-		i = encodeVarintOrder(dAtA, i, uint64(len(m.Where)))
+	if len(m.OutTradeNo) > 0 {
+		i -= len(m.OutTradeNo)
+		copy(dAtA[i:], m.OutTradeNo)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.OutTradeNo)))
 		i--
 		dAtA[i] = 0x22
 	}
-	if len(m.Sort) > 0 {
-		i -= len(m.Sort)
-		copy(dAtA[i:], m.Sort)
-		i = encodeVarintOrder(dAtA, i, uint64(len(m.Sort)))
+	if len(m.StoreId) > 0 {
+		i -= len(m.StoreId)
+		copy(dAtA[i:], m.StoreId)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.StoreId)))
 		i--
 		dAtA[i] = 0x1a
 	}
-	if m.Page != 0 {
-		i = encodeVarintOrder(dAtA, i, uint64(m.Page))
+	if len(m.OrderId) > 0 {
+		i -= len(m.OrderId)
+		copy(dAtA[i:], m.OrderId)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.OrderId)))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x12
 	}
-	if m.Limit != 0 {
-		i = encodeVarintOrder(dAtA, i, uint64(m.Limit))
+	if m.Id != 0 {
+		i = encodeVarintOrder(dAtA, i, uint64(m.Id))
 		i--
 		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Request) Marshal() (dAtA []byte, err error) {
+func (m *ChannelLog) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -646,7 +1019,162 @@ func (m *Request) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Request) MarshalTo(dAtA []byte) (int, error) {
+func (m *ChannelLog) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChannelLog) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.CreatedAt) > 0 {
+		i -= len(m.CreatedAt)
+		copy(dAtA[i:], m.CreatedAt)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.CreatedAt)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Latency != 0 {
+		i = encodeVarintOrder(dAtA, i, uint64(m.Latency))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Response) > 0 {
+		i -= len(m.Response)
+		copy(dAtA[i:], m.Response)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Response)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.Request) > 0 {
+		i -= len(m.Request)
+		copy(dAtA[i:], m.Request)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Request)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.ApiName) > 0 {
+		i -= len(m.ApiName)
+		copy(dAtA[i:], m.ApiName)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.ApiName)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Channel) > 0 {
+		i -= len(m.Channel)
+		copy(dAtA[i:], m.Channel)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Channel)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.OutTradeNo) > 0 {
+		i -= len(m.OutTradeNo)
+		copy(dAtA[i:], m.OutTradeNo)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.OutTradeNo)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.StoreId) > 0 {
+		i -= len(m.StoreId)
+		copy(dAtA[i:], m.StoreId)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.StoreId)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.OrderId) > 0 {
+		i -= len(m.OrderId)
+		copy(dAtA[i:], m.OrderId)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.OrderId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Id != 0 {
+		i = encodeVarintOrder(dAtA, i, uint64(m.Id))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Order != nil {
+		{
+			size, err := m.Order.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintOrder(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Where) > 0 {
+		i -= len(m.Where)
+		copy(dAtA[i:], m.Where)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Where)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Sort) > 0 {
+		i -= len(m.Sort)
+		copy(dAtA[i:], m.Sort)
+		i = encodeVarintOrder(dAtA, i, uint64(len(m.Sort)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Page != 0 {
+		i = encodeVarintOrder(dAtA, i, uint64(m.Page))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Limit != 0 {
+		i = encodeVarintOrder(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Request) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Request) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
@@ -703,6 +1231,34 @@ func (m *Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ChannelLogs) > 0 {
+		for iNdEx := len(m.ChannelLogs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.ChannelLogs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOrder(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Events) > 0 {
+		for iNdEx := len(m.Events) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Events[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOrder(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	if len(m.Orders) > 0 {
 		for iNdEx := len(m.Orders) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -828,6 +1384,121 @@ func (m *Order) Size() (n int) {
 	if l > 0 {
 		n += 2 + l + sovOrder(uint64(l))
 	}
+	l = len(m.State)
+	if l > 0 {
+		n += 2 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Fingerprint)
+	if l > 0 {
+		n += 2 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Response)
+	if l > 0 {
+		n += 2 + l + sovOrder(uint64(l))
+	}
+	return n
+}
+
+func (m *OrderEvent) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovOrder(uint64(m.Id))
+	}
+	l = len(m.OrderId)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.StoreId)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.OutTradeNo)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.FromState)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.ToState)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Source)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Actor)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Remark)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Payload)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.CreatedAt)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	return n
+}
+
+func (m *ChannelLog) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != 0 {
+		n += 1 + sovOrder(uint64(m.Id))
+	}
+	l = len(m.OrderId)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.StoreId)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.OutTradeNo)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Channel)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.ApiName)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Request)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Response)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
+	if m.Latency != 0 {
+		n += 1 + sovOrder(uint64(m.Latency))
+	}
+	l = len(m.CreatedAt)
+	if l > 0 {
+		n += 1 + l + sovOrder(uint64(l))
+	}
 	return n
 }
 
@@ -897,6 +1568,18 @@ func (m *Response) Size() (n int) {
 			n += 1 + l + sovOrder(uint64(l))
 		}
 	}
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovOrder(uint64(l))
+		}
+	}
+	if len(m.ChannelLogs) > 0 {
+		for _, e := range m.ChannelLogs {
+			l = e.Size()
+			n += 1 + l + sovOrder(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -937,7 +1620,795 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Channel = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthCode", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AuthCode = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Title = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TotalFee", wireType)
+			}
+			m.TotalFee = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TotalFee |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fee", wireType)
+			}
+			m.Fee = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fee |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutTradeNo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OutTradeNo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TradeNo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TradeNo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperatorId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OperatorId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TerminalId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TerminalId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
+			}
+			m.Status = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Status |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LinkId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.LinkId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RefundFee", wireType)
+			}
+			m.RefundFee = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RefundFee |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attach", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attach = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatedAt = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UpdatedAt = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.State = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fingerprint", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Fingerprint = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Response = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOrder(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OrderEvent) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOrder
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OrderEvent: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OrderEvent: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			m.Id = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Id |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OrderId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OutTradeNo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OutTradeNo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromState", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FromState = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ToState", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -965,11 +2436,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			m.ToState = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Source", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -997,11 +2468,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.StoreId = string(dAtA[iNdEx:postIndex])
+			m.Source = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Actor", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1029,11 +2500,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Channel = string(dAtA[iNdEx:postIndex])
+			m.Actor = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field AuthCode", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Remark", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1061,11 +2532,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.AuthCode = string(dAtA[iNdEx:postIndex])
+			m.Remark = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 10:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Title", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Payload", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1093,13 +2564,13 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Title = string(dAtA[iNdEx:postIndex])
+			m.Payload = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TotalFee", wireType)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
 			}
-			m.TotalFee = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOrder
@@ -1109,16 +2580,82 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.TotalFee |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreatedAt = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipOrder(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ChannelLog) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowOrder
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChannelLog: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChannelLog: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fee", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
-			m.Fee = 0
+			m.Id = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOrder
@@ -1128,14 +2665,14 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Fee |= int64(b&0x7F) << shift
+				m.Id |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 8:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OutTradeNo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OrderId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1163,11 +2700,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OutTradeNo = string(dAtA[iNdEx:postIndex])
+			m.OrderId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 9:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TradeNo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1195,11 +2732,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TradeNo = string(dAtA[iNdEx:postIndex])
+			m.StoreId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 10:
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field OperatorId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OutTradeNo", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1227,11 +2764,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.OperatorId = string(dAtA[iNdEx:postIndex])
+			m.OutTradeNo = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 11:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TerminalId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Channel", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1259,30 +2796,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.TerminalId = string(dAtA[iNdEx:postIndex])
+			m.Channel = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 12:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Status", wireType)
-			}
-			m.Status = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowOrder
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Status |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 13:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LinkId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ApiName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1310,13 +2828,13 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.LinkId = string(dAtA[iNdEx:postIndex])
+			m.ApiName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 14:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RefundFee", wireType)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Request", wireType)
 			}
-			m.RefundFee = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowOrder
@@ -1326,14 +2844,27 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RefundFee |= int64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 15:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Request = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Attach", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1361,11 +2892,11 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Attach = string(dAtA[iNdEx:postIndex])
+			m.Response = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 16:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1393,11 +2924,30 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.CreatedAt = string(dAtA[iNdEx:postIndex])
+			m.Error = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 17:
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Latency", wireType)
+			}
+			m.Latency = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Latency |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1425,7 +2975,7 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.UpdatedAt = string(dAtA[iNdEx:postIndex])
+			m.CreatedAt = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1905,6 +3455,74 @@ func (m *Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Events = append(m.Events, &OrderEvent{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChannelLogs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOrder
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOrder
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOrder
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChannelLogs = append(m.ChannelLogs, &ChannelLog{})
+			if err := m.ChannelLogs[len(m.ChannelLogs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOrder(dAtA[iNdEx:])
@@ -48,6 +48,10 @@ type OrdersService interface {
 	Update(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 	// 删除订单
 	Delete(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	// 获取订单状态变更记录
+	Events(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	// 获取订单通道请求记录
+	ChannelLogs(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 }
 
 type ordersService struct {
@@ -132,6 +136,26 @@ func (c *ordersService) Delete(ctx context.Context, in *Request, opts ...client.
 	return out, nil
 }
 
+func (c *ordersService) Events(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Orders.Events", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersService) ChannelLogs(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Orders.ChannelLogs", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Orders service
 
 type OrdersHandler interface {
@@ -149,6 +173,10 @@ type OrdersHandler interface {
 	Update(context.Context, *Request, *Response) error
 	// 删除订单
 	Delete(context.Context, *Request, *Response) error
+	// 获取订单状态变更记录
+	Events(context.Context, *Request, *Response) error
+	// 获取订单通道请求记录
+	ChannelLogs(context.Context, *Request, *Response) error
 }
 
 func RegisterOrdersHandler(s server.Server, hdlr OrdersHandler, opts ...server.HandlerOption) error {
@@ -160,6 +188,8 @@ func RegisterOrdersHandler(s server.Server, hdlr OrdersHandler, opts ...server.H
 		Create(ctx context.Context, in *Request, out *Response) error
 		Update(ctx context.Context, in *Request, out *Response) error
 		Delete(ctx context.Context, in *Request, out *Response) error
+		Events(ctx context.Context, in *Request, out *Response) error
+		ChannelLogs(ctx context.Context, in *Request, out *Response) error
 	}
 	type Orders struct {
 		orders
@@ -199,3 +229,11 @@ func (h *ordersHandler) Update(ctx context.Context, in *Request, out *Response)
 func (h *ordersHandler) Delete(ctx context.Context, in *Request, out *Response) error {
 	return h.OrdersHandler.Delete(ctx, in, out)
 }
+
+func (h *ordersHandler) Events(ctx context.Context, in *Request, out *Response) error {
+	return h.OrdersHandler.Events(ctx, in, out)
+}
+
+func (h *ordersHandler) ChannelLogs(ctx context.Context, in *Request, out *Response) error {
+	return h.OrdersHandler.ChannelLogs(ctx, in, out)
+}
@@ -34,6 +34,12 @@ type BizContent struct {
 	OperatorId  string `protobuf:"bytes,8,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
 	TerminalId  string `protobuf:"bytes,9,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
 	Attach      string `protobuf:"bytes,10,opt,name=attach,proto3" json:"attach,omitempty"`
+	Polling     bool   `protobuf:"varint,11,opt,name=polling,proto3" json:"polling,omitempty"`
+	OpenId      string `protobuf:"bytes,12,opt,name=open_id,json=openId,proto3" json:"open_id,omitempty"`
+	SubOpenId   string `protobuf:"bytes,13,opt,name=sub_open_id,json=subOpenId,proto3" json:"sub_open_id,omitempty"`
+	BuyerId     string `protobuf:"bytes,14,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	Scene       string `protobuf:"bytes,15,opt,name=scene,proto3" json:"scene,omitempty"`
+	ClientIp    string `protobuf:"bytes,16,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
 }
 
 func (m *BizContent) Reset()         { *m = BizContent{} }
@@ -139,10 +145,57 @@ func (m *BizContent) GetAttach() string {
 	return ""
 }
 
+func (m *BizContent) GetPolling() bool {
+	if m != nil {
+		return m.Polling
+	}
+	return false
+}
+
+func (m *BizContent) GetOpenId() string {
+	if m != nil {
+		return m.OpenId
+	}
+	return ""
+}
+
+func (m *BizContent) GetSubOpenId() string {
+	if m != nil {
+		return m.SubOpenId
+	}
+	return ""
+}
+
+func (m *BizContent) GetBuyerId() string {
+	if m != nil {
+		return m.BuyerId
+	}
+	return ""
+}
+
+func (m *BizContent) GetScene() string {
+	if m != nil {
+		return m.Scene
+	}
+	return ""
+}
+
+func (m *BizContent) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
 // 公共请求参数
 type Request struct {
 	StoreId    string      `protobuf:"bytes,1,opt,name=store_id,json=storeId,proto3" json:"store_id,omitempty"`
 	BizContent *BizContent `protobuf:"bytes,2,opt,name=biz_content,json=bizContent,proto3" json:"biz_content,omitempty"`
+	AppId      string      `protobuf:"bytes,3,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	SignType   string      `protobuf:"bytes,4,opt,name=sign_type,json=signType,proto3" json:"sign_type,omitempty"`
+	Timestamp  string      `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Nonce      string      `protobuf:"bytes,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Sign       string      `protobuf:"bytes,7,opt,name=sign,proto3" json:"sign,omitempty"`
 }
 
 func (m *Request) Reset()         { *m = Request{} }
@@ -192,6 +245,41 @@ func (m *Request) GetBizContent() *BizContent {
 	return nil
 }
 
+func (m *Request) GetAppId() string {
+	if m != nil {
+		return m.AppId
+	}
+	return ""
+}
+
+func (m *Request) GetSignType() string {
+	if m != nil {
+		return m.SignType
+	}
+	return ""
+}
+
+func (m *Request) GetTimestamp() string {
+	if m != nil {
+		return m.Timestamp
+	}
+	return ""
+}
+
+func (m *Request) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *Request) GetSign() string {
+	if m != nil {
+		return m.Sign
+	}
+	return ""
+}
+
 // 退款订单
 type Refund struct {
 	OutTradeNo string `protobuf:"bytes,1,opt,name=out_trade_no,json=outTradeNo,proto3" json:"out_trade_no,omitempty"`
@@ -271,6 +359,9 @@ type Content struct {
 	AlipayBuyerLogonId string    `protobuf:"bytes,14,opt,name=alipay_buyer_logon_id,json=alipayBuyerLogonId,proto3" json:"alipay_buyer_logon_id,omitempty"`
 	AlipayBuyerUserId  string    `protobuf:"bytes,15,opt,name=alipay_buyer_user_id,json=alipayBuyerUserId,proto3" json:"alipay_buyer_user_id,omitempty"`
 	RefundList         []*Refund `protobuf:"bytes,16,rep,name=refund_list,json=refundList,proto3" json:"refund_list,omitempty"`
+	CodeUrl            string    `protobuf:"bytes,17,opt,name=code_url,json=codeUrl,proto3" json:"code_url,omitempty"`
+	PayParams          string    `protobuf:"bytes,18,opt,name=pay_params,json=payParams,proto3" json:"pay_params,omitempty"`
+	PayUrl             string    `protobuf:"bytes,19,opt,name=pay_url,json=payUrl,proto3" json:"pay_url,omitempty"`
 }
 
 func (m *Content) Reset()         { *m = Content{} }
@@ -418,9 +509,31 @@ func (m *Content) GetRefundList() []*Refund {
 	return nil
 }
 
+func (m *Content) GetCodeUrl() string {
+	if m != nil {
+		return m.CodeUrl
+	}
+	return ""
+}
+
+func (m *Content) GetPayParams() string {
+	if m != nil {
+		return m.PayParams
+	}
+	return ""
+}
+
+func (m *Content) GetPayUrl() string {
+	if m != nil {
+		return m.PayUrl
+	}
+	return ""
+}
+
 // 公共响应参数
 type Response struct {
 	Content *Content `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Sign    string   `protobuf:"bytes,2,opt,name=sign,proto3" json:"sign,omitempty"`
 }
 
 func (m *Response) Reset()         { *m = Response{} }
@@ -463,6 +576,13 @@ func (m *Response) GetContent() *Content {
 	return nil
 }
 
+func (m *Response) GetSign() string {
+	if m != nil {
+		return m.Sign
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*BizContent)(nil), "trade.BizContent")
 	proto.RegisterType((*Request)(nil), "trade.Request")
@@ -474,48 +594,62 @@ func init() {
 func init() { proto.RegisterFile("proto/trade/trade.proto", fileDescriptor_aeea254ae72e2035) }
 
 var fileDescriptor_aeea254ae72e2035 = []byte{
-	// 651 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xc1, 0x6e, 0xd3, 0x4a,
-	0x14, 0x8d, 0x9b, 0xc6, 0x71, 0xae, 0xdb, 0xf4, 0x75, 0x5e, 0x5f, 0x9f, 0x0b, 0x22, 0x8d, 0x2c,
-	0x16, 0x11, 0x95, 0x5a, 0x08, 0xfc, 0x00, 0xad, 0xa8, 0x14, 0xa9, 0x14, 0x61, 0x40, 0x62, 0x67,
-	0x39, 0xf1, 0x6d, 0x63, 0xc9, 0x9d, 0x31, 0x33, 0x63, 0xa1, 0xf6, 0x2b, 0xf8, 0x19, 0xc4, 0x96,
-	0x25, 0xcb, 0x2e, 0x59, 0xa2, 0xf6, 0x47, 0xd0, 0xdc, 0x99, 0xa4, 0x2d, 0x45, 0xa8, 0x0b, 0x36,
-	0x51, 0xee, 0x39, 0x67, 0xee, 0xf5, 0x9c, 0x73, 0x6d, 0xf8, 0xbf, 0x92, 0x42, 0x8b, 0x1d, 0x2d,
-	0xb3, 0x1c, 0xed, 0xef, 0x36, 0x21, 0xac, 0x45, 0x45, 0xfc, 0x79, 0x01, 0x60, 0xb7, 0x38, 0xdb,
-	0x13, 0x5c, 0x23, 0xd7, 0x2c, 0x82, 0xf6, 0x64, 0x9a, 0x71, 0x8e, 0x65, 0xe4, 0xf5, 0xbd, 0x41,
-	0x27, 0x99, 0x95, 0xec, 0x3e, 0x74, 0xb2, 0x5a, 0x4f, 0xd3, 0x89, 0xc8, 0x31, 0x5a, 0x20, 0x2e,
-	0x30, 0xc0, 0x9e, 0xc8, 0x91, 0xad, 0x41, 0x4b, 0x17, 0xba, 0xc4, 0xa8, 0x49, 0x84, 0x2d, 0x58,
-	0x1f, 0x96, 0x44, 0xad, 0x53, 0x1a, 0x94, 0x72, 0x11, 0x2d, 0x12, 0x09, 0xa2, 0xd6, 0x6f, 0x0d,
-	0x74, 0x28, 0x58, 0x0c, 0xcb, 0x46, 0x21, 0xf1, 0xa8, 0xe6, 0xb9, 0x91, 0xb4, 0x48, 0x12, 0x8a,
-	0x5a, 0x27, 0x84, 0x1d, 0x0a, 0x33, 0x58, 0x0b, 0x9d, 0x95, 0xe9, 0x11, 0x62, 0xe4, 0xf7, 0xbd,
-	0x41, 0x33, 0x09, 0x08, 0xd8, 0x47, 0x64, 0x0f, 0x00, 0xdc, 0x61, 0xc3, 0xb6, 0x89, 0xed, 0x58,
-	0xc4, 0xd0, 0x9b, 0x10, 0x8a, 0x0a, 0x65, 0xa6, 0x85, 0x4c, 0x8b, 0x3c, 0x0a, 0xdc, 0x03, 0x38,
-	0x68, 0x94, 0x1b, 0x81, 0x46, 0x79, 0x52, 0xf0, 0xac, 0x34, 0x82, 0x8e, 0x15, 0xcc, 0xa0, 0x51,
-	0xce, 0xd6, 0xc1, 0xcf, 0xb4, 0xce, 0x26, 0xd3, 0x08, 0x88, 0x73, 0x55, 0xfc, 0x1e, 0xda, 0x09,
-	0x7e, 0xa8, 0x51, 0x69, 0xb6, 0x01, 0x81, 0xd2, 0x42, 0xa2, 0x69, 0xe0, 0x4c, 0xa3, 0x7a, 0x94,
-	0xb3, 0x21, 0x84, 0xe3, 0xe2, 0x2c, 0x9d, 0x58, 0x77, 0xc9, 0xb6, 0x70, 0xb8, 0xba, 0x6d, 0x73,
-	0xb8, 0xb2, 0x3d, 0x81, 0xf1, 0xfc, 0x7f, 0x9c, 0x82, 0x6f, 0xef, 0x7e, 0xcb, 0x3f, 0xef, 0x96,
-	0x7f, 0x37, 0xbc, 0x71, 0xa1, 0xcc, 0xbd, 0x59, 0x07, 0x5f, 0xe9, 0x4c, 0xd7, 0xca, 0xa5, 0xe2,
-	0xaa, 0xf8, 0xcb, 0x22, 0xb4, 0x67, 0x79, 0x6f, 0x42, 0x28, 0x51, 0xd7, 0x92, 0xdb, 0x5c, 0xdd,
-	0x04, 0x0b, 0x51, 0xb2, 0x64, 0x30, 0x09, 0x4e, 0xd4, 0xb1, 0x1b, 0xd1, 0xb1, 0xc8, 0x4b, 0x75,
-	0x7c, 0x7d, 0x5f, 0x9a, 0x37, 0xf7, 0xe5, 0xef, 0x84, 0xbf, 0x01, 0xc1, 0xbc, 0x83, 0x6f, 0x07,
-	0xe8, 0xdf, 0xdd, 0xbd, 0xfd, 0xc7, 0xbd, 0x08, 0x7e, 0xdd, 0x8b, 0x2b, 0x6b, 0x3a, 0xd7, 0xad,
-	0xa1, 0x71, 0xc5, 0x09, 0xa6, 0xc8, 0x73, 0x97, 0x77, 0xdb, 0xd4, 0x2f, 0x78, 0x4e, 0x37, 0x75,
-	0x31, 0x86, 0xee, 0xa6, 0xce, 0xc3, 0x87, 0xd0, 0xfd, 0x88, 0x93, 0x69, 0xa6, 0x53, 0x51, 0x21,
-	0x37, 0x5b, 0xb0, 0x44, 0x82, 0x25, 0x8b, 0xbe, 0xaa, 0x90, 0x8f, 0x72, 0xb6, 0x0d, 0xff, 0x3a,
-	0x55, 0xa1, 0x52, 0x55, 0x8f, 0xd5, 0x44, 0x16, 0x63, 0x8c, 0x96, 0x49, 0xba, 0x6a, 0xa9, 0x91,
-	0x7a, 0x33, 0x23, 0xd8, 0x13, 0xf8, 0x2f, 0x2b, 0x8b, 0x2a, 0x3b, 0x4d, 0xc7, 0xf5, 0x29, 0xca,
-	0xb4, 0x14, 0xc7, 0x82, 0x9a, 0x77, 0xe9, 0x04, 0xb3, 0xe4, 0xae, 0xe1, 0x0e, 0x0c, 0x35, 0xca,
-	0xd9, 0x0e, 0xac, 0xdd, 0x38, 0x52, 0x2b, 0xa4, 0xb5, 0x5f, 0xb1, 0x33, 0xae, 0x9d, 0x78, 0xa7,
-	0x50, 0xd2, 0x33, 0x85, 0xce, 0xa5, 0xb2, 0x50, 0x3a, 0xfa, 0xa7, 0xdf, 0x1c, 0x84, 0xc3, 0x65,
-	0xb7, 0x9e, 0x36, 0x83, 0xc4, 0xf9, 0x78, 0x50, 0x28, 0x1d, 0x3f, 0x83, 0x20, 0x41, 0x55, 0x09,
-	0xae, 0x90, 0x0d, 0xae, 0xfc, 0xf0, 0x68, 0xad, 0xbb, 0xee, 0xdc, 0x6c, 0xa7, 0x67, 0xf4, 0xf0,
-	0xab, 0x07, 0x3e, 0x65, 0xae, 0xd8, 0x16, 0xf8, 0xcf, 0x45, 0xb5, 0x3f, 0xdc, 0x67, 0xdd, 0xf9,
-	0x14, 0x7a, 0x89, 0xee, 0xad, 0xcc, 0x6b, 0xdb, 0x3f, 0x6e, 0xb0, 0x47, 0xd0, 0x7a, 0x5d, 0xa3,
-	0x3c, 0xbd, 0x8b, 0x76, 0x6b, 0xfe, 0xd2, 0xdc, 0x41, 0xfc, 0x18, 0x42, 0x2b, 0xbe, 0x6b, 0xfb,
-	0xdd, 0xe8, 0xdb, 0x45, 0xcf, 0x3b, 0xbf, 0xe8, 0x79, 0x3f, 0x2e, 0x7a, 0xde, 0xa7, 0xcb, 0x5e,
-	0xe3, 0xfc, 0xb2, 0xd7, 0xf8, 0x7e, 0xd9, 0x6b, 0x8c, 0x7d, 0xfa, 0x9a, 0x3e, 0xfd, 0x19, 0x00,
-	0x00, 0xff, 0xff, 0xb7, 0xea, 0x42, 0x53, 0x68, 0x05, 0x00, 0x00,
+	// 865 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x5d, 0x6f, 0xe3, 0x44,
+	0x14, 0xad, 0x9b, 0x4d, 0x62, 0x5f, 0xb7, 0xdd, 0xed, 0xec, 0x97, 0x17, 0xd8, 0x6c, 0x14, 0xf1,
+	0x10, 0xb1, 0x22, 0x0b, 0xe1, 0x17, 0xd0, 0x8a, 0x0a, 0xa3, 0x65, 0x29, 0x66, 0x2b, 0x1e, 0xad,
+	0xb1, 0x7d, 0x37, 0xb5, 0xe4, 0xcc, 0x0c, 0x33, 0x63, 0xa1, 0xec, 0x4f, 0xe0, 0x89, 0x9f, 0xc5,
+	0xe3, 0x3e, 0x20, 0xc4, 0x23, 0x6a, 0x7f, 0x05, 0x6f, 0x68, 0x3e, 0x9c, 0x34, 0x14, 0xa1, 0x20,
+	0xf1, 0x52, 0xe5, 0x9c, 0x73, 0xe7, 0xeb, 0xdc, 0x7b, 0x5c, 0x78, 0x2c, 0x24, 0xd7, 0xfc, 0x85,
+	0x96, 0xb4, 0x42, 0xf7, 0x77, 0x66, 0x19, 0xd2, 0xb7, 0x60, 0xf2, 0x5b, 0x0f, 0xe0, 0xa4, 0x7e,
+	0x7b, 0xca, 0x99, 0x46, 0xa6, 0x49, 0x02, 0xc3, 0xf2, 0x92, 0x32, 0x86, 0x4d, 0x12, 0x8c, 0x83,
+	0x69, 0x94, 0x75, 0x90, 0xbc, 0x0f, 0x11, 0x6d, 0xf5, 0x65, 0x5e, 0xf2, 0x0a, 0x93, 0x7d, 0xab,
+	0x85, 0x86, 0x38, 0xe5, 0x15, 0x92, 0x07, 0xd0, 0xd7, 0xb5, 0x6e, 0x30, 0xe9, 0x59, 0xc1, 0x01,
+	0x32, 0x86, 0x03, 0xde, 0xea, 0xdc, 0x1e, 0x94, 0x33, 0x9e, 0xdc, 0xb1, 0x22, 0xf0, 0x56, 0xbf,
+	0x36, 0xd4, 0x2b, 0x4e, 0x26, 0x70, 0x68, 0x2a, 0x24, 0xbe, 0x69, 0x59, 0x65, 0x4a, 0xfa, 0xb6,
+	0x24, 0xe6, 0xad, 0xce, 0x2c, 0xf7, 0x8a, 0x9b, 0x83, 0x35, 0xd7, 0xb4, 0xc9, 0xdf, 0x20, 0x26,
+	0x83, 0x71, 0x30, 0xed, 0x65, 0xa1, 0x25, 0xce, 0x10, 0xc9, 0x53, 0x00, 0xbf, 0xd8, 0xa8, 0x43,
+	0xab, 0x46, 0x8e, 0x31, 0xf2, 0x33, 0x88, 0xb9, 0x40, 0x49, 0x35, 0x97, 0x79, 0x5d, 0x25, 0xa1,
+	0xbf, 0x80, 0xa7, 0xd2, 0xca, 0x14, 0x68, 0x94, 0xcb, 0x9a, 0xd1, 0xc6, 0x14, 0x44, 0xae, 0xa0,
+	0xa3, 0xd2, 0x8a, 0x3c, 0x82, 0x01, 0xd5, 0x9a, 0x96, 0x97, 0x09, 0x58, 0xcd, 0x23, 0x63, 0x94,
+	0xe0, 0x4d, 0x53, 0xb3, 0x45, 0x12, 0x8f, 0x83, 0x69, 0x98, 0x75, 0x90, 0x3c, 0x86, 0x21, 0x17,
+	0xc8, 0xcc, 0x76, 0x07, 0x6e, 0x89, 0x81, 0x69, 0x45, 0x46, 0x10, 0xab, 0xb6, 0xc8, 0x3b, 0xf1,
+	0xd0, 0x8a, 0x91, 0x6a, 0x8b, 0x6f, 0x9c, 0xfe, 0x04, 0xc2, 0xa2, 0x5d, 0xa1, 0xbd, 0xe9, 0x91,
+	0x33, 0xdf, 0xe2, 0xb4, 0x32, 0xfe, 0xaa, 0x12, 0x19, 0x26, 0x77, 0x9d, 0xbf, 0x16, 0x18, 0x67,
+	0xca, 0xa6, 0x46, 0xa6, 0xf3, 0x5a, 0x24, 0xf7, 0x5c, 0x4b, 0x1c, 0x91, 0x8a, 0xc9, 0xaf, 0x01,
+	0x0c, 0x33, 0xfc, 0xa1, 0x45, 0xa5, 0xcd, 0xce, 0x4a, 0x73, 0x89, 0x66, 0x67, 0xdf, 0x56, 0x8b,
+	0xd3, 0x8a, 0xcc, 0x21, 0x2e, 0xea, 0xb7, 0x79, 0xe9, 0xfa, 0x6f, 0x1b, 0x1b, 0xcf, 0x8f, 0x67,
+	0x6e, 0x52, 0x36, 0x83, 0x91, 0x41, 0xb1, 0xfe, 0x4d, 0x1e, 0xc2, 0x80, 0x0a, 0x61, 0x36, 0xf3,
+	0xed, 0xa6, 0x42, 0xa4, 0x95, 0xb9, 0x8e, 0xaa, 0x17, 0x2c, 0xd7, 0x2b, 0x81, 0xbe, 0xd7, 0xa1,
+	0x21, 0x5e, 0xaf, 0x04, 0x92, 0x0f, 0x20, 0xd2, 0xf5, 0x12, 0x95, 0xa6, 0x4b, 0xe1, 0xbb, 0xbc,
+	0x21, 0xcc, 0xfb, 0x18, 0x67, 0xa5, 0xeb, 0x6f, 0x94, 0x39, 0x40, 0x08, 0xdc, 0x31, 0xeb, 0x6d,
+	0x5b, 0xa3, 0xcc, 0xfe, 0x9e, 0xe4, 0x30, 0x70, 0x93, 0x71, 0x6b, 0xba, 0x82, 0x5b, 0xd3, 0xb5,
+	0x35, 0x39, 0x7e, 0x64, 0xd7, 0x93, 0xf3, 0x08, 0x06, 0x4a, 0x53, 0xdd, 0x2a, 0xff, 0x08, 0x8f,
+	0x26, 0x3f, 0xf5, 0x61, 0xd8, 0x3d, 0xf4, 0x19, 0xc4, 0x12, 0x75, 0x2b, 0x99, 0x9b, 0x7a, 0x7f,
+	0x82, 0xa3, 0xec, 0xdc, 0x3f, 0x05, 0x8f, 0xf2, 0xa5, 0x5a, 0xf8, 0x23, 0x22, 0xc7, 0x7c, 0xad,
+	0x16, 0x37, 0xd3, 0xd4, 0xdb, 0x4e, 0xd3, 0xff, 0x13, 0x8d, 0x27, 0x10, 0xae, 0x77, 0x70, 0xce,
+	0x0d, 0xf5, 0x3f, 0xbd, 0x7d, 0xf8, 0xaf, 0xa9, 0x09, 0xff, 0x9e, 0x9a, 0x8d, 0x35, 0xd1, 0x4d,
+	0x6b, 0xec, 0x71, 0xf5, 0x12, 0x73, 0x64, 0x95, 0x4f, 0xc3, 0xd0, 0xe0, 0x2f, 0x58, 0x65, 0x5f,
+	0xea, 0x47, 0x28, 0xf6, 0x2f, 0x75, 0x90, 0x7c, 0x08, 0x47, 0x3f, 0x62, 0x79, 0x49, 0x75, 0xbe,
+	0x9d, 0x8a, 0x03, 0xc7, 0xfa, 0xd9, 0x9f, 0xc1, 0x7d, 0x5f, 0x55, 0xab, 0x5c, 0xb5, 0x85, 0x2a,
+	0x65, 0x5d, 0xa0, 0xcf, 0xc8, 0xb1, 0x93, 0x52, 0xf5, 0x5d, 0x27, 0x90, 0x4f, 0xe1, 0x21, 0x6d,
+	0x6a, 0x41, 0x57, 0xb9, 0x8b, 0x4c, 0xc3, 0x17, 0x9c, 0x6d, 0x82, 0x43, 0x9c, 0x78, 0x62, 0xb4,
+	0x97, 0x46, 0x4a, 0x2b, 0xf2, 0x02, 0x1e, 0x6c, 0x2d, 0x69, 0x95, 0x8b, 0x9a, 0x8b, 0xd4, 0xf1,
+	0x8d, 0x15, 0x17, 0xca, 0x86, 0x6e, 0x06, 0xb1, 0xf3, 0x24, 0x6f, 0x6a, 0xa5, 0x93, 0x7b, 0xe3,
+	0xde, 0x34, 0x9e, 0x1f, 0xfa, 0x68, 0xb8, 0x1e, 0x64, 0xde, 0xc7, 0x97, 0xb5, 0x4b, 0x99, 0x19,
+	0x93, 0xbc, 0x95, 0x4d, 0x72, 0xdc, 0x99, 0x50, 0xe1, 0x85, 0x6c, 0x8c, 0xe1, 0xe6, 0x60, 0x41,
+	0x25, 0x5d, 0xaa, 0x84, 0x58, 0x31, 0x12, 0x74, 0x75, 0x6e, 0x09, 0xf3, 0xc9, 0x30, 0xb2, 0x59,
+	0x78, 0xdf, 0x39, 0x2e, 0xe8, 0xea, 0x42, 0x36, 0x93, 0x2f, 0x21, 0xcc, 0x50, 0x09, 0xce, 0x14,
+	0x92, 0xe9, 0xc6, 0xe2, 0xc0, 0xa6, 0xf4, 0xc8, 0x5f, 0xa5, 0x8b, 0xe8, 0xda, 0xf2, 0x2e, 0x37,
+	0xfb, 0x9b, 0xdc, 0xcc, 0xff, 0xdc, 0x87, 0x81, 0x1d, 0x2d, 0x45, 0x9e, 0xc3, 0xe0, 0x73, 0x2e,
+	0xce, 0xe6, 0x67, 0xe4, 0x68, 0xfd, 0x18, 0xfb, 0x9d, 0x78, 0xef, 0xee, 0x1a, 0xbb, 0x33, 0x27,
+	0x7b, 0xe4, 0x23, 0xe8, 0x7f, 0xdb, 0xa2, 0x5c, 0xed, 0x52, 0xfb, 0x7c, 0x9d, 0xcd, 0x1d, 0x8a,
+	0x3f, 0x81, 0xd8, 0x15, 0xff, 0x97, 0xed, 0x4f, 0x29, 0x2b, 0xb1, 0xd9, 0xa5, 0x78, 0x06, 0xd1,
+	0xb9, 0xc4, 0x52, 0x22, 0xd5, 0xb8, 0x4b, 0xfd, 0xc7, 0x10, 0x7e, 0xa5, 0xa8, 0xa8, 0xcf, 0xe9,
+	0xae, 0x77, 0xf9, 0x1e, 0x8b, 0xdd, 0x8a, 0x4f, 0x92, 0x5f, 0xae, 0x46, 0xc1, 0xbb, 0xab, 0x51,
+	0xf0, 0xc7, 0xd5, 0x28, 0xf8, 0xf9, 0x7a, 0xb4, 0xf7, 0xee, 0x7a, 0xb4, 0xf7, 0xfb, 0xf5, 0x68,
+	0xaf, 0x18, 0xd8, 0xff, 0xc5, 0x9f, 0xfd, 0x35, 0x00, 0xf4, 0xe9, 0xaa, 0x54, 0xa6, 0x07, 0x00,
+	0x00,
 }
 
 func (m *BizContent) Marshal() (dAtA []byte, err error) {
@@ -538,6 +672,53 @@ func (m *BizContent) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ClientIp) > 0 {
+		i -= len(m.ClientIp)
+		copy(dAtA[i:], m.ClientIp)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.ClientIp)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if len(m.Scene) > 0 {
+		i -= len(m.Scene)
+		copy(dAtA[i:], m.Scene)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.Scene)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.BuyerId) > 0 {
+		i -= len(m.BuyerId)
+		copy(dAtA[i:], m.BuyerId)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.BuyerId)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if len(m.SubOpenId) > 0 {
+		i -= len(m.SubOpenId)
+		copy(dAtA[i:], m.SubOpenId)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.SubOpenId)))
+		i--
+		dAtA[i] = 0x6a
+	}
+	if len(m.OpenId) > 0 {
+		i -= len(m.OpenId)
+		copy(dAtA[i:], m.OpenId)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.OpenId)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.Polling {
+		i--
+		if m.Polling {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x58
+	}
 	if len(m.Attach) > 0 {
 		i -= len(m.Attach)
 		copy(dAtA[i:], m.Attach)
@@ -627,6 +808,41 @@ func (m *Request) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Sign) > 0 {
+		i -= len(m.Sign)
+		copy(dAtA[i:], m.Sign)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.Sign)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Nonce) > 0 {
+		i -= len(m.Nonce)
+		copy(dAtA[i:], m.Nonce)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.Nonce)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Timestamp) > 0 {
+		i -= len(m.Timestamp)
+		copy(dAtA[i:], m.Timestamp)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.Timestamp)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.SignType) > 0 {
+		i -= len(m.SignType)
+		copy(dAtA[i:], m.SignType)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.SignType)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.AppId) > 0 {
+		i -= len(m.AppId)
+		copy(dAtA[i:], m.AppId)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.AppId)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.BizContent != nil {
 		{
 			size, err := m.BizContent.MarshalToSizedBuffer(dAtA[:i])
@@ -713,6 +929,33 @@ func (m *Content) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PayUrl) > 0 {
+		i -= len(m.PayUrl)
+		copy(dAtA[i:], m.PayUrl)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.PayUrl)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x9a
+	}
+	if len(m.PayParams) > 0 {
+		i -= len(m.PayParams)
+		copy(dAtA[i:], m.PayParams)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.PayParams)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if len(m.CodeUrl) > 0 {
+		i -= len(m.CodeUrl)
+		copy(dAtA[i:], m.CodeUrl)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.CodeUrl)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
 	if len(m.RefundList) > 0 {
 		for iNdEx := len(m.RefundList) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -853,6 +1096,13 @@ func (m *Response) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Sign) > 0 {
+		i -= len(m.Sign)
+		copy(dAtA[i:], m.Sign)
+		i = encodeVarintTrade(dAtA, i, uint64(len(m.Sign)))
+		i--
+		dAtA[i] = 0x12
+	}
 	if m.Content != nil {
 		{
 			size, err := m.Content.MarshalToSizedBuffer(dAtA[:i])
@@ -923,6 +1173,29 @@ func (m *BizContent) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTrade(uint64(l))
 	}
+	if m.Polling {
+		n += 2
+	}
+	l = len(m.OpenId)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.SubOpenId)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.BuyerId)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.Scene)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.ClientIp)
+	if l > 0 {
+		n += 2 + l + sovTrade(uint64(l))
+	}
 	return n
 }
 
@@ -940,6 +1213,26 @@ func (m *Request) Size() (n int) {
 		l = m.BizContent.Size()
 		n += 1 + l + sovTrade(uint64(l))
 	}
+	l = len(m.AppId)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.SignType)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.Timestamp)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.Nonce)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
+	l = len(m.Sign)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
 	return n
 }
 
@@ -1034,6 +1327,18 @@ func (m *Content) Size() (n int) {
 			n += 2 + l + sovTrade(uint64(l))
 		}
 	}
+	l = len(m.CodeUrl)
+	if l > 0 {
+		n += 2 + l + sovTrade(uint64(l))
+	}
+	l = len(m.PayParams)
+	if l > 0 {
+		n += 2 + l + sovTrade(uint64(l))
+	}
+	l = len(m.PayUrl)
+	if l > 0 {
+		n += 2 + l + sovTrade(uint64(l))
+	}
 	return n
 }
 
@@ -1047,6 +1352,10 @@ func (m *Response) Size() (n int) {
 		l = m.Content.Size()
 		n += 1 + l + sovTrade(uint64(l))
 	}
+	l = len(m.Sign)
+	if l > 0 {
+		n += 1 + l + sovTrade(uint64(l))
+	}
 	return n
 }
 
@@ -1379,62 +1688,29 @@ func (m *BizContent) Unmarshal(dAtA []byte) error {
 			}
 			m.Attach = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTrade(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthTrade
-			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthTrade
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Request) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowTrade
-			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Polling", wireType)
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Request: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Request: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Polling = bool(v != 0)
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OpenId", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1462,13 +1738,13 @@ func (m *Request) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.StoreId = string(dAtA[iNdEx:postIndex])
+			m.OpenId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 13:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field BizContent", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field SubOpenId", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowTrade
@@ -1478,38 +1754,411 @@ func (m *Request) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthTrade
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthTrade
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.BizContent == nil {
-				m.BizContent = &BizContent{}
-			}
-			if err := m.BizContent.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.SubOpenId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipTrade(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthTrade
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BuyerId", wireType)
 			}
-			if (iNdEx + skippy) < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BuyerId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scene", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Scene = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientIp", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ClientIp = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTrade(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Request) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTrade
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Request: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Request: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StoreId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StoreId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BizContent", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.BizContent == nil {
+				m.BizContent = &BizContent{}
+			}
+			if err := m.BizContent.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SignType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Timestamp = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonce = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sign", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sign = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTrade(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if (iNdEx + skippy) < 0 {
 				return ErrInvalidLengthTrade
 			}
 			if (iNdEx + skippy) > l {
@@ -2190,6 +2839,102 @@ func (m *Content) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CodeUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CodeUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PayParams", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PayParams = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PayUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PayUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTrade(dAtA[iNdEx:])
@@ -2279,6 +3024,38 @@ func (m *Response) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sign", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTrade
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTrade
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTrade
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sign = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTrade(dAtA[iNdEx:])
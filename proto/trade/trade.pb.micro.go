@@ -39,6 +39,10 @@ type TradesService interface {
 	Query(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 	Refund(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 	RefundQuery(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	Cancel(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	Precreate(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	JsapiPay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
+	WebPay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error)
 }
 
 type tradesService struct {
@@ -93,6 +97,46 @@ func (c *tradesService) RefundQuery(ctx context.Context, in *Request, opts ...cl
 	return out, nil
 }
 
+func (c *tradesService) Cancel(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Trades.Cancel", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradesService) Precreate(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Trades.Precreate", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradesService) JsapiPay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Trades.JsapiPay", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tradesService) WebPay(ctx context.Context, in *Request, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Trades.WebPay", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Trades service
 
 type TradesHandler interface {
@@ -101,6 +145,10 @@ type TradesHandler interface {
 	Query(context.Context, *Request, *Response) error
 	Refund(context.Context, *Request, *Response) error
 	RefundQuery(context.Context, *Request, *Response) error
+	Cancel(context.Context, *Request, *Response) error
+	Precreate(context.Context, *Request, *Response) error
+	JsapiPay(context.Context, *Request, *Response) error
+	WebPay(context.Context, *Request, *Response) error
 }
 
 func RegisterTradesHandler(s server.Server, hdlr TradesHandler, opts ...server.HandlerOption) error {
@@ -109,6 +157,10 @@ func RegisterTradesHandler(s server.Server, hdlr TradesHandler, opts ...server.H
 		Query(ctx context.Context, in *Request, out *Response) error
 		Refund(ctx context.Context, in *Request, out *Response) error
 		RefundQuery(ctx context.Context, in *Request, out *Response) error
+		Cancel(ctx context.Context, in *Request, out *Response) error
+		Precreate(ctx context.Context, in *Request, out *Response) error
+		JsapiPay(ctx context.Context, in *Request, out *Response) error
+		WebPay(ctx context.Context, in *Request, out *Response) error
 	}
 	type Trades struct {
 		trades
@@ -136,3 +188,19 @@ func (h *tradesHandler) Refund(ctx context.Context, in *Request, out *Response)
 func (h *tradesHandler) RefundQuery(ctx context.Context, in *Request, out *Response) error {
 	return h.TradesHandler.RefundQuery(ctx, in, out)
 }
+
+func (h *tradesHandler) Cancel(ctx context.Context, in *Request, out *Response) error {
+	return h.TradesHandler.Cancel(ctx, in, out)
+}
+
+func (h *tradesHandler) Precreate(ctx context.Context, in *Request, out *Response) error {
+	return h.TradesHandler.Precreate(ctx, in, out)
+}
+
+func (h *tradesHandler) JsapiPay(ctx context.Context, in *Request, out *Response) error {
+	return h.TradesHandler.JsapiPay(ctx, in, out)
+}
+
+func (h *tradesHandler) WebPay(ctx context.Context, in *Request, out *Response) error {
+	return h.TradesHandler.WebPay(ctx, in, out)
+}
@@ -1,10 +1,19 @@
 package migrations
 
 import (
+	"github.com/micro/go-micro/v2/util/log"
+
 	db "github.com/lecex/pay/providers/database"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/secret"
 
+	appPB "github.com/lecex/pay/proto/app"
+	billPB "github.com/lecex/pay/proto/bill"
 	configPB "github.com/lecex/pay/proto/config"
 	orderPB "github.com/lecex/pay/proto/order"
+	repairPB "github.com/lecex/pay/proto/repair"
+	sharingPB "github.com/lecex/pay/proto/sharing"
+	webhookPB "github.com/lecex/pay/proto/webhook"
 )
 
 func init() {
@@ -13,6 +22,60 @@ func init() {
 	alipay()
 	wechat()
 	icbc()
+	webhook()
+	webhookLog()
+	billDiscrepancy()
+	billRun()
+	sharingRule()
+	sharingSplit()
+	orderEvent()
+	channelLog()
+	repair()
+	lease()
+	app()
+	nonce()
+	// 已有数据表新增字段
+	addColumn("configs", "notify_url", "varchar(255) DEFAULT NULL COMMENT '商户订单状态通知地址'")
+	addColumn("configs", "notify_secret", "text DEFAULT NULL COMMENT '商户订单状态通知签名秘钥'")
+	addColumn("configs", "return_url", "varchar(255) DEFAULT NULL COMMENT '网页支付完成后跳转地址'")
+	addColumn("wechats", "app_secret", "text DEFAULT NULL COMMENT '公众号秘钥'")
+	addColumn("configs", "profit_sharing", "int(11) DEFAULT 0 COMMENT '分账(禁用0、启用1)'")
+	if !db.DB.Dialect().HasColumn("orders", "state") {
+		addColumn("orders", "state", "varchar(32) DEFAULT NULL COMMENT '订单状态机状态'")
+		orderState()
+	}
+	addColumn("orders", "fingerprint", "varchar(64) DEFAULT NULL COMMENT '请求指纹'")
+	addColumn("orders", "response", "text DEFAULT NULL COMMENT '订单最终应答内容'")
+	addIndex("orders", "status_AND_updated_at", "status,updated_at")
+	addColumn("sharing_splits", "returned_amount", "int(16) DEFAULT 0 COMMENT '已回退金额'")
+	// 商户秘钥加密后长度超出原字段长度
+	modifyColumn("wechats", "api_key", "text", "text DEFAULT NULL COMMENT 'API秘钥'")
+	modifyColumn("wechats", "app_secret", "text", "text DEFAULT NULL COMMENT '公众号秘钥'")
+	modifyColumn("configs", "notify_secret", "text", "text DEFAULT NULL COMMENT '商户订单状态通知签名秘钥'")
+	encryptSecrets()
+}
+
+// addColumn 数据表字段不存在时新增字段
+func addColumn(table, column, definition string) {
+	if !db.DB.Dialect().HasColumn(table, column) {
+		db.DB.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition)
+	}
+}
+
+// modifyColumn 数据表字段类型不一致时修改字段
+func modifyColumn(table, column, dataType, definition string) {
+	var current string
+	row := db.DB.Raw("SELECT DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?", table, column).Row()
+	if err := row.Scan(&current); err == nil && current != dataType {
+		db.DB.Exec("ALTER TABLE " + table + " MODIFY COLUMN " + column + " " + definition)
+	}
+}
+
+// addIndex 数据表索引不存在时新增索引
+func addIndex(table, index, columns string) {
+	if !db.DB.Dialect().HasIndex(table, index) {
+		db.DB.Exec("ALTER TABLE " + table + " ADD INDEX " + index + " (" + columns + ")")
+	}
 }
 
 // config 用户数据迁移
@@ -28,6 +91,10 @@ func config() {
 			icbc_id int(11) DEFAULT 0 COMMENT '工行配置ID',
 			channel varchar(16) DEFAULT NULL COMMENT '默认支付通道',
 			status int(11) DEFAULT 1 COMMENT '商品状态(禁用0、启用1)',
+			notify_url varchar(255) DEFAULT NULL COMMENT '商户订单状态通知地址',
+			notify_secret text DEFAULT NULL COMMENT '商户订单状态通知签名秘钥',
+			return_url varchar(255) DEFAULT NULL COMMENT '网页支付完成后跳转地址',
+			profit_sharing int(11) DEFAULT 0 COMMENT '分账(禁用0、启用1)',
 			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 			PRIMARY KEY (id),
@@ -67,11 +134,12 @@ func wechat() {
 			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
 			app_id varchar(64) DEFAULT NULL COMMENT '应用ID',
 			mch_id varchar(64) DEFAULT NULL COMMENT '商家ID',
-			api_key varchar(64) DEFAULT NULL COMMENT 'API秘钥',
+			api_key text DEFAULT NULL COMMENT 'API秘钥',
 			sub_app_id varchar(64) DEFAULT NULL COMMENT '子应用ID',
 			sub_mch_id varchar(64) DEFAULT NULL COMMENT '子商家ID',
 			pem_cert text DEFAULT NULL COMMENT 'pem证书',
 			pem_key text DEFAULT NULL COMMENT 'pem秘钥',
+			app_secret text DEFAULT NULL COMMENT '公众号秘钥',
 			fee int(11) DEFAULT 0 COMMENT '手续费单位万分之一',
 			sandbox int(11) DEFAULT 0 COMMENT '沙盒模式(禁用0、启用1)',
 			PRIMARY KEY (id)
@@ -123,11 +191,326 @@ func order() {
 			refund_fee int(16) DEFAULT NULL COMMENT '订单退款总金额',
 			link_id varchar(36) DEFAULT NULL COMMENT '关联订单ID',
 			attach text DEFAULT NULL COMMENT '附加数据',
+			state varchar(32) DEFAULT NULL COMMENT '订单状态机状态',
+			fingerprint varchar(64) DEFAULT NULL COMMENT '请求指纹',
+			response text DEFAULT NULL COMMENT '订单最终应答内容',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			UNIQUE KEY store_id_AND_out_trade_no (store_id,out_trade_no),
+			KEY status_AND_updated_at (status,updated_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// orderState 根据原订单状态和退款金额初始化已有订单的状态机状态
+func orderState() {
+	db.DB.Exec(`UPDATE orders SET state = 'CLOSED' WHERE status = -1`)
+	db.DB.Exec(`UPDATE orders SET state = 'CREATED' WHERE status = 0`)
+	db.DB.Exec(`UPDATE orders SET state = 'SUCCESS' WHERE status = 1 AND (total_fee < 0 OR IFNULL(refund_fee, 0) = 0)`)
+	db.DB.Exec(`UPDATE orders SET state = 'PARTIALLY_REFUNDED' WHERE status = 1 AND total_fee > 0 AND refund_fee > 0 AND refund_fee < total_fee`)
+	db.DB.Exec(`UPDATE orders SET state = 'REFUNDED' WHERE status = 1 AND total_fee > 0 AND refund_fee >= total_fee`)
+}
+
+// orderEvent 订单状态变更记录数据迁移
+func orderEvent() {
+	orderEvent := &orderPB.OrderEvent{}
+	if !db.DB.HasTable(&orderEvent) {
+		db.DB.Exec(`
+			CREATE TABLE order_events (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			order_id varchar(36) DEFAULT NULL COMMENT '订单ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			out_trade_no varchar(36) DEFAULT NULL COMMENT '商家订单编号',
+			from_state varchar(32) DEFAULT NULL COMMENT '变更前状态',
+			to_state varchar(32) DEFAULT NULL COMMENT '变更后状态',
+			source varchar(16) DEFAULT NULL COMMENT '变更来源 [rpc 接口调用,notify 异步通知,poller 轮询]',
+			actor varchar(64) DEFAULT NULL COMMENT '触发方',
+			remark varchar(255) DEFAULT NULL COMMENT '变更说明',
+			payload text DEFAULT NULL COMMENT '通道原始返回内容',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY order_id (order_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// channelLog 通道请求记录数据迁移
+func channelLog() {
+	channelLog := &orderPB.ChannelLog{}
+	if !db.DB.HasTable(&channelLog) {
+		db.DB.Exec(`
+			CREATE TABLE channel_logs (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			order_id varchar(36) DEFAULT NULL COMMENT '订单ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			out_trade_no varchar(36) DEFAULT NULL COMMENT '商家订单编号',
+			channel varchar(36) DEFAULT NULL COMMENT '支付通道',
+			api_name varchar(64) DEFAULT NULL COMMENT '通道接口名称',
+			request text DEFAULT NULL COMMENT '请求内容 敏感字段已脱敏',
+			response text DEFAULT NULL COMMENT '通道返回内容',
+			error varchar(255) DEFAULT NULL COMMENT '错误信息',
+			latency int(11) DEFAULT 0 COMMENT '耗时 [毫秒]',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY order_id (order_id),
+			KEY store_id_AND_out_trade_no (store_id,out_trade_no)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// webhook 商户通知数据迁移
+func webhook() {
+	webhook := &webhookPB.Webhook{}
+	if !db.DB.HasTable(&webhook) {
+		db.DB.Exec(`
+			CREATE TABLE webhooks (
+			id varchar(36) NOT NULL COMMENT '通知ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			order_id varchar(36) DEFAULT NULL COMMENT '订单ID',
+			event varchar(32) DEFAULT NULL COMMENT '事件类型',
+			url varchar(255) DEFAULT NULL COMMENT '推送地址',
+			payload text DEFAULT NULL COMMENT '推送内容',
+			status int(11) DEFAULT 0 COMMENT '推送状态 [-1 推送失败,0 待推送,1 推送成功]',
+			attempts int(11) DEFAULT 0 COMMENT '已推送次数',
+			next_at datetime DEFAULT NULL COMMENT '下次推送时间',
 			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 			PRIMARY KEY (id),
-			UNIQUE KEY store_id_AND_out_trade_no (store_id,out_trade_no)
+			KEY status_AND_next_at (status,next_at),
+			KEY store_id (store_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// webhookLog 商户通知推送记录数据迁移
+func webhookLog() {
+	webhookLog := &webhookPB.WebhookLog{}
+	if !db.DB.HasTable(&webhookLog) {
+		db.DB.Exec(`
+			CREATE TABLE webhook_logs (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			webhook_id varchar(36) DEFAULT NULL COMMENT '通知ID',
+			url varchar(255) DEFAULT NULL COMMENT '推送地址',
+			status_code int(11) DEFAULT 0 COMMENT 'HTTP 状态码',
+			response text DEFAULT NULL COMMENT '商户响应内容',
+			error varchar(255) DEFAULT NULL COMMENT '错误信息',
+			latency int(11) DEFAULT 0 COMMENT '耗时 [毫秒]',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY webhook_id (webhook_id)
 			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 		`)
 	}
 }
+
+// billDiscrepancy 对账差异数据迁移
+func billDiscrepancy() {
+	billDiscrepancy := &billPB.BillDiscrepancy{}
+	if !db.DB.HasTable(&billDiscrepancy) {
+		db.DB.Exec(`
+			CREATE TABLE bill_discrepancies (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			channel varchar(36) DEFAULT NULL COMMENT '支付通道',
+			bill_date date DEFAULT NULL COMMENT '对账日期',
+			type varchar(32) DEFAULT NULL COMMENT '差异类型 [LOCAL_MISSING 系统无订单,CHANNEL_MISSING 通道无订单,AMOUNT_MISMATCH 金额不符,STATUS_MISMATCH 状态不符,REVOKED_MISMATCH 通道已撤销]',
+			out_trade_no varchar(36) DEFAULT NULL COMMENT '商家订单编号',
+			trade_no varchar(64) DEFAULT NULL COMMENT '渠道订单编号',
+			local_fee int(16) DEFAULT 0 COMMENT '系统订单金额',
+			channel_fee int(16) DEFAULT 0 COMMENT '通道对账金额',
+			local_status int(11) DEFAULT 0 COMMENT '系统订单状态',
+			channel_status varchar(32) DEFAULT NULL COMMENT '通道交易状态',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY store_id_AND_bill_date (store_id,bill_date)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// billRun 每日对账完成记录数据迁移
+func billRun() {
+	if !db.DB.HasTable("bill_runs") {
+		db.DB.Exec(`
+			CREATE TABLE bill_runs (
+			bill_date date NOT NULL COMMENT '对账日期',
+			finished_at datetime DEFAULT NULL COMMENT '对账完成时间',
+			PRIMARY KEY (bill_date)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// sharingRule 分账规则数据迁移
+func sharingRule() {
+	sharingRule := &sharingPB.SharingRule{}
+	if !db.DB.HasTable(&sharingRule) {
+		db.DB.Exec(`
+			CREATE TABLE sharing_rules (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			channel varchar(36) DEFAULT NULL COMMENT '支付通道',
+			type varchar(32) DEFAULT NULL COMMENT '接收方类型',
+			account varchar(64) DEFAULT NULL COMMENT '接收方账号',
+			name varchar(128) DEFAULT NULL COMMENT '接收方名称',
+			relation_type varchar(32) DEFAULT NULL COMMENT '与分账方的关系',
+			rate int(11) DEFAULT 0 COMMENT '分账比率 万分率',
+			description varchar(80) DEFAULT NULL COMMENT '分账描述',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY store_id (store_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// sharingSplit 分账明细数据迁移
+func sharingSplit() {
+	sharingSplit := &sharingPB.SharingSplit{}
+	if !db.DB.HasTable(&sharingSplit) {
+		db.DB.Exec(`
+			CREATE TABLE sharing_splits (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			order_id varchar(36) DEFAULT NULL COMMENT '订单ID',
+			out_trade_no varchar(36) DEFAULT NULL COMMENT '商家订单编号',
+			trade_no varchar(64) DEFAULT NULL COMMENT '渠道订单编号',
+			channel varchar(36) DEFAULT NULL COMMENT '支付通道',
+			out_order_no varchar(64) DEFAULT NULL COMMENT '分账单号',
+			rule_id int(11) DEFAULT 0 COMMENT '分账规则ID',
+			type varchar(32) DEFAULT NULL COMMENT '接收方类型',
+			account varchar(64) DEFAULT NULL COMMENT '接收方账号',
+			name varchar(128) DEFAULT NULL COMMENT '接收方名称',
+			relation_type varchar(32) DEFAULT NULL COMMENT '与分账方的关系',
+			amount int(16) DEFAULT 0 COMMENT '分账金额',
+			description varchar(80) DEFAULT NULL COMMENT '分账描述',
+			status int(11) DEFAULT 0 COMMENT '分账状态 [-1 分账失败,0 待分账,1 分账成功,2 已回退]',
+			out_return_no varchar(64) DEFAULT NULL COMMENT '回退单号',
+			returned_amount int(16) DEFAULT 0 COMMENT '已回退金额',
+			error varchar(255) DEFAULT NULL COMMENT '失败原因',
+			attempts int(11) DEFAULT 0 COMMENT '分账请求次数',
+			next_at datetime DEFAULT NULL COMMENT '下次分账时间',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY status_AND_next_at (status,next_at),
+			KEY order_id (order_id),
+			KEY store_id_AND_out_trade_no (store_id,out_trade_no)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// repair 订单补偿数据迁移
+func repair() {
+	repair := &repairPB.Repair{}
+	if !db.DB.HasTable(&repair) {
+		db.DB.Exec(`
+			CREATE TABLE repairs (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			store_id varchar(128) DEFAULT NULL COMMENT '商家ID',
+			order_id varchar(36) DEFAULT NULL COMMENT '订单ID',
+			out_trade_no varchar(36) DEFAULT NULL COMMENT '商家订单编号',
+			channel varchar(36) DEFAULT NULL COMMENT '支付通道',
+			to_state varchar(32) DEFAULT NULL COMMENT '通道处理结果对应的订单状态',
+			trade_no varchar(64) DEFAULT NULL COMMENT '渠道订单编号',
+			payload text DEFAULT NULL COMMENT '通道返回内容',
+			status int(11) DEFAULT 0 COMMENT '补偿状态 [-1 补偿失败,0 待补偿,1 已修复]',
+			error varchar(255) DEFAULT NULL COMMENT '失败原因',
+			attempts int(11) DEFAULT 0 COMMENT '补偿次数',
+			next_at datetime DEFAULT NULL COMMENT '下次补偿时间',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			KEY status_AND_next_at (status,next_at),
+			KEY order_id (order_id),
+			KEY store_id_AND_out_trade_no (store_id,out_trade_no)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// lease 后台任务租约数据迁移
+func lease() {
+	if !db.DB.HasTable("leases") {
+		db.DB.Exec(`
+			CREATE TABLE leases (
+			name varchar(64) NOT NULL COMMENT '后台任务名称',
+			owner varchar(64) DEFAULT NULL COMMENT '持有租约的实例',
+			expired_at datetime DEFAULT NULL COMMENT '租约到期时间',
+			PRIMARY KEY (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// app 开发者应用数据迁移
+func app() {
+	app := &appPB.App{}
+	if !db.DB.HasTable(&app) {
+		db.DB.Exec(`
+			CREATE TABLE apps (
+			id int(11) unsigned NOT NULL AUTO_INCREMENT COMMENT 'ID',
+			app_id varchar(64) NOT NULL COMMENT '应用APPID',
+			name varchar(64) DEFAULT NULL COMMENT '应用名称',
+			sign_type varchar(16) DEFAULT NULL COMMENT '签名方式 [RSA2,HMAC-SHA256]',
+			public_key text DEFAULT NULL COMMENT '应用公钥',
+			secret text DEFAULT NULL COMMENT '应用秘钥',
+			store_ids text DEFAULT NULL COMMENT '允许调用的商户ID',
+			ip_allowlist varchar(1024) DEFAULT NULL COMMENT '允许调用的IP',
+			status int(11) DEFAULT 1 COMMENT '状态(禁用0、启用1)',
+			created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			PRIMARY KEY (id),
+			UNIQUE KEY app_id (app_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// nonce 开放接口请求随机字符串数据迁移
+func nonce() {
+	if !db.DB.HasTable("nonces") {
+		db.DB.Exec(`
+			CREATE TABLE nonces (
+			app_id varchar(64) NOT NULL COMMENT '应用APPID',
+			nonce varchar(64) NOT NULL COMMENT '请求随机字符串',
+			created_at datetime DEFAULT NULL COMMENT '请求时间',
+			PRIMARY KEY (app_id,nonce),
+			KEY created_at (created_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+		`)
+	}
+}
+
+// encryptSecrets 配置主密钥后加密历史明文商户秘钥
+func encryptSecrets() {
+	if k, err := secret.Default(); err != nil || k == nil {
+		if err != nil {
+			log.Log(err)
+		}
+		return
+	}
+	count, err := (&repository.ConfigRepository{db.DB}).EncryptSecrets(false)
+	if err != nil {
+		log.Log(err)
+		return
+	}
+	if count > 0 {
+		log.Logf("加密历史商户秘钥 %d 条", count)
+	}
+	count, err = (&repository.AppRepository{db.DB}).EncryptSecrets(false)
+	if err != nil {
+		log.Log(err)
+		return
+	}
+	if count > 0 {
+		log.Logf("加密历史应用秘钥 %d 条", count)
+	}
+}
@@ -0,0 +1,81 @@
+package bill
+
+import (
+	pb "github.com/lecex/pay/proto/bill"
+	orderPB "github.com/lecex/pay/proto/order"
+)
+
+// 对账差异类型
+const (
+	LocalMissing    = "LOCAL_MISSING"    // 通道有交易 系统无订单
+	ChannelMissing  = "CHANNEL_MISSING"  // 系统订单成功 通道无交易
+	AmountMismatch  = "AMOUNT_MISMATCH"  // 金额不符
+	StatusMismatch  = "STATUS_MISMATCH"  // 通道交易成功 系统订单未成功
+	RevokedMismatch = "REVOKED_MISMATCH" // 通道交易已撤销 系统订单成功
+)
+
+// Record 通道对账单交易明细
+type Record struct {
+	OutTradeNo string // 商户订单号 退款为商户退款单号
+	TradeNo    string // 通道交易号
+	TotalFee   int64  // 交易金额 [单位分] 退款为负数
+	Status     string // 交易状态 SUCCESS 成功 其它为通道原始状态
+	Refund     bool   // 是否退款
+	Time       string // 交易时间
+}
+
+// Reconcile 逐笔核对通道对账单和系统订单
+// orders 应包含对账日期前后的订单 用于匹配跨日交易, dayOrders 为对账日期内创建的订单 用于发现通道缺失的交易
+func Reconcile(records []*Record, orders []*orderPB.Order, dayOrders []*orderPB.Order) (discrepancies []*pb.BillDiscrepancy) {
+	byOutTradeNo := map[string]*orderPB.Order{}
+	byTradeNo := map[string]*orderPB.Order{}
+	for _, o := range orders {
+		byOutTradeNo[o.OutTradeNo] = o
+		if o.TradeNo != "" && o.TotalFee > 0 { // 退款订单可能与原订单交易号相同
+			byTradeNo[o.TradeNo] = o
+		}
+	}
+	matched := map[string]bool{}
+	for _, r := range records {
+		o, ok := byOutTradeNo[r.OutTradeNo]
+		if !ok && !r.Refund {
+			o, ok = byTradeNo[r.TradeNo]
+		}
+		d := &pb.BillDiscrepancy{
+			OutTradeNo:    r.OutTradeNo,
+			TradeNo:       r.TradeNo,
+			ChannelFee:    r.TotalFee,
+			ChannelStatus: r.Status,
+		}
+		if !ok {
+			d.Type = LocalMissing
+			discrepancies = append(discrepancies, d)
+			continue
+		}
+		matched[o.Id] = true
+		d.LocalFee, d.LocalStatus = o.TotalFee, o.Status
+		switch {
+		case r.Status == "SUCCESS" && o.Status != 1:
+			d.Type = StatusMismatch
+		case r.Status == "REVOKED" && o.Status == 1:
+			d.Type = RevokedMismatch
+		case r.TotalFee != o.TotalFee:
+			d.Type = AmountMismatch
+		default:
+			continue
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	for _, o := range dayOrders {
+		if o.Status == 1 && !matched[o.Id] {
+			discrepancies = append(discrepancies, &pb.BillDiscrepancy{
+				Type:        ChannelMissing,
+				OutTradeNo:  o.OutTradeNo,
+				TradeNo:     o.TradeNo,
+				LocalFee:    o.TotalFee,
+				LocalStatus: o.Status,
+			})
+		}
+	}
+	return discrepancies
+}
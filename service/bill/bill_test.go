@@ -0,0 +1,122 @@
+package bill
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	orderPB "github.com/lecex/pay/proto/order"
+)
+
+func fixture(t *testing.T, name string) []byte {
+	data, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func checkRecords(t *testing.T, records []*Record, want []Record) {
+	if len(records) != len(want) {
+		t.Fatalf("want %d records got %d", len(want), len(records))
+	}
+	for i, r := range records {
+		if *r != want[i] {
+			t.Errorf("record %d: want %+v got %+v", i, want[i], *r)
+		}
+	}
+}
+
+func TestParseAlipayZip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	z := zip.NewWriter(buf)
+	for _, name := range []string{"alipay.csv", "alipay_summary.csv"} {
+		w, _ := z.Create(name)
+		w.Write(fixture(t, name))
+	}
+	z.Close()
+	records, err := ParseAlipayZip(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRecords(t, records, []Record{
+		{OutTradeNo: "GZ0001", TradeNo: "2020010122001400001000000001", TotalFee: 180, Status: "SUCCESS", Time: "2020-01-01 10:00:05"},
+		{OutTradeNo: "GZ0002", TradeNo: "2020010122001400001000000002", TotalFee: 1000, Status: "SUCCESS", Time: "2020-01-01 11:00:03"},
+		{OutTradeNo: "GZ0001_R", TradeNo: "2020010122001400001000000001", TotalFee: -80, Status: "SUCCESS", Refund: true, Time: "2020-01-01 12:00:00"},
+	})
+}
+
+func TestParseWechat(t *testing.T) {
+	data := fixture(t, "wechat.csv")
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	gz.Write(data)
+	gz.Close()
+	want := []Record{
+		{OutTradeNo: "WX0001", TradeNo: "4200000001202001010000000001", TotalFee: 180, Status: "SUCCESS", Time: "2020-01-01 10:00:05"},
+		{OutTradeNo: "WX0002", TradeNo: "4200000001202001010000000002", TotalFee: 1000, Status: "SUCCESS", Time: "2020-01-01 11:00:03"},
+		{OutTradeNo: "WX0001_R", TradeNo: "50000000012020010100000000001", TotalFee: -80, Status: "SUCCESS", Refund: true, Time: "2020-01-01 12:00:00"},
+	}
+	for _, d := range [][]byte{data, buf.Bytes()} {
+		records, err := ParseWechat(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRecords(t, records, want)
+	}
+}
+
+func TestParseIcbc(t *testing.T) {
+	f, err := os.Open("testdata/icbc.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := ParseIcbc(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRecords(t, records, []Record{
+		{OutTradeNo: "IC0001", TradeNo: "020002040095202001010000001", TotalFee: 180, Status: "SUCCESS", Time: "2020-01-01 10:00:05"},
+		{OutTradeNo: "IC0001_R", TradeNo: "020002040095202001010000001", TotalFee: -80, Status: "SUCCESS", Refund: true, Time: "2020-01-01 12:00:00"},
+		{OutTradeNo: "IC0002", TradeNo: "020002040095202001010000002", TotalFee: 1000, Status: "1", Time: "2020-01-01 11:00:03"},
+	})
+}
+
+func TestReconcile(t *testing.T) {
+	records, err := ParseWechat(fixture(t, "wechat.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records = append(records,
+		&Record{OutTradeNo: "WX0009", TradeNo: "4200000001202001010000000009", TotalFee: 100, Status: "SUCCESS"},
+		&Record{OutTradeNo: "WX0005", TradeNo: "4200000001202001010000000005", TotalFee: 100, Status: "REVOKED"},
+	)
+	orders := []*orderPB.Order{
+		{Id: "1", OutTradeNo: "WX0001", TradeNo: "4200000001202001010000000001", TotalFee: 180, Status: 1},
+		{Id: "2", OutTradeNo: "WX0002", TradeNo: "4200000001202001010000000002", TotalFee: 1200, Status: 1}, // 金额不符
+		{Id: "3", OutTradeNo: "WX0001_R", TotalFee: -80, Status: 0, LinkId: "1"},                            // 状态不符
+		{Id: "4", OutTradeNo: "WX0003", TradeNo: "4200000001202001010000000003", TotalFee: 100, Status: 1},  // 通道无订单
+		{Id: "5", OutTradeNo: "WX0004", TotalFee: 100, Status: -1},                                          // 已关闭订单无需对账
+		{Id: "6", OutTradeNo: "WX0005", TradeNo: "4200000001202001010000000005", TotalFee: 100, Status: 1},  // 通道已撤销
+	}
+	discrepancies := Reconcile(records, orders, orders)
+	want := map[string]string{
+		"WX0002":   AmountMismatch,
+		"WX0001_R": StatusMismatch,
+		"WX0009":   LocalMissing,
+		"WX0003":   ChannelMissing,
+		"WX0005":   RevokedMismatch,
+	}
+	if len(discrepancies) != len(want) {
+		t.Fatalf("want %d discrepancies got %+v", len(want), discrepancies)
+	}
+	for _, d := range discrepancies {
+		if want[d.OutTradeNo] != d.Type {
+			t.Errorf("%s: want %s got %s", d.OutTradeNo, want[d.OutTradeNo], d.Type)
+		}
+	}
+}
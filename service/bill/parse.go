@@ -0,0 +1,205 @@
+package bill
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// readCsv 读取对账文件 各通道对账单每行字段数不一致且含有注释行
+func readCsv(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("对账文件格式错误:" + err.Error())
+	}
+	for _, row := range rows {
+		for i, v := range row {
+			row[i] = strings.Trim(strings.TrimSpace(v), "`")
+		}
+	}
+	return rows, nil
+}
+
+// fen 金额元转为分
+func fen(yuan string) (int64, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(yuan))
+	if err != nil {
+		return 0, errors.New("对账金额格式错误:" + yuan)
+	}
+	return d.Mul(decimal.NewFromFloat(float64(100))).IntPart(), nil
+}
+
+// abs 绝对值
+func abs(i int64) int64 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// isDigits 判断交易号 用于跳过标题和汇总行
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseAlipayZip 解析支付宝对账单压缩包 压缩包内包含业务明细和汇总两个 GBK 编码的 csv 文件
+// 文件名编码不固定 按内容解析所有文件 汇总文件没有交易明细
+func ParseAlipayZip(data []byte) (records []*Record, err error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("支付宝对账单压缩包格式错误:" + err.Error())
+	}
+	for _, f := range z.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		r, err := ParseAlipay(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r...)
+	}
+	return records, nil
+}
+
+// ParseAlipay 解析支付宝业务明细
+// 字段: 0 支付宝交易号,1 商户订单号,2 业务类型,3 商品名称,4 创建时间,5 完成时间,... 11 订单金额(元),... 21 退款批次号/请求号
+// 文件为 GBK 编码 只按字段位置解析 不依赖中文内容 退款行带有退款请求号
+func ParseAlipay(r io.Reader) (records []*Record, err error) {
+	rows, err := readCsv(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row) < 22 || !isDigits(row[0]) {
+			continue
+		}
+		totalFee, err := fen(row[11])
+		if err != nil {
+			return nil, err
+		}
+		record := &Record{
+			OutTradeNo: row[1],
+			TradeNo:    row[0],
+			TotalFee:   abs(totalFee),
+			Status:     "SUCCESS",
+			Time:       row[5],
+		}
+		if row[21] != "" {
+			record.OutTradeNo = row[21]
+			record.TotalFee = -record.TotalFee
+			record.Refund = true
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ParseWechat 解析微信对账单 支持 GZIP 压缩
+// 字段: 0 交易时间,... 5 微信订单号,6 商户订单号,... 9 交易状态,... 12 应结订单金额,... 14 微信退款单号,15 商户退款单号,16 退款金额,... 19 退款状态,... 24 订单金额,25 申请退款金额
+// 每个字段以 ` 开头 明细后为汇总数据
+func ParseWechat(data []byte) (records []*Record, err error) {
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.New("微信对账单压缩格式错误:" + err.Error())
+		}
+		defer gz.Close()
+		if data, err = ioutil.ReadAll(gz); err != nil {
+			return nil, errors.New("微信对账单压缩格式错误:" + err.Error())
+		}
+	}
+	rows, err := readCsv(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row) < 20 || !isDigits(row[5]) {
+			continue
+		}
+		record := &Record{
+			OutTradeNo: row[6],
+			TradeNo:    row[5],
+			Status:     row[9],
+			Time:       row[0],
+		}
+		amount := row[12]
+		if len(row) > 24 && row[24] != "" { // 新版对账单包含订单金额
+			amount = row[24]
+		}
+		if row[9] == "REFUND" {
+			record.OutTradeNo = row[15]
+			record.TradeNo = row[14]
+			record.Status = row[19]
+			record.Refund = true
+			amount = row[16]
+			if len(row) > 25 && row[25] != "" {
+				amount = row[25]
+			}
+		}
+		totalFee, err := fen(amount)
+		if err != nil {
+			return nil, err
+		}
+		record.TotalFee = abs(totalFee)
+		if record.Refund {
+			record.TotalFee = -record.TotalFee
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ParseIcbc 解析工行对账单
+// 字段: 0 商户订单号,1 工行订单号,2 退款编号,3 交易金额(分),4 交易状态(0 成功),5 交易时间
+// 退款行带有退款编号
+func ParseIcbc(r io.Reader) (records []*Record, err error) {
+	rows, err := readCsv(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if len(row) < 6 || !isDigits(row[3]) {
+			continue
+		}
+		totalFee, _ := strconv.ParseInt(row[3], 10, 64)
+		record := &Record{
+			OutTradeNo: row[0],
+			TradeNo:    row[1],
+			TotalFee:   totalFee,
+			Status:     row[4],
+			Time:       row[5],
+		}
+		if row[4] == "0" {
+			record.Status = "SUCCESS"
+		}
+		if row[2] != "" {
+			record.OutTradeNo = row[2]
+			record.TotalFee = -totalFee
+			record.Refund = true
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,145 @@
+package cashier
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lecex/core/env"
+	"github.com/shopspring/decimal"
+
+	configPB "github.com/lecex/pay/proto/config"
+)
+
+// 扫码客户端
+const (
+	Wechat   = "wechat"   // 微信
+	Alipay   = "alipay"   // 支付宝
+	UnionPay = "unionpay" // 云闪付 暂无网页支付通道
+)
+
+// OpenIdTTL 收银台 openid 令牌有效期 过期后需要重新扫码授权
+const OpenIdTTL = 30 * time.Minute
+
+// amountRegexp 付款金额 最多两位小数
+var amountRegexp = regexp.MustCompile(`^\d{1,8}(\.\d{1,2})?$`)
+
+// Client 根据 User-Agent 识别扫码客户端 无法识别时返回空
+func Client(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "MicroMessenger"):
+		return Wechat
+	case strings.Contains(userAgent, "AlipayClient"):
+		return Alipay
+	case strings.Contains(userAgent, "UnionPay"):
+		return UnionPay
+	}
+	return ""
+}
+
+// Channel 扫码客户端对应的支付通道 商户未配置对应通道时返回错误
+func Channel(client string, con *configPB.Config) (string, error) {
+	switch client {
+	case Wechat:
+		if con.Wechat == nil || con.Wechat.MchId == "" {
+			return "", errors.New("商户未开通微信支付")
+		}
+		return Wechat, nil
+	case Alipay:
+		if con.Alipay == nil || con.Alipay.AppId == "" {
+			return "", errors.New("商户未开通支付宝支付")
+		}
+		return Alipay, nil
+	case UnionPay:
+		return "", errors.New("暂不支持云闪付扫码付款 请使用微信或支付宝扫码")
+	}
+	return "", errors.New("请使用微信或支付宝扫码付款")
+}
+
+// WechatApp 网页授权使用的公众号 配置子商户应用ID时使用子商户公众号 sub 为 true 时 openid 为子商户用户标识
+func WechatApp(con *configPB.Config) (appId string, sub bool, err error) {
+	if con.Wechat == nil || con.Wechat.AppSecret == "" {
+		return "", false, errors.New("商户未配置公众号秘钥")
+	}
+	if con.Wechat.SubAppId != "" {
+		return con.Wechat.SubAppId, true, nil
+	}
+	return con.Wechat.AppId, false, nil
+}
+
+// OpenIdToken 网页授权后签发 openid 令牌 格式 openid.过期时间戳.签名 签名为 hex(HMAC-SHA256(公众号秘钥, 商户ID.openid.过期时间戳))
+// 下单时只接受令牌中的 openid 用户无法提交其它用户或其它商户的 openid
+func OpenIdToken(secret string, storeId string, openId string, expiresAt time.Time) string {
+	content := openId + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return content + "." + openIdSign(secret, storeId, content)
+}
+
+// OpenId 校验 openid 令牌并返回 openid 签名错误或令牌过期时返回错误
+func OpenId(secret string, storeId string, token string, now time.Time) (string, error) {
+	err := errors.New("微信授权已失效 请重新扫码")
+	i := strings.LastIndex(token, ".")
+	if secret == "" || i < 0 {
+		return "", err
+	}
+	content, sign := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sign), []byte(openIdSign(secret, storeId, content))) {
+		return "", err
+	}
+	j := strings.LastIndex(content, ".")
+	if j <= 0 {
+		return "", err
+	}
+	expiresAt, e := strconv.ParseInt(content[j+1:], 10, 64)
+	if e != nil || now.Unix() > expiresAt {
+		return "", err
+	}
+	return content[:j], nil
+}
+
+func openIdSign(secret string, storeId string, content string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(storeId + "." + content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ParseAmount 付款金额元转为分
+func ParseAmount(amount string) (int64, error) {
+	amount = strings.TrimSpace(amount)
+	if !amountRegexp.MatchString(amount) {
+		return 0, fmt.Errorf("付款金额格式错误:%s", amount)
+	}
+	d, err := decimal.NewFromString(amount)
+	if err != nil {
+		return 0, fmt.Errorf("付款金额格式错误:%s", amount)
+	}
+	fee := d.Mul(decimal.NewFromFloat(float64(100))).IntPart()
+	if fee <= 0 {
+		return 0, errors.New("付款金额必须大于0")
+	}
+	return fee, nil
+}
+
+// OutTradeNo 收银台订单编号 CS + 时间 + 6位随机数
+func OutTradeNo() string {
+	n, _ := rand.Int(rand.Reader, big.NewInt(1000000))
+	return fmt.Sprintf("CS%s%06d", time.Now().Format("20060102150405"), n.Int64())
+}
+
+// Url 商户收银台地址 用于生成门店收款二维码 PAY_CASHIER_URL 为收银台服务的外网地址
+// 例如 https://xxx.com/pay-api/cashier 对应商户收银台 https://xxx.com/pay-api/cashier/index?store_id=
+func Url(storeId string) string {
+	u := env.Getenv("PAY_CASHIER_URL", "")
+	if u == "" {
+		return ""
+	}
+	return u + "/index?store_id=" + url.QueryEscape(storeId)
+}
@@ -0,0 +1,96 @@
+package cashier
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	configPB "github.com/lecex/pay/proto/config"
+)
+
+func TestClient(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148 MicroMessenger/7.0.10(0x17000a21) NetType/WIFI Language/zh_CN":                         Wechat,
+		"Mozilla/5.0 (Linux; Android 10; V1916A) AppleWebKit/537.36 Chrome/69.0.3497.100 Mobile Safari/537.36 AlipayChannelId/5136 AlipayClient/10.1.85.6000":                             Alipay,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148 (com.unionpay.chsp) (cordova 4.5.4) (updebug 0) (version 724) (UnionPay/1.0 CloudPay)": UnionPay,
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 Version/13.0.4 Mobile/15E148 Safari/604.1":                                                           "",
+	}
+	for ua, want := range cases {
+		if got := Client(ua); got != want {
+			t.Errorf("%s: want %q got %q", ua, want, got)
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	con := &configPB.Config{
+		Alipay: &configPB.Alipay{AppId: "2016"},
+		Wechat: &configPB.Wechat{},
+	}
+	if channel, err := Channel(Alipay, con); err != nil || channel != "alipay" {
+		t.Fatalf("alipay: %s %v", channel, err)
+	}
+	for _, client := range []string{Wechat, UnionPay, ""} {
+		if _, err := Channel(client, con); err == nil {
+			t.Errorf("%q: want error", client)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	ok := map[string]int64{"1": 100, "0.01": 1, "12.3": 1230, " 99.99 ": 9999}
+	for amount, want := range ok {
+		if got, err := ParseAmount(amount); err != nil || got != want {
+			t.Errorf("%q: want %d got %d %v", amount, want, got, err)
+		}
+	}
+	for _, amount := range []string{"", "0", "0.00", "-1", "1.001", "abc", "1e3", "123456789"} {
+		if _, err := ParseAmount(amount); err == nil {
+			t.Errorf("%q: want error", amount)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	body, err := Render(&Page{StoreId: "s1", StoreName: "<b>门店</b>", Client: Wechat, OpenIdToken: `o"1`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(body, "<b>门店</b>") || !strings.Contains(body, "&lt;b&gt;门店&lt;/b&gt;") {
+		t.Fatalf("store name not escaped")
+	}
+	if strings.Contains(body, `o"1`) {
+		t.Fatalf("open id not escaped")
+	}
+}
+
+func TestOpenIdToken(t *testing.T) {
+	now := time.Now()
+	token := OpenIdToken("secret", "s1", "oUpF8uN95-Pteags6E_roPHg7AG", now.Add(OpenIdTTL))
+	if openId, err := OpenId("secret", "s1", token, now); err != nil || openId != "oUpF8uN95-Pteags6E_roPHg7AG" {
+		t.Fatalf("openid = %q, %v", openId, err)
+	}
+	cases := map[string]string{
+		"other secret": "secret2",
+		"other store":  "secret",
+		"forged":       "secret",
+		"expired":      "secret",
+	}
+	for name, secret := range cases {
+		storeId, tok, at := "s1", token, now
+		switch name {
+		case "other store":
+			storeId = "s2"
+		case "forged":
+			tok = "other" + token[strings.Index(token, "."):]
+		case "expired":
+			at = now.Add(OpenIdTTL + time.Minute)
+		}
+		if _, err := OpenId(secret, storeId, tok, at); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+	if _, err := OpenId("", "s1", token, now); err == nil {
+		t.Error("want error without secret")
+	}
+}
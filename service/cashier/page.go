@@ -0,0 +1,140 @@
+package cashier
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// Page 收银台输入金额页面数据
+type Page struct {
+	StoreId     string // 商户ID
+	StoreName   string // 商户名称
+	Client      string // 扫码客户端
+	OpenIdToken string // 微信用户openid令牌
+	ReturnUrl   string // 支付完成后跳转地址
+}
+
+const head = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width,initial-scale=1,maximum-scale=1,user-scalable=no">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;font-family:-apple-system,"PingFang SC","Microsoft YaHei",sans-serif;background:#f5f5f5;color:#333}
+.box{margin:20px 15px;padding:20px;background:#fff;border-radius:8px}
+.name{font-size:18px;text-align:center;margin-bottom:20px}
+.label{font-size:14px;color:#999}
+.amount{display:flex;align-items:center;border-bottom:1px solid #eee;padding:10px 0;font-size:36px}
+.amount input{flex:1;border:0;outline:0;font-size:36px;width:100%}
+.btn{display:block;width:100%;margin-top:30px;padding:12px 0;border:0;border-radius:6px;font-size:18px;color:#fff;background:#1aad19}
+.btn.alipay{background:#1677ff}
+.btn:disabled{opacity:.6}
+.msg{margin-top:15px;min-height:20px;text-align:center;font-size:14px;color:#e64340}
+.error{text-align:center;padding:40px 0;font-size:16px}
+</style>
+</head>
+<body>
+`
+
+var amountTemplate = template.Must(template.New("amount").Parse(head + `<div class="box">
+<div class="name">{{.Page.StoreName}}</div>
+<div class="label">付款金额</div>
+<div class="amount">¥<input id="amount" type="number" step="0.01" min="0.01" inputmode="decimal" autofocus></div>
+<button id="pay" class="btn {{.Page.Client}}">付款</button>
+<div id="msg" class="msg"></div>
+</div>
+<script>
+(function () {
+  var page = {storeId: {{.Page.StoreId}}, client: {{.Page.Client}}, openIdToken: {{.Page.OpenIdToken}}, returnUrl: {{.Page.ReturnUrl}}};
+  var btn = document.getElementById('pay');
+  var msg = document.getElementById('msg');
+  function done(text) {
+    btn.disabled = false;
+    msg.innerText = text || '';
+  }
+  function success() {
+    if (page.returnUrl) {
+      location.href = page.returnUrl;
+      return;
+    }
+    document.querySelector('.box').innerHTML = '<div class="error">支付成功</div>';
+  }
+  function wechatPay(params) {
+    var invoke = function () {
+      WeixinJSBridge.invoke('getBrandWCPayRequest', params, function (r) {
+        if (r.err_msg === 'get_brand_wcpay_request:ok') {
+          success();
+        } else if (r.err_msg === 'get_brand_wcpay_request:cancel') {
+          done('已取消支付');
+        } else {
+          done('支付失败:' + r.err_msg);
+        }
+      });
+    };
+    if (typeof WeixinJSBridge === 'undefined') {
+      document.addEventListener('WeixinJSBridgeReady', invoke, false);
+    } else {
+      invoke();
+    }
+  }
+  btn.onclick = function () {
+    var amount = document.getElementById('amount').value;
+    if (!/^\d+(\.\d{1,2})?$/.test(amount) || Number(amount) <= 0) {
+      done('请输入正确的付款金额');
+      return;
+    }
+    btn.disabled = true;
+    msg.innerText = '';
+    var xhr = new XMLHttpRequest();
+    xhr.open('POST', 'pay');
+    xhr.setRequestHeader('Content-Type', 'application/x-www-form-urlencoded');
+    xhr.onload = function () {
+      var r;
+      try {
+        r = JSON.parse(xhr.responseText);
+      } catch (e) {
+        return done('下单失败 请稍后重试');
+      }
+      if (r.return_code !== 'SUCCESS') {
+        return done(r.return_msg || '下单失败 请稍后重试');
+      }
+      if (page.client === 'wechat') {
+        wechatPay(JSON.parse(r.pay_params));
+      } else {
+        location.href = r.pay_url;
+      }
+    };
+    xhr.onerror = function () {
+      done('网络错误 请稍后重试');
+    };
+    xhr.send('store_id=' + encodeURIComponent(page.storeId) + '&amount=' + encodeURIComponent(amount) + '&open_id_token=' + encodeURIComponent(page.openIdToken));
+  };
+})();
+</script>
+</body>
+</html>
+`))
+
+var errorTemplate = template.Must(template.New("error").Parse(head + `<div class="box"><div class="error">{{.Msg}}</div></div>
+</body>
+</html>
+`))
+
+// Render 渲染输入金额页面
+func Render(page *Page) (string, error) {
+	buf := &bytes.Buffer{}
+	title := page.StoreName
+	if title == "" {
+		title = "付款"
+	}
+	err := amountTemplate.Execute(buf, map[string]interface{}{"Title": title, "Page": page})
+	return buf.String(), err
+}
+
+// RenderError 渲染错误提示页面
+func RenderError(msg string) string {
+	buf := &bytes.Buffer{}
+	errorTemplate.Execute(buf, map[string]interface{}{"Title": "付款", "Msg": msg})
+	return buf.String()
+}
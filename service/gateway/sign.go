@@ -0,0 +1,188 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/lecex/core/env"
+
+	appPB "github.com/lecex/pay/proto/app"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/util"
+)
+
+// 签名方式
+const (
+	RSA2       = "RSA2"        // SHA256WithRSA 应用私钥签名 平台使用应用公钥验签
+	HmacSHA256 = "HMAC-SHA256" // hex(HMAC-SHA256(应用秘钥, 待签名字符串))
+)
+
+var (
+	platformOnce sync.Once
+	platformKey  string
+	platformErr  error
+)
+
+// Platform 平台私钥 用于应答签名 PAY_PLATFORM_PRIVATE_KEY 或 PAY_PLATFORM_PRIVATE_KEY_FILE 未配置时应答不签名
+func Platform() (string, error) {
+	platformOnce.Do(func() {
+		platformKey = env.Getenv("PAY_PLATFORM_PRIVATE_KEY", "")
+		if file := env.Getenv("PAY_PLATFORM_PRIVATE_KEY_FILE", ""); platformKey == "" && file != "" {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				platformErr = fmt.Errorf("读取平台私钥失败:%s", err.Error())
+				return
+			}
+			platformKey = string(b)
+		}
+		if platformKey != "" {
+			_, platformErr = util.ParsePrivateKey(platformKey)
+		}
+	})
+	return platformKey, platformErr
+}
+
+// RequestContent 请求待签名字符串 公共参数与 biz_content 内的参数按参数名升序拼接 空值不参与签名
+// method 为接口名称 如 AopF2F 防止签名被用于其它接口
+func RequestContent(method string, req *pb.Request) string {
+	params := flatten(req.BizContent)
+	params["method"] = method
+	params["store_id"] = req.StoreId
+	params["app_id"] = req.AppId
+	params["sign_type"] = req.SignType
+	params["timestamp"] = req.Timestamp
+	params["nonce"] = req.Nonce
+	return util.SignContent(params)
+}
+
+// ResponseContent 应答待签名字符串 content 内的参数按参数名升序拼接 空值不参与签名 数组为 JSON 内容
+func ResponseContent(content *pb.Content) string {
+	return util.SignContent(flatten(content))
+}
+
+// flatten 转换为参数 零值不参与签名 数字保持原格式
+func flatten(v interface{}) map[string]string {
+	params := map[string]string{}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return params
+	}
+	values := map[string]interface{}{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if d.Decode(&values) != nil {
+		return params
+	}
+	for k, value := range values {
+		switch value := value.(type) {
+		case string:
+			params[k] = value
+		case json.Number:
+			params[k] = value.String()
+		case bool:
+			params[k] = fmt.Sprint(value)
+		case nil:
+		default:
+			b, _ := json.Marshal(value)
+			params[k] = string(b)
+		}
+	}
+	return params
+}
+
+// Verify 使用应用注册的公钥或秘钥验签
+func Verify(app *appPB.App, content string, sign string) error {
+	switch app.SignType {
+	case RSA2:
+		return util.RsaVerify(app.PublicKey, content, sign, RSA2)
+	case HmacSHA256:
+		if app.Secret == "" || !hmac.Equal([]byte(strings.ToLower(sign)), []byte(HmacSign(app.Secret, content))) {
+			return errors.New("验签失败")
+		}
+		return nil
+	}
+	return fmt.Errorf("不支持的签名方式:%s", app.SignType)
+}
+
+// HmacSign HMAC-SHA256 签名 返回小写 hex
+func HmacSign(secret string, content string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Sign 使用平台私钥对应答签名
+func Sign(key string, content *pb.Content) (string, error) {
+	return util.RsaSign(key, ResponseContent(content), RSA2)
+}
+
+// Contains 逗号分隔的列表是否包含 value
+func Contains(list string, value string) bool {
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedIP IP 是否在白名单内 白名单为空时不限制 支持 CIDR
+func AllowedIP(allowlist string, ip string) bool {
+	if strings.TrimSpace(allowlist) == "" {
+		return true
+	}
+	return Matches(allowlist, ip)
+}
+
+// Matches IP 是否匹配逗号分隔的 IP 或 CIDR 列表 列表为空时不匹配
+func Matches(list string, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if _, n, err := net.ParseCIDR(v); err == nil {
+			if n.Contains(addr) {
+				return true
+			}
+		} else if allowed := net.ParseIP(v); allowed != nil && allowed.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 调用方 IP remote 为连接对端地址 对端是可信代理时从 X-Forwarded-For 最右侧开始跳过可信代理
+// 取第一个非可信代理地址 客户端自行添加的 X-Forwarded-For 左侧地址不会被使用 remote 为空时返回空
+func ClientIP(remote string, forwardedFor string, trustedProxies string) string {
+	ip := remote
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		ip = host
+	}
+	ip = strings.TrimSpace(ip)
+	if ip == "" || !Matches(trustedProxies, ip) {
+		return ip
+	}
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !Matches(trustedProxies, hop) {
+			break
+		}
+	}
+	return ip
+}
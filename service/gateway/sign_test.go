@@ -0,0 +1,100 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+
+	appPB "github.com/lecex/pay/proto/app"
+	pb "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/util"
+)
+
+func TestSign(t *testing.T) {
+	req := &pb.Request{
+		StoreId:    "store-0",
+		BizContent: &pb.BizContent{OutTradeNo: "T1", TotalFee: 180, Polling: true, Title: "测试"},
+		AppId:      "app-1",
+		SignType:   RSA2,
+		Timestamp:  "1600000000",
+		Nonce:      "n1",
+		Sign:       "ignored",
+	}
+	want := "app_id=app-1&method=AopF2F&nonce=n1&out_trade_no=T1&polling=true&sign_type=RSA2&store_id=store-0&timestamp=1600000000&title=测试&total_fee=180"
+	content := RequestContent("AopF2F", req)
+	if content != want {
+		t.Fatalf("RequestContent = %s", content)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key))
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	app := &appPB.App{SignType: RSA2, PublicKey: base64.StdEncoding.EncodeToString(der)}
+	sign, _ := util.RsaSign(privateKey, content, RSA2)
+	if err := Verify(app, content, sign); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(app, RequestContent("Refund", req), sign); err == nil {
+		t.Fatal("signature accepted for another method")
+	}
+
+	app = &appPB.App{SignType: HmacSHA256, Secret: "secret"}
+	if err := Verify(app, content, HmacSign("secret", content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(app, content, HmacSign("other", content)); err == nil {
+		t.Fatal("signature accepted with wrong secret")
+	}
+
+	res := &pb.Content{ReturnCode: "SUCCESS", TotalFee: 180, RefundList: []*pb.Refund{{OutTradeNo: "R1", Status: "SUCCESS"}}}
+	if got := ResponseContent(res); got != `refund_list=[{"out_trade_no":"R1","status":"SUCCESS"}]&return_code=SUCCESS&total_fee=180` {
+		t.Fatalf("ResponseContent = %s", got)
+	}
+	sign, err = Sign(privateKey, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.RsaVerify(base64.StdEncoding.EncodeToString(der), ResponseContent(res), sign, RSA2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAllowedIP(t *testing.T) {
+	for _, c := range []struct {
+		allowlist, ip string
+		want          bool
+	}{
+		{"", "1.2.3.4", true},
+		{"1.2.3.4, 10.0.0.0/8", "10.9.8.7", true},
+		{"1.2.3.4, 10.0.0.0/8", "1.2.3.4", true},
+		{"1.2.3.4, 10.0.0.0/8", "1.2.3.5", false},
+		{"10.0.0.0/8", "", false},
+	} {
+		if got := AllowedIP(c.allowlist, c.ip); got != c.want {
+			t.Errorf("AllowedIP(%q, %q) = %v", c.allowlist, c.ip, got)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	const trusted = "172.16.0.0/12, 192.168.1.1"
+	for _, c := range []struct {
+		remote, forwardedFor, want string
+	}{
+		{"8.8.8.8:5000", "10.1.2.3", "8.8.8.8"},                           // 直接调用 不信任 X-Forwarded-For
+		{"172.16.0.1:5000", "10.1.2.3", "10.1.2.3"},                       // 可信代理追加的地址
+		{"172.16.0.1:5000", "10.1.2.3, 8.8.8.8", "8.8.8.8"},               // 客户端伪造的左侧地址不使用
+		{"172.16.0.1:5000", "8.8.8.8, 10.1.2.3, 192.168.1.1", "10.1.2.3"}, // 跳过多级可信代理
+		{"172.16.0.1:5000", "", "172.16.0.1"},
+		{"", "10.1.2.3", ""},
+	} {
+		if got := ClientIP(c.remote, c.forwardedFor, trusted); got != c.want {
+			t.Errorf("ClientIP(%q, %q) = %q, want %q", c.remote, c.forwardedFor, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/util"
+	pb "github.com/lecex/pay/proto/app"
+	"github.com/lecex/pay/service/secret"
+)
+
+// App 仓库接口
+type App interface {
+	List(req *pb.ListQuery) ([]*pb.App, error)
+	Total(req *pb.ListQuery) (int64, error)
+	Get(app *pb.App) error
+	Create(app *pb.App) error
+	Update(app *pb.App) error
+	Delete(app *pb.App) (bool, error)
+}
+
+// AppRepository 开发者应用仓库 应用秘钥加密保存
+type AppRepository struct {
+	DB *gorm.DB
+}
+
+// List 获取开发者应用列表
+func (repo *AppRepository) List(req *pb.ListQuery) (apps []*pb.App, err error) {
+	db := repo.DB
+	limit, offset := util.Page(req.Limit, req.Page) // 分页
+	sort := util.Sort(req.Sort)                     // 排序 默认 created_at desc
+	if req.App != nil {
+		db = db.Where(req.App)
+	}
+	// 查询条件
+	if err := db.Where(req.Where).Order(sort).Limit(limit).Offset(offset).Find(&apps).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	for _, app := range apps {
+		if err = decryptApp(app); err != nil {
+			return nil, err
+		}
+	}
+	return apps, nil
+}
+
+// Total 获取开发者应用总量
+func (repo *AppRepository) Total(req *pb.ListQuery) (total int64, err error) {
+	apps := []pb.App{}
+	db := repo.DB
+	if req.App != nil {
+		db = db.Where(req.App)
+	}
+	if err := db.Where(req.Where).Find(&apps).Count(&total).Error; err != nil {
+		log.Log(err)
+		return total, err
+	}
+	return total, nil
+}
+
+// Get 根据 id 或 app_id 获取开发者应用
+func (repo *AppRepository) Get(app *pb.App) error {
+	db := repo.DB
+	if app.Id != 0 {
+		db = db.Where("id = ?", app.Id)
+	} else {
+		db = db.Where("app_id = ?", app.AppId)
+	}
+	if err := db.Find(app).Error; err != nil {
+		return err
+	}
+	return decryptApp(app)
+}
+
+// Create 创建开发者应用
+func (repo *AppRepository) Create(app *pb.App) error {
+	restore, err := encryptApp(app)
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("创建应用失败")
+	}
+	defer restore()
+	err = repo.DB.Create(app).Error
+	if err != nil {
+		// 写入数据库未知失败记录
+		log.Log(err)
+		return fmt.Errorf("创建应用失败")
+	}
+	return nil
+}
+
+// Update 更新开发者应用
+func (repo *AppRepository) Update(app *pb.App) error {
+	if app.Id == 0 {
+		return fmt.Errorf("请传入更新id")
+	}
+	app.CreatedAt = ""
+	restore, err := encryptApp(app)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return repo.DB.Save(app).Error
+}
+
+// Delete 删除开发者应用
+func (repo *AppRepository) Delete(app *pb.App) (valid bool, err error) {
+	if app.Id == 0 {
+		return valid, fmt.Errorf("请传入删除id")
+	}
+	err = repo.DB.Delete(app).Error
+	if err != nil {
+		log.Log(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// encryptApp 加密应用秘钥 返回恢复明文的函数
+func encryptApp(app *pb.App) (restore func(), err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return nil, err
+	}
+	plaintext := app.Secret
+	restore = func() { app.Secret = plaintext }
+	if app.Secret, err = k.Encrypt(plaintext); err != nil {
+		restore()
+		return nil, err
+	}
+	return restore, nil
+}
+
+// decryptApp 解密应用秘钥
+func decryptApp(app *pb.App) (err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return err
+	}
+	if app.Secret, err = k.Decrypt(app.Secret); err != nil {
+		return fmt.Errorf("应用%s秘钥解密失败:%s", app.AppId, err.Error())
+	}
+	return nil
+}
+
+// EncryptSecrets 加密未加密的应用秘钥 rotate 为 true 时使用当前主密钥重新加密 返回更新的记录数量
+func (repo *AppRepository) EncryptSecrets(rotate bool) (count int, err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return 0, err
+	}
+	if k == nil {
+		return 0, errors.New("未配置主密钥:PAY_MASTER_KEY")
+	}
+	apps := []*pb.App{}
+	if err = repo.DB.Find(&apps).Error; err != nil {
+		return count, err
+	}
+	for _, a := range apps {
+		n, err := reencrypt(repo.DB, k, rotate, a, map[string]*string{"secret": &a.Secret})
+		if count += n; err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/util"
+	pb "github.com/lecex/pay/proto/bill"
+	orderPB "github.com/lecex/pay/proto/order"
+)
+
+// Bill 仓库接口
+type Bill interface {
+	Orders(storeId string, channel string, start string, end string) ([]*orderPB.Order, error)
+	Save(storeId string, channel string, billDate string, discrepancies []*pb.BillDiscrepancy) error
+	List(req *pb.ListQuery) ([]*pb.BillDiscrepancy, error)
+	Total(req *pb.ListQuery) (int64, error)
+	Finished(billDate string) (bool, error)
+	Finish(billDate string, finishedAt string) error
+}
+
+// BillRepository 对账仓库
+type BillRepository struct {
+	DB *gorm.DB
+}
+
+// Orders 获取商户通道在时间范围内创建的订单
+func (repo *BillRepository) Orders(storeId string, channel string, start string, end string) (orders []*orderPB.Order, err error) {
+	err = repo.DB.Where("store_id = ?", storeId).
+		Where("channel = ?", channel).
+		Where("created_at >= ?", start).
+		Where("created_at < ?", end).
+		Find(&orders).Error
+	if err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Save 保存对账差异 重新对账时覆盖同一商户通道日期的差异
+func (repo *BillRepository) Save(storeId string, channel string, billDate string, discrepancies []*pb.BillDiscrepancy) (err error) {
+	tx := repo.DB.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	err = tx.Where("store_id = ?", storeId).
+		Where("channel = ?", channel).
+		Where("bill_date = ?", billDate).
+		Delete(&pb.BillDiscrepancy{}).Error
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("清除对账差异失败")
+	}
+	for _, d := range discrepancies {
+		if err = tx.Create(d).Error; err != nil {
+			log.Log(err)
+			return fmt.Errorf("保存对账差异失败")
+		}
+	}
+	return tx.Commit().Error
+}
+
+// List 获取对账差异列表
+func (repo *BillRepository) List(req *pb.ListQuery) (discrepancies []*pb.BillDiscrepancy, err error) {
+	db := repo.DB
+	limit, offset := util.Page(req.Limit, req.Page) // 分页
+	sort := util.Sort(req.Sort)                     // 排序 默认 created_at desc
+	// 查询条件
+	if err := db.Where(req.Where).Order(sort).Limit(limit).Offset(offset).Find(&discrepancies).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+// Total 获取对账差异总量
+func (repo *BillRepository) Total(req *pb.ListQuery) (total int64, err error) {
+	discrepancies := []pb.BillDiscrepancy{}
+	db := repo.DB
+	if err := db.Where(req.Where).Find(&discrepancies).Count(&total).Error; err != nil {
+		log.Log(err)
+		return total, err
+	}
+	return total, nil
+}
+
+// Finished 对账日期是否已完成每日对账
+func (repo *BillRepository) Finished(billDate string) (bool, error) {
+	var total int64
+	if err := repo.DB.Table("bill_runs").Where("bill_date = ?", billDate).Count(&total).Error; err != nil {
+		log.Log(err)
+		return false, err
+	}
+	return total > 0, nil
+}
+
+// Finish 记录对账日期已完成每日对账
+func (repo *BillRepository) Finish(billDate string, finishedAt string) error {
+	err := repo.DB.Exec("INSERT INTO bill_runs (bill_date, finished_at) VALUES (?, ?) ON DUPLICATE KEY UPDATE finished_at = VALUES(finished_at)", billDate, finishedAt).Error
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("记录对账完成失败")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	pb "github.com/lecex/pay/proto/order"
+)
+
+// ChannelLog 仓库接口
+type ChannelLog interface {
+	Create(channelLog *pb.ChannelLog) error
+	List(order *pb.Order) ([]*pb.ChannelLog, error)
+}
+
+// ChannelLogRepository 通道请求记录仓库
+type ChannelLogRepository struct {
+	DB *gorm.DB
+}
+
+// Create 记录通道请求
+func (repo *ChannelLogRepository) Create(channelLog *pb.ChannelLog) error {
+	if err := repo.DB.Create(channelLog).Error; err != nil {
+		log.Log(err)
+		return fmt.Errorf("记录通道请求失败")
+	}
+	return nil
+}
+
+// List 获取订单通道请求记录 包含订单号相同但请求时订单尚未创建的记录 按请求顺序排列
+func (repo *ChannelLogRepository) List(order *pb.Order) (logs []*pb.ChannelLog, err error) {
+	db := repo.DB.Where("order_id = ?", order.Id)
+	if order.StoreId != "" && order.OutTradeNo != "" {
+		db = db.Or("store_id = ? AND out_trade_no = ?", order.StoreId, order.OutTradeNo)
+	}
+	if err := db.Order("id").Find(&logs).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return logs, nil
+}
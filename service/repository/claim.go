@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/jinzhu/gorm"
+)
+
+// claim 抢占任务 条件包含查询到的状态 status 和下次执行时间 dueAt
+// 其它实例先抢占时 next_at 已顺延 更新记录数为 0 返回 false
+func claim(db *gorm.DB, model interface{}, column string, value interface{}, status int64, attempts int64, dueAt string, nextAt string) (bool, error) {
+	db = db.Model(model).
+		Where(column+" = ?", value).
+		Where("status = ?", status).
+		Where("attempts = ?", attempts).
+		Where("next_at = ?", datetime(dueAt)).
+		Update("next_at", nextAt)
+	if db.Error != nil {
+		return false, db.Error
+	}
+	return db.RowsAffected > 0, nil
+}
+
+// datetime 统一为 2006-01-02 15:04:05 连接开启 parseTime 时时间字段读取为 RFC3339 格式
+func datetime(s string) string {
+	if len(s) >= 19 && s[10] == 'T' {
+		return s[:10] + " " + s[11:19]
+	}
+	return s
+}
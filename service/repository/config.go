@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	// 公共引入
 	"github.com/jinzhu/gorm"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/lecex/core/util"
 	pb "github.com/lecex/pay/proto/config"
+	"github.com/lecex/pay/service/secret"
 )
 
 //Config 仓库接口
@@ -36,9 +38,15 @@ func (repo *ConfigRepository) List(req *pb.ListQuery) (configs []*pb.Config, err
 		log.Log(err)
 		return nil, err
 	}
-	// 查询关联
+	// 查询关联 秘钥解密失败的配置清空秘钥后返回 不影响其它配置
 	for _, config := range configs {
 		repo.Related(config)
+		if err := decrypt(config); err != nil {
+			log.Log(err)
+			for _, field := range secrets(config) {
+				*field = ""
+			}
+		}
 	}
 	return configs, nil
 }
@@ -68,12 +76,18 @@ func (repo *ConfigRepository) Get(config *pb.Config) error {
 	}
 	// 查询关联
 	repo.Related(config)
-	return nil
+	return decrypt(config)
 }
 
 // Create 创建配置
 func (repo *ConfigRepository) Create(config *pb.Config) error {
-	err := repo.DB.Create(config).Error
+	restore, err := encrypt(config)
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("注册配置失败")
+	}
+	defer restore()
+	err = repo.DB.Create(config).Error
 	if err != nil {
 		// 写入数据库未知失败记录
 		log.Log(err)
@@ -88,6 +102,12 @@ func (repo *ConfigRepository) Update(config *pb.Config) error {
 		return fmt.Errorf("请传入更新id")
 	}
 	config.CreatedAt = ""
+	restore, err := encrypt(config)
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("更新配置失败")
+	}
+	defer restore()
 	return repo.DB.Save(config).Error
 }
 
@@ -145,3 +165,133 @@ func (repo *ConfigRepository) Related(config *pb.Config) error {
 	config.Icbc = Icbc
 	return nil
 }
+
+// secrets 商户秘钥字段 加密后保存
+func secrets(config *pb.Config) (fields []*string) {
+	fields = append(fields, &config.NotifySecret)
+	if config.Alipay != nil {
+		fields = append(fields, &config.Alipay.PrivateKey)
+	}
+	if config.Wechat != nil {
+		fields = append(fields, &config.Wechat.ApiKey, &config.Wechat.PemKey, &config.Wechat.AppSecret)
+	}
+	if config.Icbc != nil {
+		fields = append(fields, &config.Icbc.PrivateKey)
+	}
+	return fields
+}
+
+// encrypt 加密商户秘钥 返回恢复明文的函数 保存后恢复 调用方的配置内容不变
+func encrypt(config *pb.Config) (restore func(), err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return nil, err
+	}
+	fields := secrets(config)
+	plaintexts := make([]string, len(fields))
+	restore = func() {
+		for i, field := range fields {
+			*field = plaintexts[i]
+		}
+	}
+	for i, field := range fields {
+		plaintexts[i] = *field
+	}
+	for _, field := range fields {
+		if *field, err = k.Encrypt(*field); err != nil {
+			restore()
+			return nil, err
+		}
+	}
+	return restore, nil
+}
+
+// decrypt 解密商户秘钥 未加密的历史数据直接返回
+func decrypt(config *pb.Config) (err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return err
+	}
+	for _, field := range secrets(config) {
+		if *field, err = k.Decrypt(*field); err != nil {
+			return fmt.Errorf("商户%s秘钥解密失败:%s", config.Id, err.Error())
+		}
+	}
+	return nil
+}
+
+// EncryptSecrets 加密未加密的商户秘钥 rotate 为 true 时将非当前主密钥加密的秘钥使用当前主密钥重新加密 返回更新的记录数量
+func (repo *ConfigRepository) EncryptSecrets(rotate bool) (count int, err error) {
+	k, err := secret.Default()
+	if err != nil {
+		return 0, err
+	}
+	if k == nil {
+		return 0, errors.New("未配置主密钥:PAY_MASTER_KEY")
+	}
+	configs := []*pb.Config{}
+	if err = repo.DB.Find(&configs).Error; err != nil {
+		return count, err
+	}
+	for _, c := range configs {
+		n, err := reencrypt(repo.DB, k, rotate, c, map[string]*string{"notify_secret": &c.NotifySecret})
+		if count += n; err != nil {
+			return count, err
+		}
+	}
+	alipays := []*pb.Alipay{}
+	if err = repo.DB.Find(&alipays).Error; err != nil {
+		return count, err
+	}
+	for _, a := range alipays {
+		n, err := reencrypt(repo.DB, k, rotate, a, map[string]*string{"private_key": &a.PrivateKey})
+		if count += n; err != nil {
+			return count, err
+		}
+	}
+	wechats := []*pb.Wechat{}
+	if err = repo.DB.Find(&wechats).Error; err != nil {
+		return count, err
+	}
+	for _, w := range wechats {
+		n, err := reencrypt(repo.DB, k, rotate, w, map[string]*string{"api_key": &w.ApiKey, "pem_key": &w.PemKey, "app_secret": &w.AppSecret})
+		if count += n; err != nil {
+			return count, err
+		}
+	}
+	icbcs := []*pb.Icbc{}
+	if err = repo.DB.Find(&icbcs).Error; err != nil {
+		return count, err
+	}
+	for _, i := range icbcs {
+		n, err := reencrypt(repo.DB, k, rotate, i, map[string]*string{"private_key": &i.PrivateKey})
+		if count += n; err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// reencrypt 加密记录中需要加密的字段并保存 返回是否更新
+func reencrypt(db *gorm.DB, k *secret.Keyring, rotate bool, model interface{}, fields map[string]*string) (int, error) {
+	columns := map[string]interface{}{}
+	for column, field := range fields {
+		if secret.Encrypted(*field) && (!rotate || k.Current(*field)) {
+			continue
+		}
+		v, err := k.Rotate(*field)
+		if err != nil {
+			return 0, err
+		}
+		if v != *field {
+			columns[column] = v
+		}
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+	if err := db.Model(model).UpdateColumns(columns).Error; err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
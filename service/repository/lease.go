@@ -0,0 +1,38 @@
+package repository
+
+import (
+	// 公共引入
+	"github.com/jinzhu/gorm"
+)
+
+// Lease 仓库接口 多个实例之间通过数据库租约保证同一时间只有一个实例执行后台任务
+type Lease interface {
+	Acquire(name string, owner string, now string, expiredAt string) (bool, error)
+	Release(name string, owner string, now string) error
+}
+
+// LeaseRepository 租约仓库
+type LeaseRepository struct {
+	DB *gorm.DB
+}
+
+// Acquire 获取或续期租约 租约不存在、已过期或由 owner 持有时成功
+func (repo *LeaseRepository) Acquire(name string, owner string, now string, expiredAt string) (bool, error) {
+	db := repo.DB.Exec("INSERT IGNORE INTO leases (name, owner, expired_at) VALUES (?, ?, ?)", name, owner, expiredAt)
+	if db.Error != nil {
+		return false, db.Error
+	}
+	if db.RowsAffected > 0 {
+		return true, nil
+	}
+	db = repo.DB.Exec("UPDATE leases SET owner = ?, expired_at = ? WHERE name = ? AND (owner = ? OR expired_at <= ?)", owner, expiredAt, name, owner, now)
+	if db.Error != nil {
+		return false, db.Error
+	}
+	return db.RowsAffected > 0, nil
+}
+
+// Release 释放 owner 持有的租约 其它实例可立即获取
+func (repo *LeaseRepository) Release(name string, owner string, now string) error {
+	return repo.DB.Exec("UPDATE leases SET expired_at = ? WHERE name = ? AND owner = ?", now, name, owner).Error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	// 公共引入
+	"github.com/jinzhu/gorm"
+)
+
+// Nonce 仓库接口 记录开放接口请求随机字符串 防止请求重放
+type Nonce interface {
+	Use(appId string, nonce string, createdAt string) (bool, error)
+	Clean(before string) error
+}
+
+// NonceRepository 请求随机字符串仓库
+type NonceRepository struct {
+	DB *gorm.DB
+}
+
+// Use 记录随机字符串 同一应用已使用过时返回 false
+func (repo *NonceRepository) Use(appId string, nonce string, createdAt string) (bool, error) {
+	db := repo.DB.Exec("INSERT IGNORE INTO nonces (app_id, nonce, created_at) VALUES (?, ?, ?)", appId, nonce, createdAt)
+	if db.Error != nil {
+		return false, db.Error
+	}
+	return db.RowsAffected > 0, nil
+}
+
+// Clean 删除超出时间戳有效期的随机字符串
+func (repo *NonceRepository) Clean(before string) error {
+	return repo.DB.Exec("DELETE FROM nonces WHERE created_at < ?", before).Error
+}
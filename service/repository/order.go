@@ -24,6 +24,14 @@ type Order interface {
 	StoreIdAndOutTradeNoGet(order *pb.Order) error
 	StoreIdAndTradeNoGet(order *pb.Order) error
 	Exist(config *pb.Order) bool
+	LockGet(order *pb.Order) error
+	RefundingFee(order *pb.Order) (int64, error)
+	Transaction(fn func(tx Order) error) error
+	CreateEvent(event *pb.OrderEvent) error
+	Events(order *pb.Order) ([]*pb.OrderEvent, error)
+	UpdateResponse(order *pb.Order) error
+	Pending(from string, to string, limit int64) ([]*pb.Order, error)
+	Touch(order *pb.Order, updatedAt string) error
 }
 
 // OrderRepository 订单仓库
@@ -169,3 +177,82 @@ func (repo *OrderRepository) Delete(order *pb.Order) (bool, error) {
 	}
 	return true, nil
 }
+
+// LockGet 根据订单ID获取订单并加行锁 需要在事务中调用 事务结束前其它事务无法修改该订单
+func (repo *OrderRepository) LockGet(order *pb.Order) error {
+	if order.Id == "" {
+		return fmt.Errorf("请传入订单id")
+	}
+	return repo.DB.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", order.Id).First(order).Error
+}
+
+// RefundingFee 获取已占用退款金额 包含待退款和退款成功的订单
+func (repo *OrderRepository) RefundingFee(o *pb.Order) (count int64, err error) {
+	order := &pb.Order{}
+	if err = repo.DB.Model(order).Select("SUM(total_fee) as refund_fee").Where("link_id = ?", o.Id).Where("status IN (0,1)").Find(order).Error; err != nil {
+		return 0, err
+	}
+	return -order.RefundFee, err
+}
+
+// Transaction 在同一数据库事务中执行 fn 返回错误时回滚
+func (repo *OrderRepository) Transaction(fn func(tx Order) error) (err error) {
+	tx := repo.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+	if err = fn(&OrderRepository{tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// CreateEvent 记录订单状态变更
+func (repo *OrderRepository) CreateEvent(event *pb.OrderEvent) error {
+	if err := repo.DB.Create(event).Error; err != nil {
+		log.Log(err)
+		return fmt.Errorf("记录订单状态变更失败")
+	}
+	return nil
+}
+
+// Events 获取订单状态变更记录 按变更顺序排列
+func (repo *OrderRepository) Events(order *pb.Order) (events []*pb.OrderEvent, err error) {
+	if err := repo.DB.Where("order_id = ?", order.Id).Order("id").Find(&events).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return events, nil
+}
+
+// UpdateResponse 保存订单最终应答内容 只更新应答字段
+func (repo *OrderRepository) UpdateResponse(order *pb.Order) error {
+	if order.Id == "" {
+		return fmt.Errorf("请传入更新id")
+	}
+	return repo.DB.Model(&pb.Order{}).Where("id = ?", order.Id).UpdateColumn("response", order.Response).Error
+}
+
+// Pending 获取创建时间在 from 之后 最近更新时间在 to 之前的待付款和待退款订单 最久未更新的订单优先
+func (repo *OrderRepository) Pending(from string, to string, limit int64) (orders []*pb.Order, err error) {
+	if err := repo.DB.Where("status = 0").Where("created_at > ?", from).Where("updated_at <= ?", to).Order("updated_at").Limit(limit).Find(&orders).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Touch 更新订单最近更新时间 同步未完成的订单排到下次同步队列最后
+func (repo *OrderRepository) Touch(order *pb.Order, updatedAt string) error {
+	if order.Id == "" {
+		return fmt.Errorf("请传入更新id")
+	}
+	return repo.DB.Model(&pb.Order{}).Where("id = ?", order.Id).UpdateColumn("updated_at", updatedAt).Error
+}
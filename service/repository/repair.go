@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/util"
+	pb "github.com/lecex/pay/proto/repair"
+)
+
+// Repair 仓库接口
+type Repair interface {
+	List(req *pb.ListQuery) ([]*pb.Repair, error)
+	Total(req *pb.ListQuery) (int64, error)
+	Create(repair *pb.Repair) error
+	Update(repair *pb.Repair) error
+	Get(repair *pb.Repair) error
+	Due(now string, limit int64) ([]*pb.Repair, error)
+	Claim(repair *pb.Repair, nextAt string) (bool, error)
+}
+
+// RepairRepository 订单补偿仓库
+type RepairRepository struct {
+	DB *gorm.DB
+}
+
+// List 获取补偿记录列表
+func (repo *RepairRepository) List(req *pb.ListQuery) (repairs []*pb.Repair, err error) {
+	db := repo.DB
+	limit, offset := util.Page(req.Limit, req.Page) // 分页
+	sort := util.Sort(req.Sort)                     // 排序 默认 created_at desc
+	if req.Repair != nil {
+		db = db.Where(req.Repair)
+	}
+	// 查询条件
+	if err := db.Where(req.Where).Order(sort).Limit(limit).Offset(offset).Find(&repairs).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return repairs, nil
+}
+
+// Total 获取补偿记录总量
+func (repo *RepairRepository) Total(req *pb.ListQuery) (total int64, err error) {
+	repairs := []pb.Repair{}
+	db := repo.DB
+	if req.Repair != nil {
+		db = db.Where(req.Repair)
+	}
+	if err := db.Where(req.Where).Find(&repairs).Count(&total).Error; err != nil {
+		log.Log(err)
+		return total, err
+	}
+	return total, nil
+}
+
+// Get 获取补偿记录
+func (repo *RepairRepository) Get(repair *pb.Repair) error {
+	if err := repo.DB.Where("id = ?", repair.Id).Find(repair).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create 创建补偿记录
+func (repo *RepairRepository) Create(repair *pb.Repair) error {
+	err := repo.DB.Create(repair).Error
+	if err != nil {
+		// 写入数据库未知失败记录
+		log.Log(err)
+		return fmt.Errorf("创建补偿记录失败")
+	}
+	return nil
+}
+
+// Update 更新补偿记录
+func (repo *RepairRepository) Update(repair *pb.Repair) error {
+	if repair.Id == 0 {
+		return fmt.Errorf("请传入更新id")
+	}
+	repair.CreatedAt = ""
+	return repo.DB.Save(repair).Error
+}
+
+// Due 获取到期待补偿的记录
+func (repo *RepairRepository) Due(now string, limit int64) (repairs []*pb.Repair, err error) {
+	if err := repo.DB.Where("status = 0").Where("next_at <= ?", now).Order("next_at").Limit(limit).Find(&repairs).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return repairs, nil
+}
+
+// Claim 抢占补偿记录 成功后将下次补偿时间顺延 避免多个实例重复补偿 手动重试补偿失败的记录时同样抢占
+func (repo *RepairRepository) Claim(repair *pb.Repair, nextAt string) (bool, error) {
+	ok, err := claim(repo.DB, &pb.Repair{}, "id", repair.Id, repair.Status, repair.Attempts, repair.NextAt, nextAt)
+	if err != nil {
+		return false, err
+	}
+	repair.NextAt = nextAt
+	return ok, nil
+}
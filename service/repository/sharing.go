@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/util"
+	pb "github.com/lecex/pay/proto/sharing"
+)
+
+// Sharing 仓库接口
+type Sharing interface {
+	Rules(storeId string) ([]*pb.SharingRule, error)
+	SaveRules(storeId string, rules []*pb.SharingRule) error
+	CreateSplits(splits []*pb.SharingSplit) error
+	Splits(split *pb.SharingSplit) ([]*pb.SharingSplit, error)
+	Update(split *pb.SharingSplit) error
+	Due(now string, limit int64) ([]*pb.SharingSplit, error)
+	Claim(split *pb.SharingSplit, nextAt string) (bool, error)
+	List(req *pb.ListQuery) ([]*pb.SharingSplit, error)
+	Total(req *pb.ListQuery) (int64, error)
+}
+
+// SharingRepository 分账仓库
+type SharingRepository struct {
+	DB *gorm.DB
+}
+
+// Rules 获取商户分账规则
+func (repo *SharingRepository) Rules(storeId string) (rules []*pb.SharingRule, err error) {
+	if err := repo.DB.Where("store_id = ?", storeId).Order("id").Find(&rules).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return rules, nil
+}
+
+// SaveRules 保存商户分账规则 覆盖商户原有规则
+func (repo *SharingRepository) SaveRules(storeId string, rules []*pb.SharingRule) (err error) {
+	tx := repo.DB.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	if err = tx.Where("store_id = ?", storeId).Delete(&pb.SharingRule{}).Error; err != nil {
+		log.Log(err)
+		return fmt.Errorf("清除分账规则失败")
+	}
+	for _, rule := range rules {
+		rule.Id = 0
+		rule.StoreId = storeId
+		if err = tx.Create(rule).Error; err != nil {
+			log.Log(err)
+			return fmt.Errorf("保存分账规则失败")
+		}
+	}
+	return tx.Commit().Error
+}
+
+// CreateSplits 创建订单分账明细
+func (repo *SharingRepository) CreateSplits(splits []*pb.SharingSplit) (err error) {
+	tx := repo.DB.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+	for _, split := range splits {
+		if err = tx.Create(split).Error; err != nil {
+			log.Log(err)
+			return fmt.Errorf("创建分账明细失败")
+		}
+	}
+	return tx.Commit().Error
+}
+
+// Splits 根据非空字段查询分账明细
+func (repo *SharingRepository) Splits(split *pb.SharingSplit) (splits []*pb.SharingSplit, err error) {
+	if err := repo.DB.Where(split).Order("id").Find(&splits).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return splits, nil
+}
+
+// Update 更新分账明细
+func (repo *SharingRepository) Update(split *pb.SharingSplit) error {
+	if split.Id == 0 {
+		return fmt.Errorf("请传入更新id")
+	}
+	split.CreatedAt = ""
+	return repo.DB.Save(split).Error
+}
+
+// Due 获取到期待分账的明细
+func (repo *SharingRepository) Due(now string, limit int64) (splits []*pb.SharingSplit, err error) {
+	if err := repo.DB.Where("status = 0").Where("next_at <= ?", now).Order("next_at").Limit(limit).Find(&splits).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return splits, nil
+}
+
+// Claim 抢占订单分账 成功后将订单所有待分账明细的下次分账时间顺延 避免多个实例重复分账
+func (repo *SharingRepository) Claim(split *pb.SharingSplit, nextAt string) (bool, error) {
+	return claim(repo.DB, &pb.SharingSplit{}, "order_id", split.OrderId, 0, split.Attempts, split.NextAt, nextAt)
+}
+
+// List 获取分账明细列表
+func (repo *SharingRepository) List(req *pb.ListQuery) (splits []*pb.SharingSplit, err error) {
+	db := repo.DB
+	limit, offset := util.Page(req.Limit, req.Page) // 分页
+	sort := util.Sort(req.Sort)                     // 排序 默认 created_at desc
+	if req.Split != nil {
+		db = db.Where(req.Split)
+	}
+	// 查询条件
+	if err := db.Where(req.Where).Order(sort).Limit(limit).Offset(offset).Find(&splits).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return splits, nil
+}
+
+// Total 获取分账明细总量
+func (repo *SharingRepository) Total(req *pb.ListQuery) (total int64, err error) {
+	splits := []pb.SharingSplit{}
+	db := repo.DB
+	if req.Split != nil {
+		db = db.Where(req.Split)
+	}
+	if err := db.Where(req.Where).Find(&splits).Count(&total).Error; err != nil {
+		log.Log(err)
+		return total, err
+	}
+	return total, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"fmt"
+	// 公共引入
+	"github.com/jinzhu/gorm"
+	"github.com/micro/go-micro/v2/util/log"
+
+	"github.com/lecex/core/util"
+	pb "github.com/lecex/pay/proto/webhook"
+)
+
+// Webhook 仓库接口
+type Webhook interface {
+	List(req *pb.ListQuery) ([]*pb.Webhook, error)
+	Total(req *pb.ListQuery) (int64, error)
+	Create(webhook *pb.Webhook) error
+	Update(webhook *pb.Webhook) error
+	Get(webhook *pb.Webhook) error
+	Due(now string, limit int64) ([]*pb.Webhook, error)
+	Claim(webhook *pb.Webhook, nextAt string) (bool, error)
+	CreateLog(webhookLog *pb.WebhookLog) error
+	Logs(webhook *pb.Webhook) ([]*pb.WebhookLog, error)
+}
+
+// WebhookRepository 商户通知仓库
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+// List 获取所有通知信息
+func (repo *WebhookRepository) List(req *pb.ListQuery) (webhooks []*pb.Webhook, err error) {
+	db := repo.DB
+	limit, offset := util.Page(req.Limit, req.Page) // 分页
+	sort := util.Sort(req.Sort)                     // 排序 默认 created_at desc
+	if req.Webhook != nil {
+		db = db.Where(req.Webhook)
+	}
+	// 查询条件
+	if err := db.Where(req.Where).Order(sort).Limit(limit).Offset(offset).Find(&webhooks).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Total 获取所有通知查询总量
+func (repo *WebhookRepository) Total(req *pb.ListQuery) (total int64, err error) {
+	webhooks := []pb.Webhook{}
+	db := repo.DB
+	if req.Webhook != nil {
+		db = db.Where(req.Webhook)
+	}
+	if err := db.Where(req.Where).Find(&webhooks).Count(&total).Error; err != nil {
+		log.Log(err)
+		return total, err
+	}
+	return total, nil
+}
+
+// Get 获取通知信息
+func (repo *WebhookRepository) Get(webhook *pb.Webhook) error {
+	if err := repo.DB.Where(&webhook).Find(&webhook).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create 创建通知
+func (repo *WebhookRepository) Create(webhook *pb.Webhook) error {
+	err := repo.DB.Create(webhook).Error
+	if err != nil {
+		// 写入数据库未知失败记录
+		log.Log(err)
+		return fmt.Errorf("创建通知失败")
+	}
+	return nil
+}
+
+// Update 更新通知
+func (repo *WebhookRepository) Update(webhook *pb.Webhook) error {
+	if webhook.Id == "" {
+		return fmt.Errorf("请传入更新id")
+	}
+	webhook.CreatedAt = ""
+	return repo.DB.Save(webhook).Error
+}
+
+// Due 获取到期待推送的通知
+func (repo *WebhookRepository) Due(now string, limit int64) (webhooks []*pb.Webhook, err error) {
+	if err := repo.DB.Where("status = 0").Where("next_at <= ?", now).Order("next_at").Limit(limit).Find(&webhooks).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Claim 抢占通知推送 成功后将下次推送时间顺延 避免多个实例重复推送
+func (repo *WebhookRepository) Claim(webhook *pb.Webhook, nextAt string) (bool, error) {
+	ok, err := claim(repo.DB, &pb.Webhook{}, "id", webhook.Id, 0, webhook.Attempts, webhook.NextAt, nextAt)
+	if err != nil {
+		return false, err
+	}
+	webhook.NextAt = nextAt
+	return ok, nil
+}
+
+// CreateLog 创建推送记录
+func (repo *WebhookRepository) CreateLog(webhookLog *pb.WebhookLog) error {
+	err := repo.DB.Create(webhookLog).Error
+	if err != nil {
+		log.Log(err)
+		return fmt.Errorf("创建推送记录失败")
+	}
+	return nil
+}
+
+// Logs 获取通知推送记录
+func (repo *WebhookRepository) Logs(webhook *pb.Webhook) (logs []*pb.WebhookLog, err error) {
+	if err := repo.DB.Where("webhook_id = ?", webhook.Id).Order("id desc").Find(&logs).Error; err != nil {
+		log.Log(err)
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,24 @@
+package secret
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// maskPrefix 脱敏内容前缀 格式 ******:指纹 指纹为明文 sha256 前 8 位 用于核对秘钥是否一致
+const maskPrefix = "******:"
+
+// Mask 脱敏 返回秘钥指纹 空内容直接返回
+func Mask(plaintext string) string {
+	if plaintext == "" || Masked(plaintext) {
+		return plaintext
+	}
+	h := sha256.Sum256([]byte(plaintext))
+	return maskPrefix + hex.EncodeToString(h[:4])
+}
+
+// Masked 内容是否为脱敏内容
+func Masked(s string) bool {
+	return strings.HasPrefix(s, maskPrefix)
+}
@@ -0,0 +1,44 @@
+package secret
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScopeToken 权限令牌 格式 权限.过期时间戳.签名 多个权限以逗号分隔 签名为 hex(HMAC-SHA256(key, 权限.过期时间戳))
+// 由持有 key 的权限服务根据调用方身份签发 调用方无法自行伪造
+func ScopeToken(key string, scopes []string, expiresAt time.Time) string {
+	content := strings.Join(scopes, ",") + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return content + "." + scopeSign(key, content)
+}
+
+// Scopes 校验权限令牌并返回令牌中的权限 未配置 key、签名错误或令牌过期时返回空
+func Scopes(key string, token string, now time.Time) []string {
+	i := strings.LastIndex(token, ".")
+	if key == "" || i < 0 {
+		return nil
+	}
+	content, sign := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sign), []byte(scopeSign(key, content))) {
+		return nil
+	}
+	j := strings.LastIndex(content, ".")
+	if j < 0 {
+		return nil
+	}
+	expiresAt, err := strconv.ParseInt(content[j+1:], 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return nil
+	}
+	return strings.Split(content[:j], ",")
+}
+
+func scopeSign(key string, content string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
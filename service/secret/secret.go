@@ -0,0 +1,215 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/lecex/core/env"
+)
+
+// prefix 加密内容前缀 格式 enc:v1:主密钥ID:加密后的数据密钥:加密后的内容
+// 每个字段使用随机数据密钥 AES-256-GCM 加密 数据密钥由主密钥加密
+const prefix = "enc:v1:"
+
+// Keyring 主密钥 第一个为当前主密钥用于加密 其它为轮换前的主密钥只用于解密
+// 未配置主密钥时为 nil 不加密
+type Keyring struct {
+	current string
+	keys    map[string][]byte
+}
+
+var (
+	once    sync.Once
+	keyring *Keyring
+	loadErr error
+)
+
+// Default 根据环境变量加载主密钥 只加载一次
+func Default() (*Keyring, error) {
+	once.Do(func() {
+		keyring, loadErr = Load()
+	})
+	return keyring, loadErr
+}
+
+// Load 加载主密钥 PAY_MASTER_KEY 或 PAY_MASTER_KEY_FILE 文件内容为 base64 编码的 32 字节主密钥
+// 多个主密钥以逗号或换行分隔 第一个为当前主密钥 未配置时返回 nil
+func Load() (*Keyring, error) {
+	s := env.Getenv("PAY_MASTER_KEY", "")
+	if file := env.Getenv("PAY_MASTER_KEY_FILE", ""); s == "" && file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("读取主密钥文件失败:%s", err.Error())
+		}
+		s = string(b)
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return Parse(s)
+}
+
+// Parse 解析 base64 编码的主密钥 多个主密钥以逗号或换行分隔 第一个为当前主密钥
+func Parse(s string) (*Keyring, error) {
+	var keys [][]byte
+	for _, k := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' || r == ' ' }) {
+		key, err := base64.StdEncoding.DecodeString(k)
+		if err != nil {
+			return nil, fmt.Errorf("主密钥不是有效的 base64 编码:%s", err.Error())
+		}
+		keys = append(keys, key)
+	}
+	return NewKeyring(keys...)
+}
+
+// NewKeyring 创建主密钥 第一个为当前主密钥
+func NewKeyring(keys ...[]byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("未配置主密钥")
+	}
+	k := &Keyring{keys: map[string][]byte{}}
+	for i, key := range keys {
+		if len(key) != 32 {
+			return nil, errors.New("主密钥长度必须为32字节")
+		}
+		id := keyId(key)
+		if i == 0 {
+			k.current = id
+		}
+		k.keys[id] = key
+	}
+	return k, nil
+}
+
+// keyId 主密钥ID 用于解密时选择主密钥
+func keyId(key []byte) string {
+	h := sha256.Sum256(key)
+	return hex.EncodeToString(h[:4])
+}
+
+// Encrypted 内容是否已加密
+func Encrypted(s string) bool {
+	return strings.HasPrefix(s, prefix)
+}
+
+// Current 内容已使用当前主密钥加密 空内容视为已加密
+func (k *Keyring) Current(s string) bool {
+	if s == "" {
+		return true
+	}
+	if k == nil {
+		return !Encrypted(s)
+	}
+	return strings.HasPrefix(s, prefix+k.current+":")
+}
+
+// Encrypt 使用当前主密钥加密 空内容和已加密内容直接返回 未配置主密钥时不加密
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if k == nil || plaintext == "" || Encrypted(plaintext) {
+		return plaintext, nil
+	}
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.current], dek)
+	if err != nil {
+		return "", err
+	}
+	content, err := seal(dek, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.current + ":" + base64.StdEncoding.EncodeToString(wrapped) + ":" + base64.StdEncoding.EncodeToString(content), nil
+}
+
+// Decrypt 解密 未加密的历史内容直接返回
+func (k *Keyring) Decrypt(s string) (string, error) {
+	if !Encrypted(s) {
+		return s, nil
+	}
+	if k == nil {
+		return "", errors.New("内容已加密,未配置主密钥")
+	}
+	parts := strings.Split(strings.TrimPrefix(s, prefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("加密内容格式错误")
+	}
+	key, ok := k.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("未找到主密钥:%s", parts[0])
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("加密内容格式错误")
+	}
+	content, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("加密内容格式错误")
+	}
+	dek, err := open(key, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dek, content)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Rotate 使用当前主密钥重新加密 未加密的内容直接加密
+func (k *Keyring) Rotate(s string) (string, error) {
+	plaintext, err := k.Decrypt(s)
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(plaintext)
+}
+
+// seal AES-256-GCM 加密 随机 nonce 放在密文前
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open AES-256-GCM 解密
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("加密内容格式错误")
+	}
+	plaintext, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("解密失败")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,160 @@
+package sharing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/sharing"
+)
+
+// 分账状态
+const (
+	StatusFailed   = -1 // 分账失败 已达最大重试次数或订单退款取消分账
+	StatusPending  = 0  // 待分账
+	StatusSuccess  = 1  // 分账成功
+	StatusReturned = 2  // 已回退
+)
+
+// MaxRate 分账比率合计上限 万分率
+const MaxRate = 10000
+
+// TypeFinish 完结分账任务的接收方类型 下单时冻结资金但没有分账接收方的订单完结分账解冻资金
+const TypeFinish = "FINISH"
+
+// Receiver 分账接收方
+type Receiver struct {
+	Type         string // 接收方类型
+	Account      string // 接收方账号
+	Name         string // 接收方名称
+	RelationType string // 与分账方的关系
+	Amount       int64  // 分账金额 [单位分]
+	Description  string // 分账描述
+}
+
+// Validate 校验商户分账规则 同一通道分账比率合计不能超过订单金额
+func Validate(rules []*pb.SharingRule) error {
+	total := map[string]int64{}
+	for i, r := range rules {
+		if r.Channel != "alipay" && r.Channel != "wechat" {
+			return fmt.Errorf("分账规则%d:不支持分账的支付通道:%s", i+1, r.Channel)
+		}
+		if r.Type == "" || r.Account == "" {
+			return fmt.Errorf("分账规则%d:接收方类型和账号不允许为空", i+1)
+		}
+		if r.Channel == "wechat" && r.RelationType == "" {
+			return fmt.Errorf("分账规则%d:微信分账接收方关系不允许为空", i+1)
+		}
+		if r.Rate <= 0 {
+			return fmt.Errorf("分账规则%d:分账比率必须大于0", i+1)
+		}
+		total[r.Channel] += r.Rate
+		if total[r.Channel] > MaxRate {
+			return fmt.Errorf("%s分账比率合计不能超过%d", r.Channel, MaxRate)
+		}
+	}
+	return nil
+}
+
+// OutOrderNo 订单分账单号
+func OutOrderNo(order *orderPB.Order) string {
+	return "PS" + order.OutTradeNo
+}
+
+// OutReturnNo 分账回退单号 每笔退款的每个接收方使用不同的回退单号
+func OutReturnNo(split *pb.SharingSplit, outRefundNo string) string {
+	return "PR" + outRefundNo + "_" + strconv.FormatInt(split.Id, 10)
+}
+
+// ReturnAmount 退款需要回退的分账金额 按累计退款金额占订单金额的比例向下取整并扣除已回退金额
+// refundFee 为包含本次退款的累计退款金额 全额退款时回退全部剩余分账金额
+func ReturnAmount(split *pb.SharingSplit, totalFee int64, refundFee int64) int64 {
+	amount := Returned(split, totalFee, refundFee) - split.ReturnedAmount
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
+
+// Returned 累计退款金额对应的应回退分账金额 按比例向下取整 全额退款时为全部分账金额
+func Returned(split *pb.SharingSplit, totalFee int64, refundFee int64) int64 {
+	if refundFee >= totalFee {
+		return split.Amount
+	}
+	return split.Amount * refundFee / totalFee
+}
+
+// Splits 根据订单通道的分账规则计算分账明细 分账金额向下取整 金额为0的接收方不参与分账
+func Splits(rules []*pb.SharingRule, order *orderPB.Order, nextAt string) (splits []*pb.SharingSplit) {
+	for _, r := range rules {
+		if r.Channel != order.Channel {
+			continue
+		}
+		amount := order.TotalFee * r.Rate / MaxRate
+		if amount <= 0 {
+			continue
+		}
+		splits = append(splits, &pb.SharingSplit{
+			StoreId:      order.StoreId,
+			OrderId:      order.Id,
+			OutTradeNo:   order.OutTradeNo,
+			TradeNo:      order.TradeNo,
+			Channel:      order.Channel,
+			OutOrderNo:   OutOrderNo(order),
+			RuleId:       r.Id,
+			Type:         r.Type,
+			Account:      r.Account,
+			Name:         r.Name,
+			RelationType: r.RelationType,
+			Amount:       amount,
+			Description:  r.Description,
+			Status:       StatusPending,
+			NextAt:       nextAt,
+		})
+	}
+	return splits
+}
+
+// Finish 完结分账任务 使用分账明细记录由后台任务请求 分账金额为0
+func Finish(order *orderPB.Order, nextAt string) *pb.SharingSplit {
+	return &pb.SharingSplit{
+		StoreId:     order.StoreId,
+		OrderId:     order.Id,
+		OutTradeNo:  order.OutTradeNo,
+		TradeNo:     order.TradeNo,
+		Channel:     order.Channel,
+		OutOrderNo:  "PF" + order.OutTradeNo,
+		Type:        TypeFinish,
+		Description: "分账完结",
+		Status:      StatusPending,
+		NextAt:      nextAt,
+	}
+}
+
+// Receivers 分账明细转为通道分账接收方
+func Receivers(splits []*pb.SharingSplit) (receivers []*Receiver, err error) {
+	if len(splits) == 0 {
+		return nil, errors.New("分账接收方不允许为空")
+	}
+	for _, s := range splits {
+		receivers = append(receivers, NewReceiver(s))
+	}
+	return receivers, nil
+}
+
+// NewReceiver 分账明细转为通道分账接收方 分账金额扣除退款已回退的金额
+func NewReceiver(split *pb.SharingSplit) *Receiver {
+	description := split.Description
+	if description == "" {
+		description = "分账"
+	}
+	return &Receiver{
+		Type:         split.Type,
+		Account:      split.Account,
+		Name:         split.Name,
+		RelationType: split.RelationType,
+		Amount:       split.Amount - split.ReturnedAmount,
+		Description:  description,
+	}
+}
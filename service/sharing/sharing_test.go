@@ -0,0 +1,66 @@
+package sharing
+
+import (
+	"testing"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/sharing"
+)
+
+func TestValidate(t *testing.T) {
+	ok := []*pb.SharingRule{
+		{Channel: "wechat", Type: "MERCHANT_ID", Account: "1900000109", RelationType: "SERVICE_PROVIDER", Rate: 2000},
+		{Channel: "wechat", Type: "PERSONAL_OPENID", Account: "oUpF8u", RelationType: "DISTRIBUTOR", Rate: 1000},
+		{Channel: "alipay", Type: "userId", Account: "2088101117955611", Rate: 10000},
+	}
+	if err := Validate(ok); err != nil {
+		t.Fatal(err)
+	}
+	bad := [][]*pb.SharingRule{
+		{{Channel: "icbc", Type: "MERCHANT_ID", Account: "1", Rate: 1}},
+		{{Channel: "wechat", Type: "MERCHANT_ID", Account: "1", Rate: 1}},
+		{{Channel: "alipay", Type: "userId", Rate: 1}},
+		{{Channel: "alipay", Type: "userId", Account: "1", Rate: 0}},
+		{{Channel: "alipay", Type: "userId", Account: "1", Rate: 6000}, {Channel: "alipay", Type: "userId", Account: "2", Rate: 4001}},
+	}
+	for i, rules := range bad {
+		if err := Validate(rules); err == nil {
+			t.Errorf("rules %d: want error", i)
+		}
+	}
+}
+
+func TestSplits(t *testing.T) {
+	rules := []*pb.SharingRule{
+		{Id: 1, Channel: "wechat", Type: "MERCHANT_ID", Account: "1900000109", Rate: 3333},
+		{Id: 2, Channel: "wechat", Type: "PERSONAL_OPENID", Account: "oUpF8u", Rate: 1},
+		{Id: 3, Channel: "alipay", Type: "userId", Account: "2088101117955611", Rate: 1000},
+	}
+	order := &orderPB.Order{Id: "o1", StoreId: "s1", Channel: "wechat", OutTradeNo: "GZ0001", TradeNo: "42000001", TotalFee: 999}
+	splits := Splits(rules, order, "2020-01-01 00:01:00")
+	if len(splits) != 1 {
+		t.Fatalf("want 1 split got %+v", splits)
+	}
+	s := splits[0]
+	if s.Amount != 332 || s.RuleId != 1 || s.OutOrderNo != "PSGZ0001" || s.TradeNo != "42000001" || s.Status != StatusPending {
+		t.Fatalf("split: %+v", s)
+	}
+	if r := NewReceiver(s); r.Amount != 332 || r.Description != "分账" {
+		t.Fatalf("receiver: %+v", r)
+	}
+}
+
+func TestReturnAmount(t *testing.T) {
+	s := &pb.SharingSplit{Amount: 333}
+	// 订单 1000 分三次退款 300、300、400 累计回退等于分账金额
+	for i, c := range []struct{ refundFee, want int64 }{{300, 99}, {600, 100}, {1000, 134}} {
+		amount := ReturnAmount(s, 1000, c.refundFee)
+		if amount != c.want {
+			t.Fatalf("refund %d: want %d got %d", i+1, c.want, amount)
+		}
+		s.ReturnedAmount += amount
+	}
+	if s.ReturnedAmount != s.Amount || ReturnAmount(s, 1000, 1000) != 0 {
+		t.Fatalf("returned: %+v", s)
+	}
+}
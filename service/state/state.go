@@ -0,0 +1,133 @@
+package state
+
+import (
+	"fmt"
+
+	pb "github.com/lecex/pay/proto/order"
+)
+
+// 订单状态 退款订单只会处于 CREATED、SUCCESS、CLOSED
+const (
+	Created           = "CREATED"            // 已创建 待付款|待退款
+	UserPaying        = "USERPAYING"         // 用户支付中
+	Success           = "SUCCESS"            // 付款成功|退款成功
+	Closed            = "CLOSED"             // 订单关闭
+	Refunding         = "REFUNDING"          // 退款中 存在待退款的退款订单
+	PartiallyRefunded = "PARTIALLY_REFUNDED" // 部分退款
+	Refunded          = "REFUNDED"           // 全额退款
+	Reversed          = "REVERSED"           // 已撤销
+)
+
+// 状态变更来源
+const (
+	SourceRPC    = "rpc"    // 接口调用
+	SourceNotify = "notify" // 通道异步通知
+	SourcePoller = "poller" // 轮询查询
+	SourceRepair = "repair" // 补偿任务
+)
+
+// transitions 允许的状态流转 未列出的流转均不允许
+// 通道受理退款后仍可能退款失败 已退款订单在退款关闭后回到部分退款或付款成功
+var transitions = map[string][]string{
+	Created:           {UserPaying, Success, Closed, Reversed},
+	UserPaying:        {Success, Closed, Reversed},
+	Success:           {Refunding, PartiallyRefunded, Refunded, Reversed},
+	Refunding:         {Success, PartiallyRefunded, Refunded},
+	PartiallyRefunded: {Success, Refunding, Refunded},
+	Refunded:          {Success, PartiallyRefunded},
+}
+
+// refundTransitions 退款订单允许的状态流转 退款受理成功后通道通知退款关闭时关闭退款订单
+var refundTransitions = map[string][]string{
+	Created: {Success, Closed},
+	Success: {Closed},
+}
+
+// Current 订单当前状态 未记录状态的历史订单根据订单状态和退款金额推断
+func Current(order *pb.Order) string {
+	if order.State != "" {
+		return order.State
+	}
+	switch order.Status {
+	case -1:
+		return Closed
+	case 1:
+		switch {
+		case order.TotalFee > 0 && order.RefundFee >= order.TotalFee:
+			return Refunded
+		case order.TotalFee > 0 && order.RefundFee > 0:
+			return PartiallyRefunded
+		}
+		return Success
+	}
+	return Created
+}
+
+// Status 状态对应的订单状态 [-1 订单关闭,0 待付款,1 付款成功]
+func Status(state string) int64 {
+	switch state {
+	case Closed, Reversed:
+		return -1
+	case Success, Refunding, PartiallyRefunded, Refunded:
+		return 1
+	}
+	return 0
+}
+
+// Can 是否允许订单从 from 流转到 to
+func Can(from string, to string) bool {
+	return can(transitions, from, to)
+}
+
+// CanRefund 是否允许退款订单从 from 流转到 to
+func CanRefund(from string, to string) bool {
+	return can(refundTransitions, from, to)
+}
+
+func can(transitions map[string][]string, from string, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transit 校验状态流转并更新订单状态 返回的事件为空时状态未变更无需记录
+// 状态相同时视为重复处理直接返回
+func Transit(order *pb.Order, to string, event *pb.OrderEvent) (*pb.OrderEvent, error) {
+	from := Current(order)
+	if from == to {
+		order.State = to
+		order.Status = Status(to)
+		return nil, nil
+	}
+	if (order.TotalFee < 0 && !CanRefund(from, to)) || (order.TotalFee >= 0 && !Can(from, to)) {
+		return nil, fmt.Errorf("订单%s状态不允许从%s变更为%s", order.OutTradeNo, from, to)
+	}
+	order.State = to
+	order.Status = Status(to)
+	if event == nil {
+		event = &pb.OrderEvent{}
+	}
+	e := *event
+	e.OrderId = order.Id
+	e.StoreId = order.StoreId
+	e.OutTradeNo = order.OutTradeNo
+	e.FromState = from
+	e.ToState = to
+	return &e, nil
+}
+
+// Refund 根据已退款金额和待退款金额计算原订单状态
+func Refund(order *pb.Order, refundingFee int64) string {
+	switch {
+	case refundingFee > order.RefundFee:
+		return Refunding
+	case order.RefundFee >= order.TotalFee:
+		return Refunded
+	case order.RefundFee > 0:
+		return PartiallyRefunded
+	}
+	return Success
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"testing"
+
+	pb "github.com/lecex/pay/proto/order"
+)
+
+func TestCurrent(t *testing.T) {
+	cases := []struct {
+		order *pb.Order
+		want  string
+	}{
+		{&pb.Order{Status: 0, TotalFee: 100}, Created},
+		{&pb.Order{Status: -1, TotalFee: 100}, Closed},
+		{&pb.Order{Status: 1, TotalFee: 100}, Success},
+		{&pb.Order{Status: 1, TotalFee: 100, RefundFee: 40}, PartiallyRefunded},
+		{&pb.Order{Status: 1, TotalFee: 100, RefundFee: 100}, Refunded},
+		{&pb.Order{Status: 1, TotalFee: -100}, Success},
+		{&pb.Order{Status: 1, TotalFee: 100, State: Refunding}, Refunding},
+	}
+	for _, c := range cases {
+		if got := Current(c.order); got != c.want {
+			t.Errorf("Current(%+v) = %s, want %s", c.order, got, c.want)
+		}
+	}
+}
+
+func TestTransit(t *testing.T) {
+	order := &pb.Order{Id: "1", StoreId: "s", OutTradeNo: "T1", TotalFee: 100, State: Created}
+	e, err := Transit(order, Success, &pb.OrderEvent{Source: SourceNotify, Actor: "Notify.wechat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.State != Success || order.Status != 1 {
+		t.Fatalf("order = %+v", order)
+	}
+	if e.OrderId != "1" || e.FromState != Created || e.ToState != Success || e.Source != SourceNotify {
+		t.Fatalf("event = %+v", e)
+	}
+	// 重复处理不记录变更
+	if e, err = Transit(order, Success, nil); err != nil || e != nil {
+		t.Fatalf("Transit same state = %+v, %v", e, err)
+	}
+	// 支付成功的订单不允许关闭
+	if _, err = Transit(order, Closed, nil); err == nil {
+		t.Fatal("Transit SUCCESS -> CLOSED should fail")
+	}
+	if order.State != Success {
+		t.Fatalf("state changed after illegal transition: %s", order.State)
+	}
+	if _, err = Transit(order, Reversed, nil); err != nil || order.Status != -1 {
+		t.Fatalf("Transit SUCCESS -> REVERSED: %v %+v", err, order)
+	}
+	if _, err = Transit(order, Success, nil); err == nil {
+		t.Fatal("Transit REVERSED -> SUCCESS should fail")
+	}
+}
+
+func TestTransitRefundOrder(t *testing.T) {
+	refund := &pb.Order{Id: "2", TotalFee: -40, State: Created}
+	if _, err := Transit(refund, Refunding, nil); err == nil {
+		t.Fatal("refund order should not be REFUNDING")
+	}
+	if _, err := Transit(refund, Success, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 通道受理退款后通知退款关闭
+	if _, err := Transit(refund, Closed, nil); err != nil || refund.Status != -1 {
+		t.Fatalf("Transit refund SUCCESS -> CLOSED: %v %+v", err, refund)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	cases := []struct {
+		refundFee, refundingFee int64
+		want                    string
+	}{
+		{0, 0, Success},
+		{0, 40, Refunding},
+		{40, 80, Refunding},
+		{40, 40, PartiallyRefunded},
+		{100, 100, Refunded},
+	}
+	for _, c := range cases {
+		order := &pb.Order{TotalFee: 100, RefundFee: c.refundFee}
+		if got := Refund(order, c.refundingFee); got != c.want {
+			t.Errorf("Refund(%d, %d) = %s, want %s", c.refundFee, c.refundingFee, got, c.want)
+		}
+	}
+}
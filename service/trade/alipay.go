@@ -1,10 +1,19 @@
 package trade
 
 import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
 	"github.com/clbanning/mxj"
 	notifyPB "github.com/lecex/pay/proto/notify"
 	orderPB "github.com/lecex/pay/proto/order"
 	proto "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/sharing"
+	"github.com/lecex/pay/util"
 	"github.com/shopspring/decimal"
 
 	// "github.com/shopspring/decimal"
@@ -16,6 +25,7 @@ import (
 type Alipay struct {
 	Client *alipay.Client
 	config map[string]string
+	keys   rsaKeys
 }
 
 func (srv *Alipay) NewClient(config map[string]string, sandbox bool) {
@@ -56,9 +66,126 @@ func (srv *Alipay) AopF2F(b *proto.BizContent) (req mxj.Map, err error) {
 		"timeout_express": "2m",
 		"extend_params":   map[string]interface{}{"sys_service_provider_id": srv.config["SysServiceProviderId"]},
 	}
+	if srv.config["NotifyUrl"] != "" { // 用户支付中超时后通过异步通知更新订单
+		request.QueryParams = map[string]interface{}{
+			"notify_url": srv.config["NotifyUrl"],
+		}
+	}
 	return srv.request(request)
 }
 
+// Precreate 预下单 用户扫商家收款码
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.precreate
+func (srv *Alipay) Precreate(b *proto.BizContent) (req mxj.Map, err error) {
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "alipay.trade.precreate"
+	request.BizContent = map[string]interface{}{
+		"subject":         b.Title,
+		"out_trade_no":    b.OutTradeNo,
+		"total_amount":    decimal.NewFromFloat(float64(b.TotalFee)).Div(decimal.NewFromFloat(float64(100))),
+		"timeout_express": "10m",
+		"extend_params":   map[string]interface{}{"sys_service_provider_id": srv.config["SysServiceProviderId"]},
+	}
+	if srv.config["NotifyUrl"] != "" {
+		request.QueryParams = map[string]interface{}{
+			"notify_url": srv.config["NotifyUrl"],
+		}
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	return precreateContent("alipay", b, content, "qr_code"), nil
+}
+
+// JsapiPay 小程序支付 创建交易后由前端通过交易号调起支付
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.create
+func (srv *Alipay) JsapiPay(b *proto.BizContent) (req mxj.Map, err error) {
+	if b.BuyerId == "" {
+		return nil, errors.New("支付宝用户id不允许为空:BizContent.BuyerId")
+	}
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "alipay.trade.create"
+	request.BizContent = map[string]interface{}{
+		"subject":         b.Title,
+		"out_trade_no":    b.OutTradeNo,
+		"total_amount":    decimal.NewFromFloat(float64(b.TotalFee)).Div(decimal.NewFromFloat(float64(100))),
+		"buyer_id":        b.BuyerId,
+		"timeout_express": "10m",
+		"extend_params":   map[string]interface{}{"sys_service_provider_id": srv.config["SysServiceProviderId"]},
+	}
+	if srv.config["NotifyUrl"] != "" {
+		request.QueryParams = map[string]interface{}{
+			"notify_url": srv.config["NotifyUrl"],
+		}
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	var payParams map[string]string
+	if tradeNo := rawString(content, "trade_no", "trade_no"); tradeNo != "" {
+		payParams = map[string]string{"tradeNO": tradeNo}
+	}
+	return jsapiContent("alipay", b, content, payParams), nil
+}
+
+// WebPay 手机网站/电脑网站支付 返回签名后的网关跳转地址 由浏览器直接访问
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.wap.pay
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.page.pay
+func (srv *Alipay) WebPay(b *proto.BizContent) (req mxj.Map, err error) {
+	method, productCode := "alipay.trade.wap.pay", "QUICK_WAP_WAY"
+	if strings.ToUpper(b.Scene) == "PAGE" {
+		method, productCode = "alipay.trade.page.pay", "FAST_INSTANT_TRADE_PAY"
+	}
+	biz := map[string]interface{}{
+		"subject":         b.Title,
+		"out_trade_no":    b.OutTradeNo,
+		"total_amount":    decimal.NewFromFloat(float64(b.TotalFee)).Div(decimal.NewFromFloat(float64(100))),
+		"product_code":    productCode,
+		"timeout_express": "10m",
+		"extend_params":   map[string]interface{}{"sys_service_provider_id": srv.config["SysServiceProviderId"]},
+	}
+	if srv.config["ReturnUrl"] != "" {
+		biz["quit_url"] = srv.config["ReturnUrl"] // 手机网站支付中途退出返回商户网站地址
+	}
+	bizContent, err := json.Marshal(biz)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"app_id":         srv.config["AppId"],
+		"method":         method,
+		"format":         "JSON",
+		"charset":        "utf-8",
+		"sign_type":      srv.config["SignType"],
+		"timestamp":      time.Now().In(time.FixedZone("CST", 8*3600)).Format("2006-01-02 15:04:05"),
+		"version":        "1.0",
+		"notify_url":     srv.config["NotifyUrl"],
+		"return_url":     srv.config["ReturnUrl"],
+		"app_auth_token": srv.config["AppAuthToken"],
+		"biz_content":    string(bizContent),
+	}
+	sign, err := srv.keys.sign(srv.config["PrivateKey"], util.SignContent(params), params["sign_type"])
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	for k, v := range params {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	values.Set("sign", sign)
+	gateway := "https://openapi.alipay.com/gateway.do"
+	if srv.Client.Config.Sandbox {
+		gateway = "https://openapi.alipaydev.com/gateway.do"
+	}
+	return webPayContent("alipay", b, mxj.Map{"content": mxj.Map{}}, gateway+"?"+values.Encode()), nil
+}
+
 // Cancel 撤销交易
 //    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.cancel/
 func (srv *Alipay) Cancel(b *proto.BizContent) (req mxj.Map, err error) {
@@ -97,8 +224,104 @@ func (srv *Alipay) RefundQuery(b *proto.BizContent) (req mxj.Map, err error) {
 	return srv.request(request)
 }
 
-// request 请求处理
+// Bill 下载对账单 获取下载地址后下载业务明细压缩包
+//    文档地址：https://opendocs.alipay.com/apis/api_15/alipay.data.dataservice.bill.downloadurl.query
+func (srv *Alipay) Bill(date string) (records []*bill.Record, err error) {
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "alipay.data.dataservice.bill.downloadurl.query"
+	request.BizContent = map[string]interface{}{
+		"bill_type": "trade",
+		"bill_date": date,
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	billUrl := rawString(content, "bill_download_url", "bill_download_url")
+	if billUrl == "" {
+		if rawString(content, "sub_code", "sub_code") == "isp.bill_not_exist" { // 当日无交易
+			return nil, nil
+		}
+		return nil, errors.New("获取对账单下载地址失败:" + rawString(content, "return_msg", "sub_msg"))
+	}
+	data, err := download(billUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bill.ParseAlipayZip(data)
+}
+
+// ProfitSharing 交易结算分账 分账前绑定分账关系 已绑定的接收方重复绑定返回成功
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.royalty.relation.bind
+//    文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.order.settle
+func (srv *Alipay) ProfitSharing(order *orderPB.Order, outOrderNo string, receivers []*sharing.Receiver) (req mxj.Map, err error) {
+	receiverList := []map[string]interface{}{}
+	royaltyParameters := []map[string]interface{}{}
+	for _, r := range receivers {
+		receiverList = append(receiverList, map[string]interface{}{
+			"type":    r.Type,
+			"account": r.Account,
+			"name":    r.Name,
+			"memo":    r.Description,
+		})
+		royaltyParameters = append(royaltyParameters, map[string]interface{}{
+			"royalty_type":  "transfer",
+			"trans_in_type": r.Type,
+			"trans_in":      r.Account,
+			"amount":        decimal.NewFromFloat(float64(r.Amount)).Div(decimal.NewFromFloat(float64(100))),
+			"desc":          r.Description,
+		})
+	}
+	request := requests.NewCommonRequest()
+	request.ApiName = "alipay.trade.royalty.relation.bind"
+	request.BizContent = map[string]interface{}{
+		"out_request_no": outOrderNo + "B",
+		"receiver_list":  receiverList,
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	if ok, msg := alipayResult(content); !ok {
+		return sharingContent("alipay", content, false, "绑定分账关系失败:"+msg), nil
+	}
+	request = requests.NewCommonRequest()
+	request.ApiName = "alipay.trade.order.settle"
+	request.BizContent = map[string]interface{}{
+		"out_request_no":     outOrderNo,
+		"trade_no":           order.TradeNo,
+		"royalty_parameters": royaltyParameters,
+	}
+	content, err = srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	ok, msg := alipayResult(content)
+	return sharingContent("alipay", content, ok, msg), nil
+}
+
+// alipayResult 支付宝接口业务结果 code 10000 为成功
+func alipayResult(content mxj.Map) (bool, string) {
+	if returnCode, _ := content["return_code"].(string); returnCode != "" {
+		return returnCode == "SUCCESS", rawString(content, "return_msg", "sub_msg")
+	}
+	if rawString(content, "code", "code") == "10000" {
+		return true, ""
+	}
+	msg := rawString(content, "sub_msg", "sub_msg")
+	if msg == "" {
+		msg = rawString(content, "msg", "msg")
+	}
+	return false, msg
+}
+
+// request 请求处理 请求完成后记录通道请求
 func (srv *Alipay) request(request *requests.CommonRequest) (req mxj.Map, err error) {
+	start := time.Now()
+	defer func() {
+		record("alipay", srv.config, request.ApiName, requestParams(request.QueryParams, request.BizContent), req, start, err)
+	}()
 	response, err := srv.Client.ProcessCommonRequest(request)
 	if err != nil {
 		return nil, err
@@ -108,14 +331,56 @@ func (srv *Alipay) request(request *requests.CommonRequest) (req mxj.Map, err er
 
 // Notify 异步通知
 //    文档地址：https://opendocs.alipay.com/open/200/106120
-func (srv *Alipay) Notify(req *notifyPB.Request) (ok bool, err error) {
-	// // ====异步通知参数解析和验签Sign====
-	// // 解析异步通知的参数
-	// //    req：*http.Request
-	// //    返回参数 notifyReq：通知的参数
-	// //    返回参数 err：错误信息
-	// notifyReq, err := srv.parseNotifyResult(req) // c.Request()是 echo 框架的获取
-	// // 验签操作
-	// return alipay.VerifySign(srv.config["AliPayPublicKey"], notifyReq)
-	return
+func (srv *Alipay) Notify(req *notifyPB.Request) (content mxj.Map, err error) {
+	params := util.Form(req)
+	sign, signType := params["sign"], params["sign_type"]
+	delete(params, "sign")
+	delete(params, "sign_type")
+	err = srv.keys.verify(srv.config["AliPayPublicKey"], util.SignContent(params), sign, signType)
+	if err != nil {
+		return nil, err
+	}
+	if params["app_id"] != srv.config["AppId"] {
+		return nil, errors.New("通知应用ID与商户配置不符:" + params["app_id"])
+	}
+	raw := mxj.Map{}
+	for k, v := range params {
+		raw[k] = v
+	}
+	totalFee, err := decimal.NewFromString(params["total_amount"])
+	if err != nil {
+		return nil, errors.New("通知订单金额错误:" + params["total_amount"])
+	}
+	content = mxj.Map{
+		"return_code":           "SUCCESS",
+		"return_msg":            params["trade_status"],
+		"channel":               "alipay",
+		"out_trade_no":          params["out_trade_no"],
+		"trade_no":              params["trade_no"],
+		"total_fee":             totalFee.Mul(decimal.NewFromFloat(float64(100))).IntPart(),
+		"time_end":              params["gmt_payment"],
+		"alipay_buyer_logon_id": params["buyer_logon_id"],
+		"alipay_buyer_user_id":  params["buyer_id"],
+		"content":               raw,
+	}
+	switch params["trade_status"] {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		content["status"] = "SUCCESS"
+	case "TRADE_CLOSED":
+		content["status"] = "CLOSED"
+	default:
+		content["status"] = "USERPAYING"
+	}
+	if params["out_biz_no"] != "" { // 退款通知不改变支付订单状态
+		content["status"] = "REFUND"
+	}
+	return content, nil
+}
+
+// NotifyReply 异步通知应答 处理成功返回 success 否则支付宝会重复通知
+func (srv *Alipay) NotifyReply(content mxj.Map, err error) string {
+	if err != nil {
+		return "fail"
+	}
+	return "success"
 }
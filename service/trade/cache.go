@@ -0,0 +1,131 @@
+package trade
+
+import (
+	"crypto/rsa"
+	"sync"
+	"time"
+
+	configPB "github.com/lecex/pay/proto/config"
+	"github.com/lecex/pay/util"
+)
+
+// Cache 商户支付配置和支付通道缓存
+// 读取商户配置需要查询关联表并解密全部秘钥 创建通道需要解析秘钥和证书 有效期内同一商户复用
+// 缓存中的配置即配置版本 清除缓存后重新读取配置并创建通道 通道解析的秘钥随通道一起释放
+// 本实例更新配置时立即清除 其它实例订阅配置变更事件清除 事件丢失时最多使用 TTL 时长的旧配置
+type Cache struct {
+	TTL time.Duration
+
+	mu     sync.Mutex
+	gen    uint64 // 清除次数 读取配置期间清除过缓存时不缓存读取结果
+	stores map[string]*store
+}
+
+// store 商户缓存 配置和由该配置创建的支付通道
+type store struct {
+	config   *configPB.Config
+	expireAt time.Time
+	channels map[string]Channel
+}
+
+// NewCache 创建商户配置缓存 ttl 为缓存有效期
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{TTL: ttl, stores: map[string]*store{}}
+}
+
+// Config 获取商户配置 缓存不存在或已过期时通过 load 读取 读取失败不缓存
+// 返回的配置由缓存共用 调用方不能修改 c 为 nil 时每次读取
+func (c *Cache) Config(storeId string, load func() (*configPB.Config, error)) (*configPB.Config, error) {
+	if c == nil {
+		return load()
+	}
+	c.mu.Lock()
+	s, ok := c.stores[storeId]
+	gen := c.gen
+	c.mu.Unlock()
+	if ok && time.Now().Before(s.expireAt) {
+		return s.config, nil
+	}
+	con, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.stores[storeId] = &store{config: con, expireAt: time.Now().Add(c.TTL), channels: map[string]Channel{}}
+	}
+	return con, nil
+}
+
+// Channel 获取支付通道 con 为缓存中的商户配置时复用已创建的通道 其它配置每次创建
+func (c *Cache) Channel(name string, con *configPB.Config) (Channel, error) {
+	if c == nil {
+		return NewChannel(name, con)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stores[con.Id]
+	if !ok || s.config != con {
+		return NewChannel(name, con)
+	}
+	if channel, ok := s.channels[name]; ok {
+		return channel, nil
+	}
+	channel, err := NewChannel(name, con)
+	if err != nil {
+		return nil, err
+	}
+	s.channels[name] = channel
+	return channel, nil
+}
+
+// Invalidate 清除商户配置和支付通道缓存 商户配置更新或删除后调用
+func (c *Cache) Invalidate(storeId string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	delete(c.stores, storeId)
+}
+
+// rsaKeys 支付通道使用的 RSA 秘钥 首次签名验签时解析 同一通道复用
+type rsaKeys struct {
+	mu      sync.Mutex
+	private *rsa.PrivateKey
+	public  *rsa.PublicKey
+}
+
+// sign 使用通道私钥签名 通道配置不变 私钥只解析一次
+func (k *rsaKeys) sign(privateKey, content, signType string) (string, error) {
+	k.mu.Lock()
+	if k.private == nil {
+		key, err := util.ParsePrivateKey(privateKey)
+		if err != nil {
+			k.mu.Unlock()
+			return "", err
+		}
+		k.private = key
+	}
+	key := k.private
+	k.mu.Unlock()
+	return util.RsaSignKey(key, content, signType)
+}
+
+// verify 使用通道公钥验签 通道配置不变 公钥只解析一次
+func (k *rsaKeys) verify(publicKey, content, sign, signType string) error {
+	k.mu.Lock()
+	if k.public == nil {
+		key, err := util.ParsePublicKey(publicKey)
+		if err != nil {
+			k.mu.Unlock()
+			return err
+		}
+		k.public = key
+	}
+	key := k.public
+	k.mu.Unlock()
+	return util.RsaVerifyKey(key, content, sign, signType)
+}
@@ -0,0 +1,91 @@
+package trade
+
+import (
+	"testing"
+	"time"
+
+	configPB "github.com/lecex/pay/proto/config"
+)
+
+func TestCache(t *testing.T) {
+	created, loaded := 0, 0
+	Register("cache", func(con *configPB.Config) Channel {
+		created++
+		return &Alipay{}
+	})
+	cache := NewCache(time.Minute)
+	load := func(id string) func() (*configPB.Config, error) {
+		return func() (*configPB.Config, error) {
+			loaded++
+			return &configPB.Config{Id: id, Alipay: &configPB.Alipay{PrivateKey: "key-0"}}, nil
+		}
+	}
+	// 有效期内不重复读取配置 同一配置复用支付通道
+	con, _ := cache.Config("store-0", load("store-0"))
+	c1, _ := cache.Channel("cache", con)
+	con2, _ := cache.Config("store-0", load("store-0"))
+	c2, _ := cache.Channel("cache", con2)
+	if loaded != 1 || created != 1 || con != con2 || c1 != c2 {
+		t.Fatalf("loaded = %d, created = %d", loaded, created)
+	}
+	// 不是缓存中的配置每次创建
+	cache.Channel("cache", &configPB.Config{Id: "store-0", Alipay: &configPB.Alipay{PrivateKey: "key-1"}})
+	if created != 2 {
+		t.Fatalf("created = %d for uncached config", created)
+	}
+	// 不同商户不共用
+	other, _ := cache.Config("store-1", load("store-1"))
+	cache.Channel("cache", other)
+	if loaded != 2 || created != 3 {
+		t.Fatalf("loaded = %d, created = %d for another store", loaded, created)
+	}
+	// 清除后重新读取配置并创建通道 其它商户不受影响
+	cache.Invalidate("store-0")
+	con3, _ := cache.Config("store-0", load("store-0"))
+	if c3, _ := cache.Channel("cache", con3); loaded != 3 || created != 4 || c3 == c1 {
+		t.Fatalf("loaded = %d, created = %d after invalidate", loaded, created)
+	}
+	cache.Config("store-1", load("store-1"))
+	if loaded != 3 {
+		t.Fatalf("loaded = %d, store-1 reloaded", loaded)
+	}
+	// 读取期间清除的配置不缓存
+	cache.Invalidate("store-0")
+	cache.Config("store-0", func() (*configPB.Config, error) {
+		cache.Invalidate("store-0")
+		return load("store-0")()
+	})
+	cache.Config("store-0", load("store-0"))
+	if loaded != 5 {
+		t.Fatalf("loaded = %d, stale config cached", loaded)
+	}
+	// 过期后重新读取
+	expired := NewCache(0)
+	expired.Config("store-0", load("store-0"))
+	expired.Config("store-0", load("store-0"))
+	if loaded != 7 {
+		t.Fatalf("loaded = %d after expired", loaded)
+	}
+}
+
+func TestRsaKeys(t *testing.T) {
+	_, cert, pemKey := testPem(t, time.Now().Add(time.Hour))
+	k := &rsaKeys{}
+	sign, err := k.sign(pemKey, "a=1", "RSA2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = k.verify(cert, "a=1", sign, "RSA2"); err != nil {
+		t.Fatal(err)
+	}
+	// 同一通道只解析一次
+	private, public := k.private, k.public
+	k.sign(pemKey, "a=2", "RSA2")
+	k.verify(cert, "a=1", sign, "RSA2")
+	if k.private != private || k.public != public {
+		t.Fatal("key parsed twice")
+	}
+	if _, err = (&rsaKeys{}).sign("bad", "a=1", "RSA2"); err == nil {
+		t.Fatal("want invalid key error")
+	}
+}
@@ -0,0 +1,316 @@
+package trade
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/clbanning/mxj"
+	"github.com/lecex/core/env"
+
+	configPB "github.com/lecex/pay/proto/config"
+	notifyPB "github.com/lecex/pay/proto/notify"
+	orderPB "github.com/lecex/pay/proto/order"
+	proto "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/sharing"
+)
+
+// Channel 支付通道接口 各通道都支持的能力 可选能力通过类型断言判断
+type Channel interface {
+	// AopF2F 商家扫用户付款码
+	AopF2F(b *proto.BizContent) (mxj.Map, error)
+	// Query 支付查询
+	Query(b *proto.BizContent) (mxj.Map, error)
+	// Precreate 预下单 用户扫商家收款码 返回二维码链接 code_url
+	Precreate(b *proto.BizContent) (mxj.Map, error)
+	// Cancel 撤销交易
+	Cancel(b *proto.BizContent) (mxj.Map, error)
+	// Refund 交易退款
+	Refund(refundOrder *orderPB.Order, originalOrder *orderPB.Order) (mxj.Map, error)
+	// RefundQuery 退款查询
+	RefundQuery(b *proto.BizContent) (mxj.Map, error)
+	// Notify 异步通知 验签后返回与查询接口一致的结果
+	Notify(req *notifyPB.Request) (mxj.Map, error)
+	// NotifyReply 异步通知应答内容 err 不为空时应答处理失败等待通道重新通知
+	NotifyReply(content mxj.Map, err error) string
+}
+
+// JsapiPayer 支持公众号/小程序支付的支付通道
+type JsapiPayer interface {
+	// JsapiPay 公众号/小程序支付 返回前端调起支付参数 pay_params
+	JsapiPay(b *proto.BizContent) (mxj.Map, error)
+}
+
+// WebPayer 支持手机网站/电脑网站支付的支付通道
+type WebPayer interface {
+	// WebPay 手机网站/电脑网站支付 返回浏览器跳转地址 pay_url
+	WebPay(b *proto.BizContent) (mxj.Map, error)
+}
+
+// Biller 支持下载对账单的支付通道
+type Biller interface {
+	// Bill 下载对账单 date 格式 2006-01-02
+	Bill(date string) ([]*bill.Record, error)
+}
+
+// ProfitSharer 支持分账的支付通道
+type ProfitSharer interface {
+	// ProfitSharing 分账 同一订单的所有接收方使用同一分账单号一次请求
+	ProfitSharing(order *orderPB.Order, outOrderNo string, receivers []*sharing.Receiver) (mxj.Map, error)
+}
+
+// SharingFinisher 下单时冻结待分账资金的支付通道 没有分账接收方时需要完结分账解冻资金
+type SharingFinisher interface {
+	// ProfitSharingFinish 完结分账 剩余待分账资金解冻给商户
+	ProfitSharingFinish(order *orderPB.Order, outOrderNo string) (mxj.Map, error)
+}
+
+// SharingReturner 支持分账回退的支付通道
+type SharingReturner interface {
+	// ProfitSharingReturn 分账回退 退款前将已分账资金从接收方退回
+	ProfitSharingReturn(outOrderNo string, outReturnNo string, receiver *sharing.Receiver) (mxj.Map, error)
+}
+
+// RefundNotifier 支持退款异步通知的支付通道
+type RefundNotifier interface {
+	// RefundNotify 退款异步通知 解密或验签后返回退款结果
+	// out_trade_no 原订单号 out_refund_no 退款订单号 status SUCCESS 退款成功 CLOSED 退款失败
+	RefundNotify(req *notifyPB.Request) (mxj.Map, error)
+}
+
+// Factory 根据商户配置实例化支付通道
+type Factory func(con *configPB.Config) Channel
+
+var (
+	mu       sync.RWMutex
+	channels = map[string]Factory{}
+	// billClient 对账文件下载
+	billClient = &http.Client{Timeout: time.Minute}
+	// sharingClient 分账请求
+	sharingClient = &http.Client{Timeout: 30 * time.Second}
+)
+
+func init() {
+	Register("alipay", func(con *configPB.Config) Channel {
+		c := &Alipay{}
+		c.NewClient(map[string]string{
+			"StoreId":              con.Id,
+			"AppId":                con.Alipay.AppId,
+			"PrivateKey":           con.Alipay.PrivateKey,
+			"AliPayPublicKey":      con.Alipay.AliPayPublicKey,
+			"AppAuthToken":         con.Alipay.AppAuthToken,
+			"SysServiceProviderId": con.Alipay.SysServiceProviderId,
+			"SignType":             con.Alipay.SignType,
+			"NotifyUrl":            NotifyUrl("alipay", con.Id),
+			"ReturnUrl":            con.ReturnUrl,
+		}, con.Alipay.Sandbox)
+		return c
+	})
+	Register("wechat", func(con *configPB.Config) Channel {
+		c := &Wechat{}
+		c.NewClient(map[string]string{
+			"StoreId":         con.Id,
+			"AppId":           con.Wechat.AppId,
+			"MchId":           con.Wechat.MchId,
+			"ApiKey":          con.Wechat.ApiKey,
+			"SubAppId":        con.Wechat.SubAppId,
+			"SubMchId":        con.Wechat.SubMchId,
+			"PemCert":         con.Wechat.PemCert,
+			"PemKey":          con.Wechat.PemKey,
+			"NotifyUrl":       NotifyUrl("wechat", con.Id),
+			"RefundNotifyUrl": NotifyUrl("wechatRefund", con.Id),
+			"ReturnUrl":       con.ReturnUrl,
+			"ProfitSharing":   profitSharing(con),
+		}, con.Wechat.Sandbox)
+		return c
+	})
+	Register("icbc", func(con *configPB.Config) Channel {
+		c := &Icbc{}
+		c.NewClient(map[string]string{
+			"StoreId":        con.Id,
+			"AppId":          con.Icbc.AppId,
+			"PrivateKey":     con.Icbc.PrivateKey,
+			"IcbcPublicKey":  con.Icbc.IcbcPublicKey,
+			"SignType":       con.Icbc.SignType,
+			"ReturnSignType": con.Icbc.ReturnSignType,
+			"MerId":          con.Icbc.MerId,
+			"NotifyUrl":      NotifyUrl("icbc", con.Id),
+		})
+		return c
+	})
+}
+
+// NotifyUrl 异步通知地址 PAY_NOTIFY_URL 为通知服务的外网地址 未配置时不发送通知地址
+// 例如 https://xxx.com/pay-api/notify 对应支付宝通知 https://xxx.com/pay-api/notify/alipay?store_id=
+func NotifyUrl(name string, storeId string) string {
+	u := env.Getenv("PAY_NOTIFY_URL", "")
+	if u == "" {
+		return ""
+	}
+	return u + "/" + name + "?store_id=" + url.QueryEscape(storeId)
+}
+
+// profitSharing 商户开启分账时微信下单需要标记分账 Y 分账 N 不分账
+func profitSharing(con *configPB.Config) string {
+	if con.ProfitSharing {
+		return "Y"
+	}
+	return "N"
+}
+
+// rawContent 获取通道原始返回内容
+func rawContent(content mxj.Map) mxj.Map {
+	switch v := content["content"].(type) {
+	case mxj.Map:
+		return v
+	case map[string]interface{}:
+		return mxj.Map(v)
+	}
+	return content
+}
+
+// payUnknownCodes 通道返回失败但支付结果未知的错误码 需要查询确认
+var payUnknownCodes = map[string]bool{
+	"SYSTEMERROR":      true, // 微信系统错误
+	"BANKERROR":        true, // 微信银行系统异常
+	"ACQ.SYSTEM_ERROR": true, // 支付宝系统错误
+}
+
+// PayFailed 付款码支付通道明确返回失败 如付款码无效、余额不足 系统错误等结果未知的失败不算
+func PayFailed(content mxj.Map) bool {
+	if content["return_code"] != "FAIL" {
+		return false
+	}
+	raw := rawContent(content)
+	for _, key := range []string{"err_code", "sub_code"} {
+		if code, _ := raw[key].(string); payUnknownCodes[code] {
+			return false
+		}
+	}
+	return true
+}
+
+// RefundStatus 退款查询结果 SUCCESS 退款成功 CLOSED 通道明确退款关闭或退款不存在 其它为处理中或查询失败
+func RefundStatus(content mxj.Map) string {
+	if content["return_code"] == "SUCCESS" {
+		status, _ := content["status"].(string)
+		return status
+	}
+	// 微信退款单不存在 退款请求未被通道受理
+	if code, _ := rawContent(content)["err_code"].(string); code == "REFUNDNOTEXIST" {
+		return "CLOSED"
+	}
+	return ""
+}
+
+// rawString 优先获取统一返回字段 不存在时获取通道原始返回字段
+func rawString(content mxj.Map, key string, rawKey string) string {
+	if v, _ := content[key].(string); v != "" {
+		return v
+	}
+	v, _ := rawContent(content)[rawKey].(string)
+	return v
+}
+
+// result 整理下单返回内容 value 为空时视为下单失败
+func result(name string, b *proto.BizContent, content mxj.Map, key string, value string, msg string) mxj.Map {
+	returnCode, _ := content["return_code"].(string)
+	returnMsg, _ := content["return_msg"].(string)
+	if returnCode == "" || returnCode == "SUCCESS" {
+		returnCode = "SUCCESS"
+		if value == "" {
+			returnCode = "FAIL"
+			returnMsg = msg
+		}
+	}
+	return mxj.Map{
+		"return_code":  returnCode,
+		"return_msg":   returnMsg,
+		"channel":      name,
+		"out_trade_no": b.OutTradeNo,
+		key:            value,
+		"content":      rawContent(content),
+	}
+}
+
+// precreateContent 整理预下单返回内容 codeKey 为通道原始返回内容中的二维码链接字段
+func precreateContent(name string, b *proto.BizContent, content mxj.Map, codeKey string) mxj.Map {
+	return result(name, b, content, "code_url", rawString(content, "code_url", codeKey), "通道未返回二维码链接")
+}
+
+// jsapiContent 整理公众号/小程序支付返回内容 payParams 为前端调起支付参数
+func jsapiContent(name string, b *proto.BizContent, content mxj.Map, payParams map[string]string) mxj.Map {
+	var params string
+	if len(payParams) > 0 {
+		j, _ := json.Marshal(payParams)
+		params = string(j)
+	}
+	return result(name, b, content, "pay_params", params, "通道未返回预支付交易单")
+}
+
+// webPayContent 整理网页支付返回内容
+func webPayContent(name string, b *proto.BizContent, content mxj.Map, payUrl string) mxj.Map {
+	return result(name, b, content, "pay_url", payUrl, "通道未返回支付跳转地址")
+}
+
+// sharingContent 整理分账返回内容 success 为通道业务处理结果
+func sharingContent(name string, content mxj.Map, success bool, msg string) mxj.Map {
+	returnCode := "SUCCESS"
+	if !success {
+		returnCode = "FAIL"
+		if msg == "" {
+			msg = "分账请求失败"
+		}
+	}
+	return mxj.Map{
+		"return_code": returnCode,
+		"return_msg":  msg,
+		"channel":     name,
+		"content":     rawContent(content),
+	}
+}
+
+// download 发送请求并读取对账文件 body 为空时使用 GET 请求
+func download(u string, body io.Reader) ([]byte, error) {
+	method := http.MethodGet
+	if body != nil {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := billClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("下载对账单失败:%s", err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("下载对账单失败:HTTP %d", resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Register 注册支付通道 同名通道会被覆盖 需要在启动时注册 已缓存的支付通道不会重新创建
+func Register(name string, factory Factory) {
+	mu.Lock()
+	channels[name] = factory
+	mu.Unlock()
+}
+
+// NewChannel 根据通道名称和商户配置创建支付通道 需要复用时通过 Cache 获取
+func NewChannel(name string, con *configPB.Config) (Channel, error) {
+	mu.RLock()
+	factory, ok := channels[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("不支持的支付通道:%s", name)
+	}
+	return factory(con), nil
+}
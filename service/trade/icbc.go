@@ -1,13 +1,21 @@
 package trade
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/clbanning/mxj"
 	notifyPB "github.com/lecex/pay/proto/notify"
 	orderPB "github.com/lecex/pay/proto/order"
 	proto "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/util"
 
 	// "github.com/shopspring/decimal"
 
@@ -18,6 +26,7 @@ import (
 type Icbc struct {
 	Client *icbc.Client
 	config map[string]string
+	keys   rsaKeys
 }
 
 func (srv *Icbc) NewClient(config map[string]string) {
@@ -60,6 +69,45 @@ func (srv *Icbc) AopF2F(b *proto.BizContent) (req mxj.Map, err error) {
 	return srv.request(request)
 }
 
+// Precreate 预下单 生成收款二维码
+//    文档地址：https://open.icbc.com.cn/icbc/apip/api_detail.html?apiId=10000000000000009001
+func (srv *Icbc) Precreate(b *proto.BizContent) (req mxj.Map, err error) {
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "qrcode.generate"
+	request.BizContent = map[string]interface{}{
+		"mer_id":            srv.config["MerId"],
+		"out_trade_no":      b.OutTradeNo,
+		"order_amt":         strconv.FormatInt(b.TotalFee, 10),
+		"trade_date":        time.Now().Format("20060102"),
+		"trade_time":        time.Now().Format("150405"),
+		"attach":            b.Title,
+		"pay_expire":        "600",
+		"notify_url":        srv.config["NotifyUrl"],
+		"tporder_create_ip": "127.0.0.1",
+		"sp_flag":           "0",
+		"notify_flag":       "1",
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	return precreateContent("icbc", b, content, "qrcode"), nil
+}
+
+// Cancel 撤销交易(冲正)
+//    文档地址：https://open.icbc.com.cn/icbc/apip/api_detail.html?apiId=10000000000000010010
+func (srv *Icbc) Cancel(b *proto.BizContent) (req mxj.Map, err error) {
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "pay.reverse"
+	request.BizContent = map[string]interface{}{
+		"mer_id":       srv.config["MerId"],
+		"out_trade_no": b.OutTradeNo,
+	}
+	return srv.request(request)
+}
+
 // Refund 交易退款
 //    文档地址：https://opendocs.icbc.com/apis/api_1/icbc.trade.refund/
 func (srv *Icbc) Refund(refundOrder *orderPB.Order, originalOrder *orderPB.Order) (req mxj.Map, err error) {
@@ -88,8 +136,36 @@ func (srv *Icbc) RefundQuery(b *proto.BizContent) (req mxj.Map, err error) {
 	return srv.request(request)
 }
 
-// request 请求处理
+// Bill 下载对账单 获取对账文件下载地址后下载 csv 文件
+func (srv *Icbc) Bill(date string) (records []*bill.Record, err error) {
+	// 配置参数
+	request := requests.NewCommonRequest()
+	request.ApiName = "bill.download"
+	request.BizContent = map[string]interface{}{
+		"mer_id":    srv.config["MerId"],
+		"bill_date": strings.Replace(date, "-", "", -1),
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	billUrl := rawString(content, "download_url", "download_url")
+	if billUrl == "" {
+		return nil, errors.New("获取对账单下载地址失败:" + rawString(content, "return_msg", "return_msg"))
+	}
+	data, err := download(billUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return bill.ParseIcbc(bytes.NewReader(data))
+}
+
+// request 请求处理 请求完成后记录通道请求
 func (srv *Icbc) request(request *requests.CommonRequest) (req mxj.Map, err error) {
+	start := time.Now()
+	defer func() {
+		record("icbc", srv.config, request.ApiName, requestParams(request.QueryParams, request.BizContent), req, start, err)
+	}()
 	response, err := srv.Client.ProcessCommonRequest(request)
 	if err != nil {
 		return req, err
@@ -98,14 +174,78 @@ func (srv *Icbc) request(request *requests.CommonRequest) (req mxj.Map, err erro
 }
 
 // Notify 异步通知
-func (srv *Icbc) Notify(req *notifyPB.Request) (ok bool, err error) {
-	// // ====异步通知参数解析和验签Sign====
-	// // 解析异步通知的参数
-	// //    req：*http.Request
-	// //    返回参数 notifyReq：通知的参数
-	// //    返回参数 err：错误信息
-	// notifyReq, err := srv.parseNotifyResult(req) // c.Request()是 echo 框架的获取
-	// // 验签操作
-	// return icbc.VerifySign(srv.config["icbcPublicKey"], notifyReq)
-	return
+//    文档地址：https://open.icbc.com.cn/icbc/apip/api_detail.html?apiId=10000000000000052474
+func (srv *Icbc) Notify(req *notifyPB.Request) (content mxj.Map, err error) {
+	params := util.Form(req)
+	sign := params["sign"]
+	delete(params, "sign")
+	signType := params["sign_type"]
+	if signType == "" {
+		signType = srv.config["ReturnSignType"]
+	}
+	// 工行签名内容为通知地址路径加上排序后的参数
+	path := req.Path
+	if u, err := url.Parse(srv.config["NotifyUrl"]); err == nil && u.Path != "" {
+		path = u.Path
+	}
+	err = srv.keys.verify(srv.config["IcbcPublicKey"], path+"?"+util.SignContent(params), sign, signType)
+	if err != nil {
+		return nil, err
+	}
+	biz, err := mxj.NewMapJson([]byte(params["biz_content"]))
+	if err != nil {
+		return nil, errors.New("通知内容格式错误:" + err.Error())
+	}
+	get := func(key string) string {
+		if v, ok := biz[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	if get("mer_id") != srv.config["MerId"] {
+		return nil, errors.New("通知商户ID与商户配置不符:" + get("mer_id"))
+	}
+	totalFee, err := strconv.ParseInt(get("total_amt"), 10, 64)
+	if err != nil {
+		return nil, errors.New("通知订单金额错误:" + get("total_amt"))
+	}
+	content = mxj.Map{
+		"return_code":  "SUCCESS",
+		"return_msg":   get("return_msg"),
+		"channel":      "icbc",
+		"out_trade_no": get("out_trade_no"),
+		"trade_no":     get("order_id"),
+		"total_fee":    totalFee,
+		"time_end":     get("pay_time"),
+		"msg_id":       get("msg_id"),
+		"content":      biz,
+	}
+	if get("return_code") == "0" {
+		content["status"] = "SUCCESS"
+	} else {
+		content["status"] = "USERPAYING"
+	}
+	return content, nil
+}
+
+// NotifyReply 异步通知应答 应答内容需要使用商户私钥签名
+//    文档地址：https://open.icbc.com.cn/icbc/apip/docs_sign.html
+func (srv *Icbc) NotifyReply(content mxj.Map, err error) string {
+	reply := struct {
+		ReturnCode int    `json:"return_code"`
+		ReturnMsg  string `json:"return_msg"`
+		MsgId      string `json:"msg_id"`
+	}{0, "success", ""}
+	if err != nil {
+		reply.ReturnCode, reply.ReturnMsg = -1, err.Error()
+	}
+	reply.MsgId, _ = content["msg_id"].(string)
+	biz, _ := json.Marshal(reply)
+	signType := srv.config["SignType"]
+	signStr := `"response_biz_content":` + string(biz) + `,"sign_type":"` + signType + `"`
+	sign, e := srv.keys.sign(srv.config["PrivateKey"], signStr, signType)
+	if e != nil {
+		return "{" + signStr + "}"
+	}
+	return "{" + signStr + `,"sign":"` + sign + `"}`
 }
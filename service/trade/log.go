@@ -0,0 +1,146 @@
+package trade
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/clbanning/mxj"
+
+	orderPB "github.com/lecex/pay/proto/order"
+)
+
+// Recorder 通道请求记录 每次通道请求完成后调用
+type Recorder func(log *orderPB.ChannelLog)
+
+var recorder Recorder
+
+// SetRecorder 设置通道请求记录 服务启动时设置 未设置时不记录
+func SetRecorder(r Recorder) {
+	mu.Lock()
+	defer mu.Unlock()
+	recorder = r
+}
+
+// secretKeys 秘钥类字段 记录时全部隐藏
+var secretKeys = map[string]bool{
+	"sign":           true,
+	"key":            true,
+	"api_key":        true,
+	"app_secret":     true,
+	"private_key":    true,
+	"app_auth_token": true,
+	"pem_cert":       true,
+	"pem_key":        true,
+	"password":       true,
+}
+
+// authCodeKeys 付款码字段 记录时只保留后四位
+var authCodeKeys = map[string]bool{
+	"auth_code": true,
+	"qr_code":   true,
+}
+
+// Redact 脱敏 返回副本 不修改原请求内容
+func Redact(v interface{}) interface{} {
+	switch m := v.(type) {
+	case mxj.Map:
+		return mxj.Map(redactMap(m))
+	case map[string]interface{}:
+		return redactMap(m)
+	case map[string]string:
+		r := map[string]interface{}{}
+		for k, v := range m {
+			r[k] = v
+		}
+		return redactMap(r)
+	case []interface{}:
+		r := make([]interface{}, len(m))
+		for i, v := range m {
+			r[i] = Redact(v)
+		}
+		return r
+	}
+	return v
+}
+
+func redactMap(m map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		key := strings.ToLower(k)
+		switch {
+		case secretKeys[key]:
+			r[k] = "******"
+		case authCodeKeys[key]:
+			r[k] = mask(v)
+		default:
+			r[k] = Redact(v)
+		}
+	}
+	return r
+}
+
+// mask 只保留后四位
+func mask(v interface{}) string {
+	s, _ := v.(string)
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// record 记录通道请求 请求内容脱敏后保存 记录失败不影响支付
+func record(channel string, config map[string]string, apiName string, params map[string]interface{}, response mxj.Map, start time.Time, err error) {
+	mu.RLock()
+	r := recorder
+	mu.RUnlock()
+	if r == nil {
+		return
+	}
+	l := &orderPB.ChannelLog{
+		StoreId:    config["StoreId"],
+		OutTradeNo: outTradeNo(params),
+		Channel:    channel,
+		ApiName:    apiName,
+		Latency:    int64(time.Since(start) / time.Millisecond),
+	}
+	if b, e := json.Marshal(Redact(params)); e == nil {
+		l.Request = string(b)
+	}
+	if response != nil {
+		if b, e := json.Marshal(Redact(response)); e == nil {
+			l.Response = string(b)
+		}
+	}
+	if err != nil {
+		l.Error = err.Error()
+	}
+	r(l)
+}
+
+// outTradeNo 请求内容中的商户订单号 用于关联订单
+func outTradeNo(params map[string]interface{}) string {
+	if v, ok := params["out_trade_no"].(string); ok {
+		return v
+	}
+	for _, v := range params {
+		if m, ok := v.(map[string]interface{}); ok {
+			if s, ok := m["out_trade_no"].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// requestParams 合并通道公共参数和业务参数
+func requestParams(queryParams map[string]interface{}, bizContent map[string]interface{}) map[string]interface{} {
+	params := map[string]interface{}{}
+	for k, v := range queryParams {
+		params[k] = v
+	}
+	if len(bizContent) > 0 {
+		params["biz_content"] = bizContent
+	}
+	return params
+}
@@ -0,0 +1,48 @@
+package trade
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/clbanning/mxj"
+
+	orderPB "github.com/lecex/pay/proto/order"
+)
+
+func TestRecord(t *testing.T) {
+	var logs []*orderPB.ChannelLog
+	SetRecorder(func(l *orderPB.ChannelLog) { logs = append(logs, l) })
+	defer SetRecorder(nil)
+
+	params := requestParams(map[string]interface{}{
+		"app_auth_token": "token-123",
+	}, map[string]interface{}{
+		"out_trade_no": "T0001",
+		"auth_code":    "284567890123456789",
+		"total_amount": "1.00",
+	})
+	response := mxj.Map{"trade_no": "2020", "sign": "abc"}
+	record("alipay", map[string]string{"StoreId": "store-0"}, "alipay.trade.pay", params, response, time.Now(), errors.New("timeout"))
+
+	if len(logs) != 1 {
+		t.Fatalf("logs = %d", len(logs))
+	}
+	l := logs[0]
+	if l.StoreId != "store-0" || l.OutTradeNo != "T0001" || l.Channel != "alipay" || l.ApiName != "alipay.trade.pay" || l.Error != "timeout" {
+		t.Fatalf("log = %+v", l)
+	}
+	for _, secret := range []string{"token-123", "284567890123456789", "abc"} {
+		if strings.Contains(l.Request+l.Response, secret) {
+			t.Errorf("log contains %s: %s %s", secret, l.Request, l.Response)
+		}
+	}
+	if !strings.Contains(l.Request, "**************6789") || !strings.Contains(l.Response, "2020") {
+		t.Errorf("log = %s %s", l.Request, l.Response)
+	}
+	// 脱敏不修改原请求内容
+	if params["biz_content"].(map[string]interface{})["auth_code"] != "284567890123456789" {
+		t.Error("Redact modified request params")
+	}
+}
@@ -0,0 +1,163 @@
+package trade
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"testing"
+
+	notifyPB "github.com/lecex/pay/proto/notify"
+	"github.com/lecex/pay/util"
+)
+
+func TestAlipayNotify(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	private := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key))
+	c := &Alipay{config: map[string]string{
+		"AppId":           "2014072300007148",
+		"AliPayPublicKey": base64.StdEncoding.EncodeToString(pub),
+	}}
+	params := map[string]string{
+		"app_id":         "2014072300007148",
+		"trade_status":   "TRADE_SUCCESS",
+		"out_trade_no":   "GZ2020010117534314525",
+		"trade_no":       "2013112011001004330000121536",
+		"total_amount":   "1.80",
+		"buyer_logon_id": "158****1562",
+	}
+	sign, err := util.RsaSign(private, util.SignContent(params), "RSA2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	form := url.Values{"sign": {sign}, "sign_type": {"RSA2"}}
+	for k, v := range params {
+		form.Set(k, v)
+	}
+	content, err := c.Notify(&notifyPB.Request{Body: form.Encode()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content["status"] != "SUCCESS" || content["total_fee"] != int64(180) {
+		t.Fatalf("unexpected content %v", content)
+	}
+	form.Set("total_amount", "18.00")
+	if _, err = c.Notify(&notifyPB.Request{Body: form.Encode()}); err == nil {
+		t.Fatal("tampered notify passed verification")
+	}
+}
+
+func TestWechatNotify(t *testing.T) {
+	c := &Wechat{config: map[string]string{"ApiKey": "192006250b4c09247ec02edce69f6a2d"}}
+	params := map[string]string{
+		"return_code":    "SUCCESS",
+		"result_code":    "SUCCESS",
+		"out_trade_no":   "GZ2020010117534314525",
+		"transaction_id": "1217752501201407033233368018",
+		"total_fee":      "180",
+		"openid":         "oUpF8uN95-Pteags6E_roPHg7AG",
+	}
+	body := "<xml>"
+	for k, v := range params {
+		body += "<" + k + "><![CDATA[" + v + "]]></" + k + ">"
+	}
+	sign := Sign(params, c.config["ApiKey"], "")
+	content, err := c.Notify(&notifyPB.Request{Body: body + "<sign>" + sign + "</sign></xml>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content["status"] != "SUCCESS" || content["total_fee"] != int64(180) {
+		t.Fatalf("unexpected content %v", content)
+	}
+	if _, err = c.Notify(&notifyPB.Request{Body: body + "<sign>BAD</sign></xml>"}); err == nil {
+		t.Fatal("bad sign passed verification")
+	}
+}
+
+// encryptReqInfo 模拟微信加密退款通知 AES-256-ECB PKCS7 填充
+func encryptReqInfo(plain string, apiKey string) string {
+	sum := md5.Sum([]byte(apiKey))
+	block, _ := aes.NewCipher([]byte(hex.EncodeToString(sum[:])))
+	padding := block.BlockSize() - len(plain)%block.BlockSize()
+	data := append([]byte(plain), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	for i := 0; i < len(data); i += block.BlockSize() {
+		block.Encrypt(data[i:i+block.BlockSize()], data[i:i+block.BlockSize()])
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+func TestWechatRefundNotify(t *testing.T) {
+	c := &Wechat{config: map[string]string{"ApiKey": "192006250b4c09247ec02edce69f6a2d"}}
+	info := "<root><out_refund_no><![CDATA[GZ2020010117534314525_R]]></out_refund_no>" +
+		"<out_trade_no><![CDATA[GZ2020010117534314525]]></out_trade_no>" +
+		"<refund_id><![CDATA[50000408942018111907145868882]]></refund_id>" +
+		"<refund_fee><![CDATA[80]]></refund_fee>" +
+		"<refund_status><![CDATA[SUCCESS]]></refund_status>" +
+		"<success_time><![CDATA[2020-01-01 12:00:00]]></success_time></root>"
+	body := "<xml><return_code>SUCCESS</return_code><req_info><![CDATA[" + encryptReqInfo(info, c.config["ApiKey"]) + "]]></req_info></xml>"
+	content, err := c.RefundNotify(&notifyPB.Request{Body: body})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content["status"] != "SUCCESS" || content["refund_fee"] != int64(80) || content["out_refund_no"] != "GZ2020010117534314525_R" {
+		t.Fatalf("unexpected content %v", content)
+	}
+	body = "<xml><return_code>SUCCESS</return_code><req_info><![CDATA[" + encryptReqInfo(info, "wrong-key") + "]]></req_info></xml>"
+	if _, err = c.RefundNotify(&notifyPB.Request{Body: body}); err == nil {
+		t.Fatal("decrypted with wrong key")
+	}
+}
+
+func TestIcbcNotify(t *testing.T) {
+	icbcKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	merKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	icbcPub, _ := x509.MarshalPKIXPublicKey(&icbcKey.PublicKey)
+	merPub, _ := x509.MarshalPKIXPublicKey(&merKey.PublicKey)
+	c := &Icbc{config: map[string]string{
+		"MerId":         "020002040095",
+		"PrivateKey":    base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(merKey)),
+		"IcbcPublicKey": base64.StdEncoding.EncodeToString(icbcPub),
+		"SignType":      "RSA2",
+		"NotifyUrl":     "https://xxx.com/pay-api/notify/icbc?store_id=1",
+	}}
+	params := map[string]string{
+		"from":        "icbc-api",
+		"api":         "/api/qrcode/V2/pay",
+		"app_id":      "10000000000000005446",
+		"charset":     "UTF-8",
+		"format":      "json",
+		"sign_type":   "RSA",
+		"timestamp":   "2020-01-01 17:53:43",
+		"biz_content": `{"return_code":"0","return_msg":"success","msg_id":"0800000123","mer_id":"020002040095","out_trade_no":"GZ2020010117534314525","order_id":"020002040095000510000123","total_amt":"180","pay_time":"20200101175343"}`,
+	}
+	sign, _ := util.RsaSign(base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(icbcKey)), "/pay-api/notify/icbc?"+util.SignContent(params), "RSA")
+	form := url.Values{"sign": {sign}}
+	for k, v := range params {
+		form.Set(k, v)
+	}
+	content, err := c.Notify(&notifyPB.Request{Body: form.Encode()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content["status"] != "SUCCESS" || content["total_fee"] != int64(180) || content["trade_no"] != "020002040095000510000123" {
+		t.Fatalf("unexpected content %v", content)
+	}
+	reply := c.NotifyReply(content, nil)
+	i := strings.Index(reply, `,"sign":"`)
+	if i < 0 {
+		t.Fatalf("reply not signed %s", reply)
+	}
+	if err = util.RsaVerify(base64.StdEncoding.EncodeToString(merPub), reply[1:i], strings.TrimSuffix(reply[i+9:], `"}`), "RSA2"); err != nil {
+		t.Fatalf("reply sign: %v %s", err, reply)
+	}
+	if !strings.Contains(reply, `"msg_id":"0800000123"`) {
+		t.Fatalf("reply missing msg_id %s", reply)
+	}
+}
@@ -0,0 +1,66 @@
+package trade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	// WechatOauthAuthorize 微信网页授权地址
+	WechatOauthAuthorize = "https://open.weixin.qq.com/connect/oauth2/authorize"
+	// WechatOauthToken 微信网页授权 code 换取 openid 地址
+	WechatOauthToken = "https://api.weixin.qq.com/sns/oauth2/access_token"
+	// oauthClient 微信网页授权请求
+	oauthClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// WechatOauthUrl 微信静默网页授权跳转地址 用户同意授权后携带 code 跳转回 redirect
+//
+//	文档地址：https://developers.weixin.qq.com/doc/offiaccount/OA_Web_Apps/Wechat_webpage_authorization.html
+func WechatOauthUrl(appId string, redirect string) string {
+	v := url.Values{}
+	v.Set("appid", appId)
+	v.Set("redirect_uri", redirect)
+	v.Set("response_type", "code")
+	v.Set("scope", "snsapi_base")
+	v.Set("state", "cashier")
+	return WechatOauthAuthorize + "?" + v.Encode() + "#wechat_redirect"
+}
+
+// WechatOpenId 通过网页授权 code 获取用户 openid code 只能使用一次 五分钟未使用自动过期
+func WechatOpenId(appId string, appSecret string, code string) (string, error) {
+	v := url.Values{}
+	v.Set("appid", appId)
+	v.Set("secret", appSecret)
+	v.Set("code", code)
+	v.Set("grant_type", "authorization_code")
+	resp, err := oauthClient.Get(WechatOauthToken + "?" + v.Encode())
+	if err != nil {
+		return "", fmt.Errorf("微信网页授权请求失败:%s", err.Error())
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("微信网页授权请求失败:%s", err.Error())
+	}
+	res := struct {
+		OpenId  string `json:"openid"`
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}{}
+	if err = json.Unmarshal(body, &res); err != nil {
+		return "", fmt.Errorf("微信网页授权返回格式错误:%s", err.Error())
+	}
+	if res.ErrCode != 0 {
+		return "", fmt.Errorf("微信网页授权失败:%d %s", res.ErrCode, res.ErrMsg)
+	}
+	if res.OpenId == "" {
+		return "", errors.New("微信网页授权未返回openid")
+	}
+	return res.OpenId, nil
+}
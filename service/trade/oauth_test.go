@@ -0,0 +1,34 @@
+package trade
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWechatOpenId(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("code") != "CODE" {
+			w.Write([]byte(`{"errcode":40029,"errmsg":"invalid code"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"TOKEN","expires_in":7200,"openid":"oUpF8uN95-Pteags6E_roPHg7AG","scope":"snsapi_base"}`))
+	}))
+	defer srv.Close()
+	old := WechatOauthToken
+	WechatOauthToken = srv.URL
+	defer func() { WechatOauthToken = old }()
+
+	openId, err := WechatOpenId("wx123", "secret", "CODE")
+	if err != nil || openId != "oUpF8uN95-Pteags6E_roPHg7AG" {
+		t.Fatalf("openid: %s %v", openId, err)
+	}
+	if _, err = WechatOpenId("wx123", "secret", "BAD"); err == nil || !strings.Contains(err.Error(), "40029") {
+		t.Fatalf("invalid code: %v", err)
+	}
+	u := WechatOauthUrl("wx123", "https://pay.example.com/cashier/index?store_id=s1")
+	if !strings.HasSuffix(u, "#wechat_redirect") || !strings.Contains(u, "redirect_uri=https%3A%2F%2Fpay.example.com%2Fcashier%2Findex%3Fstore_id%3Ds1") {
+		t.Fatalf("oauth url: %s", u)
+	}
+}
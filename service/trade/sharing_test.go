@@ -0,0 +1,50 @@
+package trade
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	orderPB "github.com/lecex/pay/proto/order"
+	"github.com/lecex/pay/service/sharing"
+)
+
+func TestWechatProfitSharing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<xml><return_code>SUCCESS</return_code><result_code>SUCCESS</result_code></xml>"))
+	}))
+	defer server.Close()
+	addReceiverUrl, profitSharingUrl := wechatAddReceiverUrl, wechatProfitSharingUrl
+	wechatAddReceiverUrl, wechatProfitSharingUrl = server.URL, server.URL
+	defer func() { wechatAddReceiverUrl, wechatProfitSharingUrl = addReceiverUrl, profitSharingUrl }()
+
+	var logs []*orderPB.ChannelLog
+	SetRecorder(func(l *orderPB.ChannelLog) { logs = append(logs, l) })
+	defer SetRecorder(nil)
+
+	_, cert, pemKey := testPem(t, time.Now().Add(time.Hour))
+	c := &Wechat{}
+	c.NewClient(map[string]string{"StoreId": "store-0", "ApiKey": "192006250b4c09247ec02edce69f6a2d", "PemCert": cert, "PemKey": pemKey}, false)
+	order := &orderPB.Order{TradeNo: "4200000001"}
+	receivers := []*sharing.Receiver{{Type: "MERCHANT_ID", Account: "190001", Amount: 10}}
+	for i := 0; i < 2; i++ {
+		if _, err := c.ProfitSharing(order, "PS0001", receivers); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := c.certClient
+	if _, err := c.cert(); err != nil || client == nil || c.certClient != client {
+		t.Fatal("cert client not reused", err)
+	}
+	// 添加接收方和请求分账都记录通道请求
+	want := []string{"pay.profitsharingaddreceiver", "pay.profitsharing", "pay.profitsharingaddreceiver", "pay.profitsharing"}
+	if len(logs) != len(want) {
+		t.Fatalf("want %d logs got %d", len(want), len(logs))
+	}
+	for i, l := range logs {
+		if l.ApiName != want[i] || l.StoreId != "store-0" || l.Channel != "wechat" || l.Response == "" {
+			t.Errorf("log %d = %+v", i, l)
+		}
+	}
+}
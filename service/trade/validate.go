@@ -0,0 +1,128 @@
+package trade
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	configPB "github.com/lecex/pay/proto/config"
+	"github.com/lecex/pay/service/webhook"
+	"github.com/lecex/pay/util"
+)
+
+// signTypes 允许的 RSA 签名方式 RSA 为 SHA1WithRSA RSA2 为 SHA256WithRSA
+var signTypes = map[string]bool{"RSA": true, "RSA2": true}
+
+// sample 试签名内容
+const sample = "app_id=dry-run&biz_content={}&method=dry.run&timestamp=2006-01-02 15:04:05"
+
+// Validate 校验商户配置 解析秘钥并使用私钥试签名 返回每个字段的错误 未配置的支付通道不校验
+func Validate(con *configPB.Config) (errs []*configPB.FieldError) {
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, &configPB.FieldError{Field: field, Message: err.Error()})
+		}
+	}
+	if con.NotifyUrl != "" { // 商户通知需要秘钥签名
+		add("notify_url", webhook.CheckUrl(con.NotifyUrl))
+		add("notify_secret", required(con.NotifySecret))
+	}
+	if c := con.Alipay; c != nil && (c.AppId != "" || c.PrivateKey != "") {
+		add("alipay.app_id", required(c.AppId))
+		add("alipay.sign_type", signType(c.SignType))
+		add("alipay.private_key", dryRun(c.PrivateKey, c.SignType))
+		add("alipay.ali_pay_public_key", publicKey(c.AliPayPublicKey))
+	}
+	if c := con.Wechat; c != nil && (c.MchId != "" || c.ApiKey != "") {
+		add("wechat.app_id", required(c.AppId))
+		add("wechat.mch_id", required(c.MchId))
+		add("wechat.api_key", apiKey(c.ApiKey))
+		if c.PemCert != "" || c.PemKey != "" {
+			add("wechat.pem_cert", required(c.PemCert))
+			add("wechat.pem_key", required(c.PemKey))
+			if c.PemCert != "" && c.PemKey != "" {
+				add("wechat.pem_key", keyPair(c.PemCert, c.PemKey))
+			}
+		}
+	}
+	if c := con.Icbc; c != nil && (c.AppId != "" || c.PrivateKey != "") {
+		add("icbc.app_id", required(c.AppId))
+		add("icbc.mer_id", required(c.MerId))
+		add("icbc.sign_type", signType(c.SignType))
+		add("icbc.return_sign_type", signType(c.ReturnSignType))
+		add("icbc.private_key", dryRun(c.PrivateKey, c.SignType))
+		add("icbc.icbc_public_key", publicKey(c.IcbcPublicKey))
+	}
+	return errs
+}
+
+func required(value string) error {
+	if value == "" {
+		return errors.New("不能为空")
+	}
+	return nil
+}
+
+func signType(value string) error {
+	if !signTypes[value] {
+		return errors.New("签名方式只支持 RSA、RSA2")
+	}
+	return nil
+}
+
+// dryRun 解析私钥并试签名 使用私钥对应的公钥验签
+func dryRun(privateKey string, signType string) error {
+	if privateKey == "" {
+		return errors.New("不能为空")
+	}
+	key, err := util.ParsePrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	sign, err := util.RsaSign(privateKey, sample, signType)
+	if err != nil {
+		return errors.New("试签名失败:" + err.Error())
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	if err = util.RsaVerify(base64.StdEncoding.EncodeToString(der), sample, sign, signType); err != nil {
+		return errors.New("试签名验签失败:" + err.Error())
+	}
+	return nil
+}
+
+func publicKey(key string) error {
+	if key == "" {
+		return errors.New("不能为空")
+	}
+	_, err := util.ParsePublicKey(key)
+	return err
+}
+
+// apiKey 微信 API 秘钥为 32 位字符串
+func apiKey(key string) error {
+	if len(key) != 32 {
+		return errors.New("API 秘钥长度必须为 32 位")
+	}
+	return nil
+}
+
+// keyPair 校验证书与私钥匹配且证书在有效期内
+func keyPair(cert string, key string) error {
+	pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return errors.New("证书与私钥不匹配:" + err.Error())
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return errors.New("证书格式错误:" + err.Error())
+	}
+	if now := time.Now(); now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return errors.New("证书不在有效期内")
+	}
+	return nil
+}
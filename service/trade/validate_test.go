@@ -0,0 +1,105 @@
+package trade
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	configPB "github.com/lecex/pay/proto/config"
+)
+
+// testPem 自签名证书和私钥 PEM 格式
+func testPem(t *testing.T, notAfter time.Time) (*rsa.PrivateKey, string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mch-0"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, string(cert), string(pemKey)
+}
+
+func fieldErrors(errs []*configPB.FieldError) map[string]string {
+	fields := map[string]string{}
+	for _, e := range errs {
+		fields[e.Field] = e.Message
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	key, cert, pemKey := testPem(t, time.Now().Add(time.Hour))
+	_, otherCert, _ := testPem(t, time.Now().Add(time.Hour))
+	_, expiredCert, expiredKey := testPem(t, time.Now().Add(-time.Minute))
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	privateKey := base64.StdEncoding.EncodeToString(der)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey := base64.StdEncoding.EncodeToString(pub)
+
+	valid := &configPB.Config{
+		Alipay: &configPB.Alipay{AppId: "app-0", PrivateKey: privateKey, AliPayPublicKey: publicKey, SignType: "RSA2"},
+		Wechat: &configPB.Wechat{AppId: "wx-0", MchId: "mch-0", ApiKey: strings.Repeat("k", 32), PemCert: cert, PemKey: pemKey},
+		Icbc:   &configPB.Icbc{AppId: "icbc-0", MerId: "mer-0", PrivateKey: pemKey, IcbcPublicKey: cert, SignType: "RSA", ReturnSignType: "RSA2"},
+	}
+	valid.NotifyUrl, valid.NotifySecret = "https://shop.example.com/notify", "secret"
+	if errs := Validate(valid); len(errs) != 0 {
+		t.Fatalf("Validate(valid) = %v", errs)
+	}
+	// 未配置的支付通道不校验
+	if errs := Validate(&configPB.Config{Alipay: &configPB.Alipay{}, Wechat: &configPB.Wechat{}}); len(errs) != 0 {
+		t.Fatalf("Validate(empty) = %v", errs)
+	}
+
+	for name, c := range map[string]struct {
+		config *configPB.Config
+		fields []string
+	}{
+		"alipay": {&configPB.Config{
+			Alipay: &configPB.Alipay{AppId: "app-0", PrivateKey: privateKey[:40], AliPayPublicKey: "bad", SignType: "rsa2"},
+		}, []string{"alipay.private_key", "alipay.ali_pay_public_key", "alipay.sign_type"}},
+		"wechat mismatch": {&configPB.Config{
+			Wechat: &configPB.Wechat{AppId: "wx-0", MchId: "mch-0", ApiKey: "short", PemCert: otherCert, PemKey: pemKey},
+		}, []string{"wechat.api_key", "wechat.pem_key"}},
+		"wechat expired": {&configPB.Config{
+			Wechat: &configPB.Wechat{AppId: "wx-0", MchId: "mch-0", ApiKey: strings.Repeat("k", 32), PemCert: expiredCert, PemKey: expiredKey},
+		}, []string{"wechat.pem_key"}},
+		"wechat missing key": {&configPB.Config{
+			Wechat: &configPB.Wechat{MchId: "mch-0", ApiKey: strings.Repeat("k", 32), PemCert: cert},
+		}, []string{"wechat.app_id", "wechat.pem_key"}},
+		"notify":           {&configPB.Config{NotifyUrl: "http://127.0.0.1:8080/notify"}, []string{"notify_url", "notify_secret"}},
+		"notify scheme":    {&configPB.Config{NotifyUrl: "file:///etc/passwd", NotifySecret: "secret"}, []string{"notify_url"}},
+		"notify private":   {&configPB.Config{NotifyUrl: "https://192.168.1.10/notify", NotifySecret: "secret"}, []string{"notify_url"}},
+		"notify localhost": {&configPB.Config{NotifyUrl: "http://localhost/notify", NotifySecret: "secret"}, []string{"notify_url"}},
+		"icbc": {&configPB.Config{
+			Icbc: &configPB.Icbc{AppId: "icbc-0", PrivateKey: pemKey, IcbcPublicKey: cert, SignType: "CA", ReturnSignType: ""},
+		}, []string{"icbc.mer_id", "icbc.sign_type", "icbc.return_sign_type"}},
+	} {
+		fields := fieldErrors(Validate(c.config))
+		if len(fields) != len(c.fields) {
+			t.Errorf("%s: errors = %v", name, fields)
+			continue
+		}
+		for _, f := range c.fields {
+			if fields[f] == "" {
+				t.Errorf("%s: missing %s in %v", name, f, fields)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package trade
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	proto "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/util"
+)
+
+func TestAlipayWebPay(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	c := &Alipay{}
+	c.NewClient(map[string]string{
+		"AppId":      "2014072300007148",
+		"PrivateKey": base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key)),
+		"SignType":   "RSA2",
+		"ReturnUrl":  "https://shop.example.com/paid",
+	}, false)
+	for scene, method := range map[string]string{"": "alipay.trade.wap.pay", "PAGE": "alipay.trade.page.pay"} {
+		content, err := c.WebPay(&proto.BizContent{
+			Title:      "网页支付测试",
+			OutTradeNo: "GZ2020010117534314525",
+			TotalFee:   180,
+			Scene:      scene,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payUrl := content["pay_url"].(string)
+		if content["return_code"] != "SUCCESS" || !strings.HasPrefix(payUrl, "https://openapi.alipay.com/gateway.do?") {
+			t.Fatalf("unexpected content %v", content)
+		}
+		u, _ := url.Parse(payUrl)
+		values := u.Query()
+		if values.Get("method") != method || values.Get("return_url") != "https://shop.example.com/paid" {
+			t.Fatalf("unexpected params %v", values)
+		}
+		params := map[string]string{}
+		for k := range values {
+			params[k] = values.Get(k)
+		}
+		sign := params["sign"]
+		delete(params, "sign")
+		pubKey := base64.StdEncoding.EncodeToString(pub)
+		if err = util.RsaVerify(pubKey, util.SignContent(params), sign, "RSA2"); err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+	}
+}
@@ -1,11 +1,32 @@
 package trade
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	notifyPB "github.com/lecex/pay/proto/notify"
 	orderPB "github.com/lecex/pay/proto/order"
 	proto "github.com/lecex/pay/proto/trade"
+	"github.com/lecex/pay/service/bill"
+	"github.com/lecex/pay/service/sharing"
+	"github.com/lecex/pay/util"
 
 	"github.com/bigrocs/wechat"
 	"github.com/bigrocs/wechat/requests"
@@ -13,9 +34,21 @@ import (
 	"github.com/clbanning/mxj"
 )
 
+// 微信分账接口地址
+var (
+	wechatAddReceiverUrl         = "https://api.mch.weixin.qq.com/pay/profitsharingaddreceiver"
+	wechatProfitSharingUrl       = "https://api.mch.weixin.qq.com/secapi/pay/profitsharing"
+	wechatProfitSharingReturnUrl = "https://api.mch.weixin.qq.com/secapi/pay/profitsharingreturn"
+	wechatProfitSharingFinishUrl = "https://api.mch.weixin.qq.com/secapi/pay/profitsharingfinish"
+)
+
 type Wechat struct {
 	Client *wechat.Client
 	config map[string]string
+
+	certOnce   sync.Once
+	certClient *http.Client // 使用商户证书的分账请求 首次使用时创建 通道缓存期间复用连接
+	certErr    error
 }
 
 // NewClient 创建新的连接
@@ -61,11 +94,125 @@ func (srv *Wechat) AopF2F(b *proto.BizContent) (req mxj.Map, err error) {
 		"time_start":       time.Now().Format("20060102150405"),                      // 当前时间
 		"time_expire":      time.Now().Add(time.Minute * 2).Format("20060102150405"), // 二分钟后结束
 	}
+	srv.profitSharing(request)
 	// 请求
 	return srv.request(request)
 
 }
 
+// Precreate 预下单 用户扫商家收款码
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/native.php?chapter=9_1
+func (srv *Wechat) Precreate(b *proto.BizContent) (req mxj.Map, err error) {
+	request := requests.NewCommonRequest()
+	request.Domain = "mch"
+	request.ApiName = "pay.unifiedorder"
+	request.QueryParams = map[string]interface{}{
+		"body":             b.Title,
+		"out_trade_no":     b.OutTradeNo,
+		"total_fee":        strconv.FormatInt(b.TotalFee, 10),
+		"spbill_create_ip": "127.0.0.1",
+		"trade_type":       "NATIVE",
+		"product_id":       b.OutTradeNo,
+		"notify_url":       srv.config["NotifyUrl"],
+		"time_start":       time.Now().Format("20060102150405"),
+		"time_expire":      time.Now().Add(time.Minute * 10).Format("20060102150405"), // 十分钟后结束
+	}
+	srv.profitSharing(request)
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	return precreateContent("wechat", b, content, "code_url"), nil
+}
+
+// JsapiPay 公众号/小程序支付 返回 wx.requestPayment 参数
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/jsapi.php?chapter=9_1
+func (srv *Wechat) JsapiPay(b *proto.BizContent) (req mxj.Map, err error) {
+	if b.OpenId == "" && b.SubOpenId == "" {
+		return nil, errors.New("微信用户openid不允许为空:BizContent.OpenId")
+	}
+	request := requests.NewCommonRequest()
+	request.Domain = "mch"
+	request.ApiName = "pay.unifiedorder"
+	request.QueryParams = map[string]interface{}{
+		"body":             b.Title,
+		"out_trade_no":     b.OutTradeNo,
+		"total_fee":        strconv.FormatInt(b.TotalFee, 10),
+		"spbill_create_ip": "127.0.0.1",
+		"trade_type":       "JSAPI",
+		"notify_url":       srv.config["NotifyUrl"],
+		"time_start":       time.Now().Format("20060102150405"),
+		"time_expire":      time.Now().Add(time.Minute * 10).Format("20060102150405"), // 十分钟后结束
+	}
+	srv.profitSharing(request)
+	// 子商户用户标识需要使用子商户应用ID调起支付
+	appId := srv.config["AppId"]
+	if b.SubOpenId != "" {
+		request.QueryParams["sub_openid"] = b.SubOpenId
+		appId = srv.config["SubAppId"]
+	} else {
+		request.QueryParams["openid"] = b.OpenId
+	}
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	var payParams map[string]string
+	if prepayId := rawString(content, "prepay_id", "prepay_id"); prepayId != "" {
+		payParams = map[string]string{
+			"appId":     appId,
+			"timeStamp": strconv.FormatInt(time.Now().Unix(), 10),
+			"nonceStr":  util.NonceStr(),
+			"package":   "prepay_id=" + prepayId,
+			"signType":  "MD5",
+		}
+		payParams["paySign"] = Sign(payParams, srv.config["ApiKey"], "MD5")
+	}
+	return jsapiContent("wechat", b, content, payParams), nil
+}
+
+// WebPay 手机网站支付(H5) 微信不支持电脑网站支付
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/H5.php?chapter=9_20&index=1
+func (srv *Wechat) WebPay(b *proto.BizContent) (req mxj.Map, err error) {
+	if strings.ToUpper(b.Scene) == "PAGE" {
+		return nil, errors.New("微信通道不支持电脑网站支付,请使用预下单接口")
+	}
+	if b.ClientIp == "" {
+		return nil, errors.New("用户终端IP不允许为空:BizContent.ClientIp")
+	}
+	sceneInfo, _ := json.Marshal(map[string]interface{}{
+		"h5_info": map[string]string{
+			"type":     "Wap",
+			"wap_url":  srv.config["ReturnUrl"],
+			"wap_name": b.Title,
+		},
+	})
+	request := requests.NewCommonRequest()
+	request.Domain = "mch"
+	request.ApiName = "pay.unifiedorder"
+	request.QueryParams = map[string]interface{}{
+		"body":             b.Title,
+		"out_trade_no":     b.OutTradeNo,
+		"total_fee":        strconv.FormatInt(b.TotalFee, 10),
+		"spbill_create_ip": b.ClientIp, // H5 支付要求为用户真实IP
+		"trade_type":       "MWEB",
+		"scene_info":       string(sceneInfo),
+		"notify_url":       srv.config["NotifyUrl"],
+		"time_start":       time.Now().Format("20060102150405"),
+		"time_expire":      time.Now().Add(time.Minute * 10).Format("20060102150405"), // 十分钟后结束
+	}
+	srv.profitSharing(request)
+	content, err := srv.request(request)
+	if err != nil {
+		return nil, err
+	}
+	payUrl := rawString(content, "mweb_url", "mweb_url")
+	if payUrl != "" && srv.config["ReturnUrl"] != "" {
+		payUrl += "&redirect_url=" + url.QueryEscape(srv.config["ReturnUrl"])
+	}
+	return webPayContent("wechat", b, content, payUrl), nil
+}
+
 // Cancel 撤销交易
 //    文档地址：https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_11&index=3
 func (srv *Wechat) Cancel(b *proto.BizContent) (req mxj.Map, err error) {
@@ -90,6 +237,9 @@ func (srv *Wechat) Refund(refundOrder *orderPB.Order, originalOrder *orderPB.Ord
 		"total_fee":     strconv.FormatInt(originalOrder.TotalFee, 10),
 		"refund_fee":    strconv.FormatInt(-refundOrder.TotalFee, 10),
 	}
+	if srv.config["RefundNotifyUrl"] != "" { // 退款处理中时通过退款异步通知更新退款订单
+		request.QueryParams["notify_url"] = srv.config["RefundNotifyUrl"]
+	}
 	return srv.request(request)
 }
 
@@ -106,9 +256,220 @@ func (srv *Wechat) RefundQuery(b *proto.BizContent) (req mxj.Map, err error) {
 	return srv.request(request)
 }
 
-// request 请求处理
+// Bill 下载对账单 对账单接口直接返回文件内容 SDK 无法解析 需要单独请求
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_6&index=8
+func (srv *Wechat) Bill(date string) (records []*bill.Record, err error) {
+	params := map[string]string{
+		"appid":      srv.config["AppId"],
+		"mch_id":     srv.config["MchId"],
+		"sub_appid":  srv.config["SubAppId"],
+		"sub_mch_id": srv.config["SubMchId"],
+		"nonce_str":  util.NonceStr(),
+		"bill_date":  strings.Replace(date, "-", "", -1),
+		"bill_type":  "ALL",
+		"tar_type":   "GZIP",
+	}
+	params["sign"] = Sign(params, srv.config["ApiKey"], "MD5")
+	data, err := download("https://api.mch.weixin.qq.com/pay/downloadbill", strings.NewReader(xmlBody(params)))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<xml>")) { // 下载失败返回 xml 错误信息
+		rsp, err := srv.ParseNotifyResult(string(data))
+		if err != nil {
+			return nil, err
+		}
+		msg := fmt.Sprint(rsp["return_msg"])
+		if msg == "No Bill Exist" { // 当日无交易
+			return nil, nil
+		}
+		return nil, errors.New("下载对账单失败:" + msg)
+	}
+	return bill.ParseWechat(data)
+}
+
+// ProfitSharing 请求单次分账 分账前添加分账接收方 分账完成后剩余资金解冻给商户
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/allocation_sl.php?chapter=25_1&index=1
+func (srv *Wechat) ProfitSharing(order *orderPB.Order, outOrderNo string, receivers []*sharing.Receiver) (req mxj.Map, err error) {
+	list := []map[string]interface{}{}
+	for _, r := range receivers {
+		receiver, _ := json.Marshal(map[string]string{
+			"type":          r.Type,
+			"account":       r.Account,
+			"name":          r.Name,
+			"relation_type": r.RelationType,
+		})
+		// 添加分账接收方 已添加的接收方重复添加返回成功
+		content, err := srv.post("pay.profitsharingaddreceiver", wechatAddReceiverUrl, map[string]string{"receiver": string(receiver)}, false)
+		if err != nil {
+			return nil, err
+		}
+		if ok, msg := wechatResult(content); !ok {
+			return sharingContent("wechat", content, false, "添加分账接收方失败:"+msg), nil
+		}
+		list = append(list, map[string]interface{}{
+			"type":        r.Type,
+			"account":     r.Account,
+			"amount":      r.Amount,
+			"description": r.Description,
+		})
+	}
+	j, _ := json.Marshal(list)
+	content, err := srv.post("pay.profitsharing", wechatProfitSharingUrl, map[string]string{
+		"transaction_id": order.TradeNo,
+		"out_order_no":   outOrderNo,
+		"receivers":      string(j),
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	ok, msg := wechatResult(content)
+	return sharingContent("wechat", content, ok, msg), nil
+}
+
+// ProfitSharingFinish 完结分账 下单标记分账但不需要分账的订单 剩余资金解冻给商户
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/allocation_sl.php?chapter=25_5&index=6
+func (srv *Wechat) ProfitSharingFinish(order *orderPB.Order, outOrderNo string) (req mxj.Map, err error) {
+	content, err := srv.post("pay.profitsharingfinish", wechatProfitSharingFinishUrl, map[string]string{
+		"transaction_id": order.TradeNo,
+		"out_order_no":   outOrderNo,
+		"amount":         "0",
+		"description":    "分账完结",
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	ok, msg := wechatResult(content)
+	return sharingContent("wechat", content, ok, msg), nil
+}
+
+// ProfitSharingReturn 分账回退 仅支持从商户号接收方回退
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/allocation_sl.php?chapter=25_7&index=7
+func (srv *Wechat) ProfitSharingReturn(outOrderNo string, outReturnNo string, receiver *sharing.Receiver) (req mxj.Map, err error) {
+	if receiver.Type != "MERCHANT_ID" {
+		return nil, errors.New("微信仅支持从商户号接收方回退分账:" + receiver.Account)
+	}
+	content, err := srv.post("pay.profitsharingreturn", wechatProfitSharingReturnUrl, map[string]string{
+		"out_order_no":        outOrderNo,
+		"out_return_no":       outReturnNo,
+		"return_account_type": receiver.Type,
+		"return_account":      receiver.Account,
+		"return_amount":       strconv.FormatInt(receiver.Amount, 10),
+		"description":         "退款回退分账",
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	ok, msg := wechatResult(content)
+	if result, _ := rawContent(content)["result"].(string); ok && result == "FAILED" {
+		ok, msg = false, fmt.Sprint(rawContent(content)["fail_reason"])
+	}
+	return sharingContent("wechat", content, ok, msg), nil
+}
+
+// post 请求分账接口 分账接口仅支持 HMAC-SHA256 签名 cert 为 true 时使用商户证书
+func (srv *Wechat) post(apiName string, u string, params map[string]string, cert bool) (content mxj.Map, err error) {
+	params["appid"] = srv.config["AppId"]
+	params["mch_id"] = srv.config["MchId"]
+	params["sub_appid"] = srv.config["SubAppId"]
+	params["sub_mch_id"] = srv.config["SubMchId"]
+	params["nonce_str"] = util.NonceStr()
+	params["sign_type"] = "HMAC-SHA256"
+	params["sign"] = Sign(params, srv.config["ApiKey"], "HMAC-SHA256")
+	start := time.Now()
+	defer func() {
+		logParams := map[string]interface{}{}
+		for k, v := range params {
+			logParams[k] = v
+		}
+		record("wechat", srv.config, apiName, logParams, content, start, err)
+	}()
+	client := sharingClient
+	if cert {
+		if client, err = srv.cert(); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := client.Post(u, "text/xml", strings.NewReader(xmlBody(params)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := srv.ParseNotifyResult(string(body))
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for k, v := range rsp {
+		result[k] = fmt.Sprint(v)
+	}
+	if sign := result["sign"]; sign != "" {
+		delete(result, "sign")
+		if Sign(result, srv.config["ApiKey"], "HMAC-SHA256") != sign {
+			return nil, errors.New("分账返回验签失败")
+		}
+	}
+	return mxj.Map{"content": mxj.Map(rsp)}, nil
+}
+
+// cert 使用商户证书的请求客户端 同一通道只创建一次
+func (srv *Wechat) cert() (*http.Client, error) {
+	srv.certOnce.Do(func() {
+		pair, err := tls.X509KeyPair([]byte(srv.config["PemCert"]), []byte(srv.config["PemKey"]))
+		if err != nil {
+			srv.certErr = errors.New("微信商户证书格式错误:" + err.Error())
+			return
+		}
+		srv.certClient = &http.Client{
+			Timeout:   sharingClient.Timeout,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{Certificates: []tls.Certificate{pair}}},
+		}
+	})
+	return srv.certClient, srv.certErr
+}
+
+// wechatResult 微信接口业务结果
+func wechatResult(content mxj.Map) (bool, string) {
+	raw := rawContent(content)
+	if raw["return_code"] != "SUCCESS" {
+		return false, fmt.Sprint(raw["return_msg"])
+	}
+	if raw["result_code"] != "SUCCESS" {
+		return false, fmt.Sprint(raw["err_code_des"])
+	}
+	return true, ""
+}
+
+// xmlBody 请求参数转为 xml 空值不发送
+func xmlBody(params map[string]string) string {
+	body := strings.Builder{}
+	body.WriteString("<xml>")
+	for k, v := range params {
+		if v != "" {
+			body.WriteString("<" + k + "><![CDATA[" + v + "]]></" + k + ">")
+		}
+	}
+	body.WriteString("</xml>")
+	return body.String()
+}
+
+// profitSharing 商户开启分账时下单标记分账 支付成功后资金冻结等待分账
+func (srv *Wechat) profitSharing(request *requests.CommonRequest) {
+	if srv.config["ProfitSharing"] == "Y" {
+		request.QueryParams["profit_sharing"] = "Y"
+	}
+}
+
+// request 请求处理 请求完成后记录通道请求
 func (srv *Wechat) request(request *requests.CommonRequest) (req mxj.Map, err error) {
-	// 请求
+	start := time.Now()
+	defer func() {
+		record("wechat", srv.config, request.ApiName, requestParams(request.QueryParams, request.BizContent), req, start, err)
+	}()
 	response, err := srv.Client.ProcessCommonRequest(request)
 	if err != nil {
 		return req, err
@@ -117,17 +478,164 @@ func (srv *Wechat) request(request *requests.CommonRequest) (req mxj.Map, err er
 }
 
 // Notify 异步通知
-func (srv *Wechat) Notify(body string) (ok bool, err error) {
-	// notifyReq, err := srv.ParseNotifyResult(body)
-	// if err != nil {
-	// 	return ok, err
-	// }
-	// return util.VerifySign(notifyReq, srv.config["ApiKey"], util.SignType_MD5), err
-	return
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/native.php?chapter=9_7&index=8
+func (srv *Wechat) Notify(req *notifyPB.Request) (content mxj.Map, err error) {
+	rsp, err := srv.ParseNotifyResult(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{}
+	for k, v := range rsp {
+		params[k] = fmt.Sprint(v)
+	}
+	if params["return_code"] != "SUCCESS" {
+		return nil, errors.New("通知通信失败:" + params["return_msg"])
+	}
+	sign := params["sign"]
+	delete(params, "sign")
+	if sign == "" || Sign(params, srv.config["ApiKey"], params["sign_type"]) != sign {
+		return nil, errors.New("验签失败")
+	}
+	totalFee, err := strconv.ParseInt(params["total_fee"], 10, 64)
+	if err != nil {
+		return nil, errors.New("通知订单金额错误:" + params["total_fee"])
+	}
+	content = mxj.Map{
+		"return_code":         "SUCCESS",
+		"return_msg":          params["result_code"],
+		"channel":             "wechat",
+		"out_trade_no":        params["out_trade_no"],
+		"trade_no":            params["transaction_id"],
+		"total_fee":           totalFee,
+		"time_end":            params["time_end"],
+		"wechat_open_id":      params["openid"],
+		"wechat_is_subscribe": params["is_subscribe"],
+		"content":             mxj.Map(rsp),
+	}
+	if params["result_code"] == "SUCCESS" {
+		content["status"] = "SUCCESS"
+	} else {
+		content["status"] = "USERPAYING"
+	}
+	return content, nil
+}
+
+// RefundNotify 退款异步通知 req_info 使用商户秘钥 MD5 值作为秘钥 AES-256-ECB 加密
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=9_16&index=10
+func (srv *Wechat) RefundNotify(req *notifyPB.Request) (content mxj.Map, err error) {
+	rsp, err := srv.ParseNotifyResult(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if fmt.Sprint(rsp["return_code"]) != "SUCCESS" {
+		return nil, errors.New("通知通信失败:" + fmt.Sprint(rsp["return_msg"]))
+	}
+	reqInfo, _ := rsp["req_info"].(string)
+	plain, err := DecryptReqInfo(reqInfo, srv.config["ApiKey"])
+	if err != nil {
+		return nil, err
+	}
+	mv, err := mxj.NewMapXml(plain)
+	if err != nil {
+		return nil, errors.New("退款通知内容格式错误:" + err.Error())
+	}
+	info, ok := mv["root"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("退款通知内容格式错误")
+	}
+	params := map[string]string{}
+	for k, v := range info {
+		params[k] = fmt.Sprint(v)
+	}
+	refundFee, err := strconv.ParseInt(params["refund_fee"], 10, 64)
+	if err != nil {
+		return nil, errors.New("通知退款金额错误:" + params["refund_fee"])
+	}
+	content = mxj.Map{
+		"return_code":   "SUCCESS",
+		"return_msg":    params["refund_status"],
+		"channel":       "wechat",
+		"out_trade_no":  params["out_trade_no"],
+		"out_refund_no": params["out_refund_no"],
+		"trade_no":      params["refund_id"],
+		"refund_fee":    refundFee,
+		"time_end":      params["success_time"],
+		"content":       mxj.Map(info),
+	}
+	switch params["refund_status"] {
+	case "SUCCESS":
+		content["status"] = "SUCCESS"
+	case "REFUNDCLOSE", "CHANGE": // 退款关闭 退款异常需要在商户平台手动处理
+		content["status"] = "CLOSED"
+	default:
+		content["status"] = "USERPAYING"
+	}
+	return content, nil
+}
+
+// DecryptReqInfo 解密退款通知 req_info base64 解码后使用 MD5(ApiKey) 小写值作为秘钥 AES-256-ECB 解密
+func DecryptReqInfo(reqInfo string, apiKey string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(reqInfo)
+	if err != nil {
+		return nil, errors.New("退款通知内容解码失败:" + err.Error())
+	}
+	sum := md5.Sum([]byte(apiKey))
+	block, err := aes.NewCipher([]byte(hex.EncodeToString(sum[:])))
+	if err != nil {
+		return nil, err
+	}
+	size := block.BlockSize()
+	if len(data) == 0 || len(data)%size != 0 {
+		return nil, errors.New("退款通知内容长度错误")
+	}
+	plain := make([]byte, len(data))
+	for i := 0; i < len(data); i += size {
+		block.Decrypt(plain[i:i+size], data[i:i+size])
+	}
+	// PKCS7 填充
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > size {
+		return nil, errors.New("退款通知解密失败")
+	}
+	for _, b := range plain[len(plain)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("退款通知解密失败")
+		}
+	}
+	return plain[:len(plain)-padding], nil
+}
+
+// NotifyReply 异步通知应答 处理成功返回 SUCCESS 否则微信会重复通知
+func (srv *Wechat) NotifyReply(content mxj.Map, err error) string {
+	code, msg := "SUCCESS", "OK"
+	if err != nil {
+		code, msg = "FAIL", err.Error()
+	}
+	return "<xml><return_code><![CDATA[" + code + "]]></return_code><return_msg><![CDATA[" + msg + "]]></return_msg></xml>"
 }
 
 // ParseNotifyResult 解析异步通知
 func (srv *Wechat) ParseNotifyResult(body string) (rsp map[string]interface{}, err error) {
 	mv, err := mxj.NewMapXml([]byte(body))
-	return mv["xml"].(map[string]interface{}), err
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := mv["xml"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("通知内容格式错误")
+	}
+	return rsp, err
+}
+
+// Sign 微信签名 signType 为 HMAC-SHA256 时使用 HMAC-SHA256 其它为 MD5
+//    文档地址：https://pay.weixin.qq.com/wiki/doc/api/micropay.php?chapter=4_3
+func Sign(params map[string]string, apiKey string, signType string) string {
+	var h hash.Hash
+	if signType == "HMAC-SHA256" {
+		h = hmac.New(sha256.New, []byte(apiKey))
+	} else {
+		h = md5.New()
+	}
+	h.Write([]byte(util.SignContent(params) + "&key=" + apiKey))
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
 }
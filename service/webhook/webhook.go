@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	configPB "github.com/lecex/pay/proto/config"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/webhook"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 通知事件类型
+const (
+	EventSuccess  = "order.success"  // 支付成功
+	EventClosed   = "order.closed"   // 订单关闭
+	EventRefunded = "order.refunded" // 退款成功
+)
+
+// Order 推送给商户的订单内容
+type Order struct {
+	Id         string `json:"id"`
+	Channel    string `json:"channel"`
+	OutTradeNo string `json:"out_trade_no"`
+	TradeNo    string `json:"trade_no"`
+	TotalFee   int64  `json:"total_fee"`
+	RefundFee  int64  `json:"refund_fee"`
+	Fee        int64  `json:"fee"`
+	Status     int64  `json:"status"`
+	LinkId     string `json:"link_id"`
+	Attach     string `json:"attach"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// errPrivateUrl 通知地址为本机或内网地址
+var errPrivateUrl = errors.New("通知地址不能为本机或内网地址")
+
+// privateNets 本机、内网、链路本地等非公网地址段
+var privateNets = func() (nets []*net.IPNet) {
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+		"172.16.0.0/12", "192.168.0.0/16", "::1/128", "fc00::/7", "fe80::/10",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// PublicIP 是否为公网地址
+func PublicIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range privateNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckUrl 校验商户通知地址 只允许 http、https 且不能为本机或内网地址 避免请求伪造访问内网
+// 域名在推送连接时校验解析后的地址
+func CheckUrl(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("通知地址必须为 http 或 https 地址")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errPrivateUrl
+	}
+	if ip := net.ParseIP(host); ip != nil && !PublicIP(ip) {
+		return errPrivateUrl
+	}
+	return nil
+}
+
+// Payload 推送内容
+type Payload struct {
+	Event   string `json:"event"`
+	StoreId string `json:"store_id"`
+	Order   Order  `json:"order"`
+}
+
+// NewWebhook 根据订单状态变更生成商户通知 商户未配置通知地址时返回 nil 未配置通知秘钥时不生成通知
+func NewWebhook(con *configPB.Config, event string, order *orderPB.Order) (*pb.Webhook, error) {
+	if con.NotifyUrl == "" {
+		return nil, nil
+	}
+	if con.NotifySecret == "" {
+		return nil, errors.New("商户未配置通知秘钥 不推送通知")
+	}
+	payload, err := json.Marshal(Payload{
+		Event:   event,
+		StoreId: con.Id,
+		Order: Order{
+			Id:         order.Id,
+			Channel:    order.Channel,
+			OutTradeNo: order.OutTradeNo,
+			TradeNo:    order.TradeNo,
+			TotalFee:   order.TotalFee,
+			RefundFee:  order.RefundFee,
+			Fee:        order.Fee,
+			Status:     order.Status,
+			LinkId:     order.LinkId,
+			Attach:     order.Attach,
+			CreatedAt:  order.CreatedAt,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Webhook{
+		StoreId: con.Id,
+		OrderId: order.Id,
+		Event:   event,
+		Url:     con.NotifyUrl,
+		Payload: string(payload),
+		NextAt:  worker.Format(now()),
+	}, nil
+}
+
+// Sign 通知签名 HMAC-SHA256(secret, timestamp + "." + payload) 十六进制小写
+func Sign(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 商户校验通知签名
+func Verify(secret, timestamp, payload, sign string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(sign))
+}
@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lecex/core/env"
+	"github.com/micro/go-micro/v2/util/log"
+
+	configPB "github.com/lecex/pay/proto/config"
+	pb "github.com/lecex/pay/proto/webhook"
+	"github.com/lecex/pay/service/repository"
+	"github.com/lecex/pay/service/worker"
+)
+
+// 通知推送状态
+const (
+	StatusFailed  = -1 // 推送失败 已达最大重试次数
+	StatusPending = 0  // 待推送
+	StatusSuccess = 1  // 推送成功
+)
+
+// now 当前时间 测试时可替换
+var now = time.Now
+
+// Worker 商户通知推送 失败后按指数退避重试
+type Worker struct {
+	Repo        repository.Webhook
+	Config      repository.Config
+	Client      *http.Client
+	Interval    time.Duration // 扫描待推送通知间隔
+	Backoff     time.Duration // 首次重试间隔 之后每次翻倍
+	MaxBackoff  time.Duration // 最大重试间隔
+	MaxAttempts int64         // 最大推送次数
+	Limit       int64         // 每次扫描最多推送数量
+	Concurrency int           // 同时推送数量
+}
+
+// NewWorker 根据环境变量创建通知推送
+func NewWorker(repo repository.Webhook, config repository.Config) *Worker {
+	return &Worker{
+		Repo:        repo,
+		Config:      config,
+		Client:      &http.Client{Timeout: envDuration("PAY_WEBHOOK_TIMEOUT", 10), Transport: publicTransport()},
+		Interval:    envDuration("PAY_WEBHOOK_INTERVAL", 5),
+		Backoff:     envDuration("PAY_WEBHOOK_BACKOFF", 15),
+		MaxBackoff:  envDuration("PAY_WEBHOOK_MAX_BACKOFF", 3600),
+		MaxAttempts: int64(envInt("PAY_WEBHOOK_MAX_ATTEMPTS", 10)),
+		Limit:       100,
+		Concurrency: 10,
+	}
+}
+
+// envInt 获取整型环境变量
+func envInt(key string, fallback int) int {
+	if i, err := strconv.Atoi(env.Getenv(key, "")); err == nil && i > 0 {
+		return i
+	}
+	return fallback
+}
+
+// envDuration 获取以秒为单位的时间环境变量
+func envDuration(key string, fallback int) time.Duration {
+	return time.Duration(envInt(key, fallback)) * time.Second
+}
+
+// Run 定时推送到期通知 stop 关闭后退出
+func (w *Worker) Run(stop <-chan struct{}) {
+	worker.Run(w.Interval, stop, w.Scan)
+}
+
+// Scan 推送一批到期通知 抢占期间其它实例不会重复推送 推送中断时到期后会重新推送
+func (w *Worker) Scan() {
+	queue := &worker.Queue{
+		Lease:       w.Client.Timeout + time.Minute,
+		Limit:       w.Limit,
+		Concurrency: w.Concurrency,
+		Now:         now,
+		Due: func(now string, limit int64) ([]interface{}, error) {
+			webhooks, err := w.Repo.Due(now, limit)
+			tasks := make([]interface{}, len(webhooks))
+			for i, webhook := range webhooks {
+				tasks[i] = webhook
+			}
+			return tasks, err
+		},
+		Claim: func(task interface{}, nextAt string) (bool, error) {
+			return w.Repo.Claim(task.(*pb.Webhook), nextAt)
+		},
+		Handle: func(task interface{}) error {
+			_, err := w.Deliver(task.(*pb.Webhook))
+			return err
+		},
+	}
+	queue.Scan()
+}
+
+// Deliver 推送一次通知并记录推送结果 推送失败时计算下次推送时间
+func (w *Worker) Deliver(webhook *pb.Webhook) (webhookLog *pb.WebhookLog, err error) {
+	webhookLog = &pb.WebhookLog{
+		WebhookId: webhook.Id,
+		Url:       webhook.Url,
+	}
+	start := time.Now()
+	statusCode, response, sendErr := w.send(webhook)
+	webhookLog.Latency = int64(time.Since(start) / time.Millisecond)
+	webhookLog.StatusCode = int64(statusCode)
+	webhookLog.Response = response
+	if sendErr == nil && (statusCode < 200 || statusCode > 299) {
+		sendErr = fmt.Errorf("商户返回状态码:%d", statusCode)
+	}
+	if sendErr != nil {
+		webhookLog.Error = worker.Truncate(sendErr.Error(), 255)
+	}
+	if err = w.Repo.CreateLog(webhookLog); err != nil {
+		log.Error(webhookLog, err)
+	}
+
+	webhook.Attempts++
+	switch {
+	case sendErr == nil:
+		webhook.Status = StatusSuccess
+	case webhook.Attempts >= w.MaxAttempts:
+		webhook.Status = StatusFailed
+	default:
+		webhook.Status = StatusPending
+		webhook.NextAt = worker.Format(now().Add(worker.Backoff(w.Backoff, w.MaxBackoff, webhook.Attempts)))
+	}
+	if err = w.Repo.Update(webhook); err != nil {
+		return webhookLog, err
+	}
+	return webhookLog, sendErr
+}
+
+// publicTransport 只连接公网地址 通知地址域名解析到本机或内网时拒绝连接 包括重定向后的地址
+func publicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !PublicIP(ip) {
+				return errPrivateUrl
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// send 签名并发送通知 返回商户响应状态码和响应内容
+func (w *Worker) send(webhook *pb.Webhook) (statusCode int, response string, err error) {
+	con := &configPB.Config{Id: webhook.StoreId}
+	if err = w.Config.Get(con); err != nil {
+		return 0, "", fmt.Errorf("查询商户配置失败:%s", err.Error())
+	}
+	if con.NotifySecret == "" { // 没有秘钥无法签名 商户无法校验通知来源
+		return 0, "", errors.New("商户未配置通知秘钥")
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, webhook.Url, strings.NewReader(webhook.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-Pay-Webhook-Id", webhook.Id)
+	req.Header.Set("X-Pay-Event", webhook.Event)
+	req.Header.Set("X-Pay-Timestamp", timestamp)
+	req.Header.Set("X-Pay-Signature", Sign(con.NotifySecret, timestamp, webhook.Payload))
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode, string(body), nil
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	configPB "github.com/lecex/pay/proto/config"
+	orderPB "github.com/lecex/pay/proto/order"
+	pb "github.com/lecex/pay/proto/webhook"
+	"github.com/lecex/pay/service/worker"
+)
+
+// fakeConfig 内存商户配置仓库
+type fakeConfig struct {
+	config *configPB.Config
+}
+
+func (repo *fakeConfig) List(req *configPB.ListQuery) ([]*configPB.Config, error) { return nil, nil }
+func (repo *fakeConfig) Total(req *configPB.ListQuery) (int64, error)             { return 0, nil }
+func (repo *fakeConfig) Create(config *configPB.Config) error                     { return nil }
+func (repo *fakeConfig) Delete(config *configPB.Config) (bool, error)             { return true, nil }
+func (repo *fakeConfig) Update(config *configPB.Config) error                     { return nil }
+func (repo *fakeConfig) Exist(config *configPB.Config) bool                       { return true }
+func (repo *fakeConfig) Get(config *configPB.Config) error {
+	if config.Id != repo.config.Id {
+		return gorm.ErrRecordNotFound
+	}
+	*config = *repo.config
+	return nil
+}
+
+// fakeWebhook 内存通知仓库
+type fakeWebhook struct {
+	mu       sync.Mutex
+	webhooks map[string]*pb.Webhook
+	logs     []*pb.WebhookLog
+}
+
+func (repo *fakeWebhook) List(req *pb.ListQuery) ([]*pb.Webhook, error) { return nil, nil }
+func (repo *fakeWebhook) Total(req *pb.ListQuery) (int64, error)        { return 0, nil }
+
+func (repo *fakeWebhook) Create(webhook *pb.Webhook) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	webhook.Id = fmt.Sprintf("webhook-%d", len(repo.webhooks)+1)
+	w := *webhook
+	repo.webhooks[webhook.Id] = &w
+	return nil
+}
+
+func (repo *fakeWebhook) Update(webhook *pb.Webhook) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	w := *webhook
+	repo.webhooks[webhook.Id] = &w
+	return nil
+}
+
+func (repo *fakeWebhook) Get(webhook *pb.Webhook) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	w, ok := repo.webhooks[webhook.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*webhook = *w
+	return nil
+}
+
+func (repo *fakeWebhook) Due(now string, limit int64) (webhooks []*pb.Webhook, err error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	for _, w := range repo.webhooks {
+		if w.Status == StatusPending && w.NextAt <= now {
+			c := *w
+			webhooks = append(webhooks, &c)
+		}
+	}
+	return webhooks, nil
+}
+
+func (repo *fakeWebhook) Claim(webhook *pb.Webhook, nextAt string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	w := repo.webhooks[webhook.Id]
+	if w.Status != StatusPending || w.Attempts != webhook.Attempts || w.NextAt != webhook.NextAt {
+		return false, nil
+	}
+	w.NextAt, webhook.NextAt = nextAt, nextAt
+	return true, nil
+}
+
+func (repo *fakeWebhook) CreateLog(webhookLog *pb.WebhookLog) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.logs = append(repo.logs, webhookLog)
+	return nil
+}
+
+func (repo *fakeWebhook) Logs(webhook *pb.Webhook) ([]*pb.WebhookLog, error) { return repo.logs, nil }
+
+func TestWorkerDeliver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Payload
+		fails    = 2 // 前两次推送返回错误
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !Verify("secret", r.Header.Get("X-Pay-Timestamp"), string(body), r.Header.Get("X-Pay-Signature")) {
+			t.Errorf("invalid signature: %s", r.Header.Get("X-Pay-Signature"))
+		}
+		if r.Header.Get("X-Pay-Event") != EventSuccess || r.Header.Get("X-Pay-Webhook-Id") == "" {
+			t.Errorf("invalid headers: %v", r.Header)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if fails > 0 {
+			fails--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var p Payload
+		json.Unmarshal(body, &p)
+		received = append(received, p)
+		w.Write([]byte("success"))
+	}))
+	defer server.Close()
+
+	con := &configPB.Config{Id: "store-0", NotifyUrl: server.URL, NotifySecret: "secret"}
+	repo := &fakeWebhook{webhooks: map[string]*pb.Webhook{}}
+	wk := &Worker{
+		Repo:        repo,
+		Config:      &fakeConfig{config: con},
+		Client:      server.Client(),
+		Backoff:     time.Second,
+		MaxBackoff:  time.Second * 3,
+		MaxAttempts: 5,
+		Limit:       10,
+		Concurrency: 2,
+	}
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, worker.CST)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, err := NewWebhook(con, EventSuccess, &orderPB.Order{Id: "order-1", OutTradeNo: "T0001", TotalFee: 100, Status: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Create(w)
+
+	// 第一次推送失败 1 秒后重试, 第二次失败 2 秒后重试
+	for i, backoff := range []time.Duration{time.Second, time.Second * 2} {
+		wk.Scan()
+		repo.Get(w)
+		if w.Status != StatusPending || w.Attempts != int64(i+1) || w.NextAt != worker.Format(clock.Add(backoff)) {
+			t.Fatalf("attempt %d: %+v", i+1, w)
+		}
+		wk.Scan() // 未到重试时间不推送
+		if len(repo.logs) != i+1 {
+			t.Fatalf("attempt %d: delivered before next_at", i+1)
+		}
+		clock = clock.Add(backoff)
+	}
+	wk.Scan()
+	repo.Get(w)
+	if w.Status != StatusSuccess || w.Attempts != 3 || len(repo.logs) != 3 {
+		t.Fatalf("not delivered: %+v", w)
+	}
+	if len(received) != 1 || received[0].Order.OutTradeNo != "T0001" || received[0].Order.TotalFee != 100 {
+		t.Fatalf("received: %+v", received)
+	}
+	if l := repo.logs[0]; l.StatusCode != 500 || l.Error == "" || repo.logs[2].StatusCode != 200 {
+		t.Fatalf("logs: %+v", repo.logs)
+	}
+}
+
+// racingWebhook 所有实例都查询到到期通知后才返回 模拟多个实例同时抢占同一条通知
+type racingWebhook struct {
+	*fakeWebhook
+	due sync.WaitGroup
+}
+
+func (repo *racingWebhook) Due(now string, limit int64) ([]*pb.Webhook, error) {
+	webhooks, err := repo.fakeWebhook.Due(now, limit)
+	repo.due.Done()
+	repo.due.Wait()
+	return webhooks, err
+}
+
+func TestWorkerClaim(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		deliveries int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		deliveries++
+		mu.Unlock()
+		w.Write([]byte("success"))
+	}))
+	defer server.Close()
+	con := &configPB.Config{Id: "store-0", NotifyUrl: server.URL, NotifySecret: "secret"}
+	repo := &racingWebhook{fakeWebhook: &fakeWebhook{webhooks: map[string]*pb.Webhook{}}}
+	w, _ := NewWebhook(con, EventSuccess, &orderPB.Order{Id: "order-1", Status: 1})
+	repo.Create(w)
+
+	workers := 2
+	repo.due.Add(workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wk := &Worker{
+			Repo:        repo,
+			Config:      &fakeConfig{config: con},
+			Client:      server.Client(),
+			Backoff:     time.Second,
+			MaxBackoff:  time.Second,
+			MaxAttempts: 5,
+			Limit:       10,
+			Concurrency: 1,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wk.Scan()
+		}()
+	}
+	wg.Wait()
+	if deliveries != 1 || len(repo.logs) != 1 {
+		t.Fatalf("want 1 delivery, got %d", deliveries)
+	}
+}
+
+func TestWorkerMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	con := &configPB.Config{Id: "store-0", NotifyUrl: server.URL, NotifySecret: "secret"}
+	repo := &fakeWebhook{webhooks: map[string]*pb.Webhook{}}
+	wk := &Worker{
+		Repo:        repo,
+		Config:      &fakeConfig{config: con},
+		Client:      server.Client(),
+		Backoff:     time.Second,
+		MaxBackoff:  time.Second,
+		MaxAttempts: 2,
+	}
+	w, _ := NewWebhook(con, EventClosed, &orderPB.Order{Id: "order-1", Status: -1})
+	repo.Create(w)
+	for i := 0; i < 2; i++ {
+		if _, err := wk.Deliver(w); err == nil {
+			t.Fatal("want delivery error")
+		}
+	}
+	if w.Status != StatusFailed || w.Attempts != 2 {
+		t.Fatalf("want failed: %+v", w)
+	}
+}
+
+func TestNewWebhookWithoutUrl(t *testing.T) {
+	w, err := NewWebhook(&configPB.Config{Id: "store-0"}, EventSuccess, &orderPB.Order{})
+	if w != nil || err != nil {
+		t.Fatalf("want nil webhook: %+v %v", w, err)
+	}
+	// 没有秘钥不生成通知 避免发送无法校验来源的通知
+	w, err = NewWebhook(&configPB.Config{Id: "store-0", NotifyUrl: "https://shop.example.com/notify"}, EventSuccess, &orderPB.Order{})
+	if w != nil || err == nil {
+		t.Fatalf("want secret error: %+v %v", w, err)
+	}
+}
+
+func TestPublicTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address connected")
+	}))
+	defer server.Close()
+	client := &http.Client{Timeout: time.Second, Transport: publicTransport()}
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatal("want private address error")
+	}
+	for _, ip := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		if !PublicIP(net.ParseIP(ip)) {
+			t.Errorf("%s should be public", ip)
+		}
+	}
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.20.0.1", "169.254.169.254", "::1", "fd00::1", "0.0.0.0"} {
+		if PublicIP(net.ParseIP(ip)) {
+			t.Errorf("%s should be private", ip)
+		}
+	}
+}
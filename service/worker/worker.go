@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v2/util/log"
+)
+
+// TimeLayout 数据库时间格式
+const TimeLayout = "2006-01-02 15:04:05"
+
+// CST 北京时间 与数据库时间一致
+var CST = time.FixedZone("CST", 8*3600)
+
+// Format 转换为数据库时间
+func Format(t time.Time) string {
+	return t.In(CST).Format(TimeLayout)
+}
+
+// Backoff 第 attempts 次失败后的重试间隔 首次为 d 之后每次翻倍 不超过 max
+func Backoff(d time.Duration, max time.Duration, attempts int64) time.Duration {
+	for i := int64(1); i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// Truncate 按字符截取 避免超出数据表字段长度
+func Truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// Run 每隔 interval 调用一次 fn stop 关闭后退出
+func Run(interval time.Duration, stop <-chan struct{}, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+// Queue 到期任务队列 Due 查询到期任务 Claim 抢占成功后 Handle 处理
+// Claim 以 Due 查询到的下次处理时间为条件 多个实例同时查询到同一任务时只有一个实例处理
+type Queue struct {
+	Interval    time.Duration // 扫描到期任务间隔
+	Lease       time.Duration // 抢占时长 抢占期间其它实例不会重复处理 处理中断时到期后重新处理
+	Limit       int64         // 每次扫描最多处理数量
+	Concurrency int           // 同时处理数量 小于 1 时逐个处理
+	Now         func() time.Time
+
+	Due    func(now string, limit int64) ([]interface{}, error)
+	Claim  func(task interface{}, nextAt string) (bool, error)
+	Handle func(task interface{}) error
+}
+
+// Run 定时处理到期任务 stop 关闭后退出
+func (q *Queue) Run(stop <-chan struct{}) {
+	Run(q.Interval, stop, q.Scan)
+}
+
+// Scan 处理一批到期任务
+func (q *Queue) Scan() {
+	tasks, err := q.Due(Format(q.now()), q.Limit)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	concurrency := q.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		sem <- struct{}{}
+		ok, err := q.Claim(task, Format(q.now().Add(q.Lease)))
+		if err != nil || !ok {
+			if err != nil {
+				log.Error(task, err)
+			}
+			<-sem
+			continue
+		}
+		wg.Add(1)
+		go func(task interface{}) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := q.Handle(task); err != nil {
+				log.Error(task, err)
+			}
+		}(task)
+	}
+	wg.Wait()
+}
+
+// now 当前时间 未设置 Now 时使用系统时间
+func (q *Queue) now() time.Time {
+	if q.Now != nil {
+		return q.Now()
+	}
+	return time.Now()
+}
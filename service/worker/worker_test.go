@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int64]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 10: 5 * time.Second} {
+		if d := Backoff(time.Second, 5*time.Second, attempts); d != want {
+			t.Errorf("Backoff(%d) = %s, want %s", attempts, d, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if s := Truncate("分账失败", 2); s != "分账" {
+		t.Fatalf("Truncate = %s", s)
+	}
+	if s := Truncate("ok", 5); s != "ok" {
+		t.Fatalf("Truncate = %s", s)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if s := Format(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)); s != "2020-01-01 08:00:00" {
+		t.Fatalf("Format = %s", s)
+	}
+}
+
+// task 内存任务 Claim 以查询到的下次处理时间为条件
+type task struct {
+	mu      sync.Mutex
+	nextAt  string
+	handled int
+}
+
+func TestQueueClaim(t *testing.T) {
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, CST)
+	tk := &task{nextAt: Format(clock)}
+	// 所有实例都查询到到期任务后才抢占 模拟多个实例同时处理同一任务
+	var due sync.WaitGroup
+	queues := 2
+	due.Add(queues)
+	var wg sync.WaitGroup
+	for i := 0; i < queues; i++ {
+		q := &Queue{
+			Lease: time.Minute,
+			Limit: 10,
+			Now:   func() time.Time { return clock },
+			Due: func(now string, limit int64) ([]interface{}, error) {
+				tk.mu.Lock()
+				dueAt := tk.nextAt
+				tk.mu.Unlock()
+				due.Done()
+				due.Wait()
+				if dueAt > now {
+					return nil, nil
+				}
+				return []interface{}{dueAt}, nil
+			},
+			Claim: func(v interface{}, nextAt string) (bool, error) {
+				tk.mu.Lock()
+				defer tk.mu.Unlock()
+				if tk.nextAt != v.(string) {
+					return false, nil
+				}
+				tk.nextAt = nextAt
+				return true, nil
+			},
+			Handle: func(v interface{}) error {
+				tk.mu.Lock()
+				defer tk.mu.Unlock()
+				tk.handled++
+				return nil
+			},
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.Scan()
+		}()
+	}
+	wg.Wait()
+	if tk.handled != 1 || tk.nextAt != Format(clock.Add(time.Minute)) {
+		t.Fatalf("handled = %d, next_at = %s", tk.handled, tk.nextAt)
+	}
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"net/url"
 	"strings"
 
 	pb "github.com/lecex/pay/proto/notify"
@@ -16,3 +17,21 @@ func Get(req *pb.Request, key string) (value string) {
 	value = strings.Join(v.Values, " ")
 	return value
 }
+
+// Form 获取表单请求全部参数 未解析表单时从原始请求体解析
+func Form(req *pb.Request) map[string]string {
+	params := map[string]string{}
+	for k, v := range req.Post {
+		params[k] = strings.Join(v.Values, " ")
+	}
+	if len(params) == 0 && req.Body != "" {
+		values, err := url.ParseQuery(req.Body)
+		if err != nil {
+			return params
+		}
+		for k := range values {
+			params[k] = values.Get(k)
+		}
+	}
+	return params
+}
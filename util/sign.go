@@ -0,0 +1,148 @@
+package util
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// SignContent 按参数名升序拼接待签名字符串 key1=value1&key2=value2 空值不参与签名
+func SignContent(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	buf := strings.Builder{}
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte('&')
+		}
+		buf.WriteString(k)
+		buf.WriteByte('=')
+		buf.WriteString(params[k])
+	}
+	return buf.String()
+}
+
+// NonceStr 32 位随机字符串
+func NonceStr() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// pemBlock 获取秘钥内容 兼容带 PEM 头尾和纯 base64 两种格式
+func pemBlock(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		return block.Bytes, nil
+	}
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key), ""))
+}
+
+// ParsePrivateKey 解析 RSA 私钥 支持 PKCS1 和 PKCS8
+func ParsePrivateKey(key string) (*rsa.PrivateKey, error) {
+	der, err := pemBlock(key)
+	if err != nil {
+		return nil, errors.New("私钥格式错误:" + err.Error())
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.New("私钥格式错误:" + err.Error())
+	}
+	rsaKey, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("私钥不是 RSA 秘钥")
+	}
+	return rsaKey, nil
+}
+
+// ParsePublicKey 解析 RSA 公钥 支持 PKIX、PKCS1 和 X509 证书
+func ParsePublicKey(key string) (*rsa.PublicKey, error) {
+	der, err := pemBlock(key)
+	if err != nil {
+		return nil, errors.New("公钥格式错误:" + err.Error())
+	}
+	var pub interface{}
+	if pub, err = x509.ParsePKIXPublicKey(der); err != nil {
+		if k, e := x509.ParsePKCS1PublicKey(der); e == nil {
+			pub, err = k, nil
+		} else if cert, e := x509.ParseCertificate(der); e == nil {
+			pub, err = cert.PublicKey, nil
+		}
+	}
+	if err != nil {
+		return nil, errors.New("公钥格式错误:" + err.Error())
+	}
+	rsaKey, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("公钥不是 RSA 秘钥")
+	}
+	return rsaKey, nil
+}
+
+// hash 根据签名方式计算摘要 RSA2 为 SHA256WithRSA 其它为 SHA1WithRSA
+func hash(content, signType string) (crypto.Hash, []byte) {
+	if strings.ToUpper(signType) == "RSA2" {
+		h := sha256.Sum256([]byte(content))
+		return crypto.SHA256, h[:]
+	}
+	h := sha1.Sum([]byte(content))
+	return crypto.SHA1, h[:]
+}
+
+// RsaSign RSA 签名 返回 base64 编码签名
+func RsaSign(privateKey, content, signType string) (string, error) {
+	key, err := ParsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return RsaSignKey(key, content, signType)
+}
+
+// RsaSignKey 使用已解析的私钥签名 返回 base64 编码签名
+func RsaSignKey(key *rsa.PrivateKey, content, signType string) (string, error) {
+	h, hashed := hash(content, signType)
+	sign, err := rsa.SignPKCS1v15(rand.Reader, key, h, hashed)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sign), nil
+}
+
+// RsaVerify RSA 验签 sign 为 base64 编码签名
+func RsaVerify(publicKey, content, sign, signType string) error {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	return RsaVerifyKey(key, content, sign, signType)
+}
+
+// RsaVerifyKey 使用已解析的公钥验签 sign 为 base64 编码签名
+func RsaVerifyKey(key *rsa.PublicKey, content, sign, signType string) error {
+	s, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		return errors.New("签名格式错误:" + err.Error())
+	}
+	h, hashed := hash(content, signType)
+	if err = rsa.VerifyPKCS1v15(key, h, hashed, s); err != nil {
+		return errors.New("验签失败")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"testing"
+)
+
+// testKey base64 编码的 PKCS8 私钥和 PKIX 公钥
+func testKey(tb testing.TB) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return base64.StdEncoding.EncodeToString(der), base64.StdEncoding.EncodeToString(pub)
+}
+
+func TestRsaSign(t *testing.T) {
+	privateKey, publicKey := testKey(t)
+	sign, err := RsaSign(privateKey, "a=1&b=2", "RSA2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = RsaVerify(publicKey, "a=1&b=2", sign, "RSA2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = RsaVerify(publicKey, "a=1&b=3", sign, "RSA2"); err == nil {
+		t.Fatal("want verify error")
+	}
+	if _, err = ParsePublicKey(privateKey); err == nil {
+		t.Fatal("want invalid public key error")
+	}
+}
+
+func BenchmarkRsaSign(b *testing.B) {
+	privateKey, _ := testKey(b)
+	b.Run("parse", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := RsaSign(privateKey, "a=1&b=2", "RSA2"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	key, _ := ParsePrivateKey(privateKey)
+	b.Run("parsed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := RsaSignKey(key, "a=1&b=2", "RSA2"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}